@@ -0,0 +1,295 @@
+// Package airspace assembles controlled and restrictive airspace records into
+// airspaces with closed boundary polygons, for drawing airspace on a map and
+// finding the airspaces that contain a point.
+//
+// Each airspace is a sequence of boundary points. The boundary follows a line
+// or an arc from each point to the next, and returns from the last point to
+// the first. Arcs and circles are approximated by points along them.
+package airspace
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+	"github.com/wallaceicy06/enhance-faa-cifp/geodesy"
+)
+
+// metersPerNauticalMile is the length of a nautical mile in meters.
+const metersPerNauticalMile = 1852.0
+
+// Kind is whether an airspace is controlled or restrictive.
+type Kind int
+
+const (
+	Controlled Kind = iota
+	Restrictive
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case Controlled:
+		return "Controlled"
+	case Restrictive:
+		return "Restrictive"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Airspace is an assembled controlled or restrictive airspace. The ICAO code,
+// type, identifier, and multiple code together identify it.
+type Airspace struct {
+	Kind     Kind
+	ICAOCode string
+	// Type is the controlled airspace type, such as "T" for class B airspace,
+	// or the restrictive airspace type, such as "R" for a restricted area.
+	// See 5.213 Controlled Airspace Type, 5.128 Restrictive Airspace Type
+	Type string
+	// ID is the airspace center of a controlled airspace, or the designation
+	// of a restrictive airspace.
+	ID string
+	// Classification is the class of a controlled airspace, from "A" to "G".
+	// It is empty for restrictive airspace.
+	Classification string
+	MultipleCode   string
+	// Name, Lower, and Upper are taken from the first boundary point that has
+	// them.
+	Name         string
+	Lower, Upper arinc.AirspaceLimit
+	// Boundary holds the boundary points in sequence order.
+	Boundary []*arinc.AirspaceBoundaryPoint
+	// Polygon is the closed outline of the boundary, whose first and last
+	// points are the same.
+	Polygon []*geo.Point
+}
+
+// Contains returns true if the point is inside the outline of the airspace.
+// The limits of the airspace are not considered. The outline is treated as a
+// planar polygon in latitude and longitude, so airspaces that cross the
+// antimeridian are not supported.
+func (a *Airspace) Contains(pt *geo.Point) bool {
+	return geo.NewPolygon(a.Polygon).Contains(pt)
+}
+
+type airspaceKey struct {
+	kind         Kind
+	icaoCode     string
+	airspaceType string
+	id           string
+	multipleCode string
+}
+
+// Assembler groups airspace records into airspaces. Records may be added in
+// any order. An Assembler is not safe for concurrent use.
+type Assembler struct {
+	arcStep   float64
+	airspaces map[airspaceKey]*Airspace
+}
+
+// NewAssembler returns a new, empty Assembler. Arcs and circles are
+// approximated by points that are at most arcStep degrees apart as seen from
+// the arc origin.
+func NewAssembler(arcStep float64) *Assembler {
+	return &Assembler{arcStep: arcStep, airspaces: make(map[airspaceKey]*Airspace)}
+}
+
+// AddControlled adds the boundary point in the controlled airspace record to
+// its airspace. If a field of the record is malformed, an error is returned.
+func (a *Assembler) AddControlled(rec *arinc.ControlledAirspaceRecord) error {
+	pt, err := rec.BoundaryPoint()
+	if err != nil {
+		return fmt.Errorf("could not decode controlled airspace %q: %v", rec.AirspaceCenter, err)
+	}
+	k := airspaceKey{Controlled, rec.ICAOCode, rec.AirspaceType, rec.AirspaceCenter, rec.MultipleCode}
+	a.add(k, rec.AirspaceClassification, pt)
+	return nil
+}
+
+// AddRestrictive adds the boundary point in the restrictive airspace record to
+// its airspace. If a field of the record is malformed, an error is returned.
+func (a *Assembler) AddRestrictive(rec *arinc.RestrictiveAirspaceRecord) error {
+	pt, err := rec.BoundaryPoint()
+	if err != nil {
+		return fmt.Errorf("could not decode restrictive airspace %q: %v", rec.RestrictiveDesignation, err)
+	}
+	k := airspaceKey{Restrictive, rec.ICAOCode, rec.RestrictiveType, rec.RestrictiveDesignation, rec.MultipleCode}
+	a.add(k, "", pt)
+	return nil
+}
+
+func (a *Assembler) add(k airspaceKey, classification string, pt *arinc.AirspaceBoundaryPoint) {
+	as, ok := a.airspaces[k]
+	if !ok {
+		as = &Airspace{
+			Kind:           k.kind,
+			ICAOCode:       k.icaoCode,
+			Type:           k.airspaceType,
+			ID:             k.id,
+			Classification: classification,
+			MultipleCode:   k.multipleCode,
+		}
+		a.airspaces[k] = as
+	}
+	as.Boundary = append(as.Boundary, pt)
+	as.Polygon = nil
+}
+
+// Airspaces returns all of the airspaces with their polygons, sorted by kind,
+// identifier, type, ICAO code, and multiple code. If the boundary of an
+// airspace cannot be assembled, an error is returned.
+func (a *Assembler) Airspaces() ([]*Airspace, error) {
+	if a.arcStep <= 0 {
+		return nil, fmt.Errorf("invalid arc step %v", a.arcStep)
+	}
+	airspaces := make([]*Airspace, 0, len(a.airspaces))
+	for _, as := range a.airspaces {
+		if as.Polygon == nil {
+			if err := as.assemble(a.arcStep); err != nil {
+				return nil, err
+			}
+		}
+		airspaces = append(airspaces, as)
+	}
+	sort.Slice(airspaces, func(i, j int) bool {
+		x, y := airspaces[i], airspaces[j]
+		if x.Kind != y.Kind {
+			return x.Kind < y.Kind
+		}
+		if x.ID != y.ID {
+			return x.ID < y.ID
+		}
+		if x.Type != y.Type {
+			return x.Type < y.Type
+		}
+		if x.ICAOCode != y.ICAOCode {
+			return x.ICAOCode < y.ICAOCode
+		}
+		return x.MultipleCode < y.MultipleCode
+	})
+	return airspaces, nil
+}
+
+// assemble sorts the boundary, fills in the name and limits, and builds the
+// polygon.
+func (as *Airspace) assemble(arcStep float64) error {
+	sort.SliceStable(as.Boundary, func(i, j int) bool { return as.Boundary[i].Sequence < as.Boundary[j].Sequence })
+	as.Name = ""
+	as.Lower, as.Upper = arinc.AirspaceLimit{}, arinc.AirspaceLimit{}
+	var haveLower, haveUpper bool
+	for _, pt := range as.Boundary {
+		if as.Name == "" {
+			as.Name = pt.Name
+		}
+		if !haveLower && pt.Lower != nil {
+			as.Lower, haveLower = *pt.Lower, true
+		}
+		if !haveUpper && pt.Upper != nil {
+			as.Upper, haveUpper = *pt.Upper, true
+		}
+	}
+	polygon, err := Polygon(as.Boundary, arcStep)
+	if err != nil {
+		return fmt.Errorf("could not assemble %s airspace %q: %v", as.Kind, as.ID, err)
+	}
+	as.Polygon = polygon
+	return nil
+}
+
+// Polygon returns the closed outline of the boundary, whose points must be in
+// sequence order. The first and last points of the outline are the same.
+// Arcs and circles are approximated by points that are at most arcStep
+// degrees apart as seen from the arc origin. Great circle and rhumb line
+// segments are straight edges between their points, which is close enough
+// for the short segments of airspace boundaries. The boundary is closed even
+// if its last point is not marked as the end.
+func Polygon(boundary []*arinc.AirspaceBoundaryPoint, arcStep float64) ([]*geo.Point, error) {
+	if len(boundary) == 0 {
+		return nil, fmt.Errorf("boundary has no points")
+	}
+	if arcStep <= 0 {
+		return nil, fmt.Errorf("invalid arc step %v", arcStep)
+	}
+	if boundary[0].Via == arinc.BoundaryCircle {
+		if len(boundary) != 1 {
+			return nil, fmt.Errorf("circle at sequence %d is not the only point of its boundary", boundary[0].Sequence)
+		}
+		return circle(boundary[0], arcStep)
+	}
+	var polygon []*geo.Point
+	for i, pt := range boundary {
+		switch {
+		case pt.Via == arinc.BoundaryCircle:
+			return nil, fmt.Errorf("circle at sequence %d is not the only point of its boundary", pt.Sequence)
+		case pt.End && i != len(boundary)-1:
+			return nil, fmt.Errorf("boundary continues after its end at sequence %d", pt.Sequence)
+		}
+		polygon = append(polygon, geo.NewPoint(pt.Lat, pt.Lon))
+		if !pt.Via.IsArc() {
+			continue
+		}
+		next := boundary[0]
+		if i+1 < len(boundary) {
+			next = boundary[i+1]
+		}
+		points, err := arc(pt, next, arcStep)
+		if err != nil {
+			return nil, fmt.Errorf("arc at sequence %d: %v", pt.Sequence, err)
+		}
+		polygon = append(polygon, points...)
+	}
+	return append(polygon, polygon[0]), nil
+}
+
+// circle returns the closed outline of a circle boundary.
+func circle(pt *arinc.AirspaceBoundaryPoint, arcStep float64) ([]*geo.Point, error) {
+	n := int(math.Ceil(360 / arcStep))
+	var points []*geo.Point
+	for k := 0; k < n; k++ {
+		lat, lon, err := geodesy.Destination(pt.ArcOriginLat, pt.ArcOriginLon, 360*float64(k)/float64(n), pt.ArcDistance*metersPerNauticalMile)
+		if err != nil {
+			return nil, fmt.Errorf("circle at sequence %d: %v", pt.Sequence, err)
+		}
+		points = append(points, geo.NewPoint(lat, lon))
+	}
+	return append(points, points[0]), nil
+}
+
+// arc returns the points strictly between from and to on the arc that starts
+// at from. The distance from the arc origin changes evenly along the arc
+// between the distances of the two points, which differ slightly from the
+// published arc distance because of rounding, so that the arc meets both
+// points exactly.
+func arc(from, to *arinc.AirspaceBoundaryPoint, arcStep float64) ([]*geo.Point, error) {
+	r0, start, _, err := geodesy.WGS84.Inverse(from.ArcOriginLat, from.ArcOriginLon, from.Lat, from.Lon)
+	if err != nil {
+		return nil, err
+	}
+	r1, end, _, err := geodesy.WGS84.Inverse(from.ArcOriginLat, from.ArcOriginLon, to.Lat, to.Lon)
+	if err != nil {
+		return nil, err
+	}
+	sweep := math.Mod(end-start+360, 360)
+	if from.Via == arinc.BoundaryCounterClockwiseArc {
+		sweep -= 360
+	}
+	if sweep == -360 {
+		sweep = 0
+	}
+	// The tolerance keeps rounding in the azimuths from adding a point to
+	// arcs whose sweep is a multiple of the step.
+	n := int(math.Ceil(math.Abs(sweep)/arcStep - 1e-9))
+	var points []*geo.Point
+	for k := 1; k < n; k++ {
+		f := float64(k) / float64(n)
+		azi := math.Mod(start+sweep*f+360, 360)
+		lat, lon, err := geodesy.Destination(from.ArcOriginLat, from.ArcOriginLon, azi, r0+(r1-r0)*f)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, geo.NewPoint(lat, lon))
+	}
+	return points, nil
+}
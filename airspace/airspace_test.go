@@ -0,0 +1,244 @@
+package airspace
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+	"github.com/wallaceicy06/enhance-faa-cifp/geodesy"
+)
+
+// testRecords are a class C airspace whose boundary starts with a clockwise
+// arc around a point, and a restricted area that is a circle. The records are
+// out of order.
+var testRecords = []string{
+	"SUSAUCK2AKOAK PAC  A00301BC   G N37300000W122120000                                                                        123452002",
+	"SUSAURK2R2531      A00101BC   CE                   N37000000W1210000000050       GND  MFL180MR-2531 TEST                   123452002",
+	"SUSAUCK2AKOAK PAC  A00101BC   R N37500000W122120000N37400000W12212000001000000   GND  M04000MOAKLAND CLASS C               123452002",
+	"SUSAUCK2AKOAK PAC  A00401BC   GEN37400000W122270000                                                                        123452002",
+	"SUSAUCK2AKOAK PAC  A00201BC   G N37400000W121592150                                                                        123452002",
+}
+
+// arcOrigin is the center of the arc of the class C airspace.
+var arcOrigin = geo.NewPoint(37+40.0/60, -(122 + 12.0/60))
+
+func testAssembler(t *testing.T) *Assembler {
+	t.Helper()
+	a := NewAssembler(5)
+	for _, line := range testRecords {
+		rec, err := arinc.Parse([]byte(line))
+		if err != nil {
+			t.Fatalf("Parse() = %v want <nil>", err)
+		}
+		switch rec := rec.(type) {
+		case *arinc.ControlledAirspaceRecord:
+			err = a.AddControlled(rec)
+		case *arinc.RestrictiveAirspaceRecord:
+			err = a.AddRestrictive(rec)
+		default:
+			t.Fatalf("Parse() = %T want an airspace record", rec)
+		}
+		if err != nil {
+			t.Fatalf("adding %q = %v want <nil>", line, err)
+		}
+	}
+	return a
+}
+
+// distance returns the distance in nautical miles between the points.
+func distance(t *testing.T, from, to *geo.Point) float64 {
+	t.Helper()
+	d, _, _, err := geodesy.WGS84.Inverse(from.Lat(), from.Lng(), to.Lat(), to.Lng())
+	if err != nil {
+		t.Fatalf("Inverse() = _, _, _, %v want _, _, _, <nil>", err)
+	}
+	return d / metersPerNauticalMile
+}
+
+// offset returns the point at the given true bearing and distance in nautical
+// miles from the point.
+func offset(t *testing.T, from *geo.Point, bearing, nm float64) *geo.Point {
+	t.Helper()
+	lat, lon, err := geodesy.Destination(from.Lat(), from.Lng(), bearing, nm*metersPerNauticalMile)
+	if err != nil {
+		t.Fatalf("Destination() = _, _, %v want _, _, <nil>", err)
+	}
+	return geo.NewPoint(lat, lon)
+}
+
+func TestAirspaces(t *testing.T) {
+	got, err := testAssembler(t).Airspaces()
+	if err != nil {
+		t.Fatalf("Airspaces() = _, %v want _, <nil>", err)
+	}
+	type summary struct {
+		Kind                 Kind
+		ICAOCode, Type, ID   string
+		Classification, Name string
+		Lower, Upper         string
+		Sequences            []int
+	}
+	var gotSummaries []summary
+	for _, a := range got {
+		s := summary{
+			Kind:           a.Kind,
+			ICAOCode:       a.ICAOCode,
+			Type:           a.Type,
+			ID:             a.ID,
+			Classification: a.Classification,
+			Name:           a.Name,
+			Lower:          a.Lower.String(),
+			Upper:          a.Upper.String(),
+		}
+		for _, pt := range a.Boundary {
+			s.Sequences = append(s.Sequences, pt.Sequence)
+		}
+		gotSummaries = append(gotSummaries, s)
+	}
+	want := []summary{
+		{Controlled, "K2", "A", "KOAK", "C", "OAKLAND CLASS C", "GND", "4000", []int{10, 20, 30, 40}},
+		{Restrictive, "K2", "R", "2531", "", "R-2531 TEST", "GND", "FL180", []int{10}},
+	}
+	if diff := cmp.Diff(want, gotSummaries); diff != "" {
+		t.Errorf("Airspaces() had diffs (-want +got): %s", diff)
+	}
+
+	for _, a := range got {
+		p := a.Polygon
+		if len(p) < 4 || p[0] != p[len(p)-1] {
+			t.Errorf("Airspaces() polygon of %q = %d points want a closed polygon", a.ID, len(p))
+		}
+	}
+
+	// The 90 degree arc from north to east of the origin is approximated by
+	// points every 5 degrees, followed by the three points of the straight
+	// edges and the closing point.
+	classC := got[0].Polygon
+	if len(classC) != 18+3+1 {
+		t.Errorf("Airspaces() polygon of %q = %d points want %d", got[0].ID, len(classC), 18+3+1)
+	}
+	for i, pt := range classC[:19] {
+		if d := distance(t, arcOrigin, pt); math.Abs(d-10) > 0.05 {
+			t.Errorf("Airspaces() arc point %d is %f NM from the origin want 10", i, d)
+		}
+	}
+
+	// The circle is approximated by 72 points and the closing point.
+	circle := got[1].Polygon
+	if len(circle) != 73 {
+		t.Errorf("Airspaces() polygon of %q = %d points want 73", got[1].ID, len(circle))
+	}
+	for i, pt := range circle {
+		if d := distance(t, geo.NewPoint(37, -121), pt); math.Abs(d-5) > 0.0001 {
+			t.Errorf("Airspaces() circle point %d is %f NM from the center want 5", i, d)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	airspaces, err := testAssembler(t).Airspaces()
+	if err != nil {
+		t.Fatalf("Airspaces() = _, %v want _, <nil>", err)
+	}
+	classC, restricted := airspaces[0], airspaces[1]
+	for _, tt := range []struct {
+		name     string
+		airspace *Airspace
+		pt       *geo.Point
+		want     bool
+	}{
+		{"ArcOrigin", classC, arcOrigin, true},
+		{"InsideArc", classC, offset(t, arcOrigin, 45, 9.5), true},
+		{"OutsideArc", classC, offset(t, arcOrigin, 45, 10.5), false},
+		{"InsideStraightEdge", classC, offset(t, arcOrigin, 225, 6.5), true},
+		{"OutsideStraightEdge", classC, offset(t, arcOrigin, 225, 8.5), false},
+		{"InsideCircle", restricted, offset(t, geo.NewPoint(37, -121), 100, 4.9), true},
+		{"OutsideCircle", restricted, offset(t, geo.NewPoint(37, -121), 100, 5.1), false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.airspace.Contains(tt.pt); got != tt.want {
+				t.Errorf("Contains(%v) = %t want %t", tt.pt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolygonArcDirection(t *testing.T) {
+	north := offset(t, arcOrigin, 0, 10)
+	east := offset(t, arcOrigin, 90, 10)
+	for _, tt := range []struct {
+		name       string
+		via        arinc.BoundaryVia
+		wantPoints int
+		wantInside *geo.Point
+	}{
+		// From east to north is 90 degrees counterclockwise, and 270 degrees
+		// clockwise.
+		{"CounterClockwise", arinc.BoundaryCounterClockwiseArc, 18 + 2, offset(t, arcOrigin, 45, 9)},
+		{"Clockwise", arinc.BoundaryClockwiseArc, 54 + 2, offset(t, arcOrigin, 225, 9)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			boundary := []*arinc.AirspaceBoundaryPoint{
+				{
+					Sequence:     10,
+					Via:          tt.via,
+					Lat:          east.Lat(),
+					Lon:          east.Lng(),
+					ArcOriginLat: arcOrigin.Lat(),
+					ArcOriginLon: arcOrigin.Lng(),
+					ArcDistance:  10,
+				},
+				{Sequence: 20, Via: arinc.BoundaryGreatCircle, End: true, Lat: north.Lat(), Lon: north.Lng()},
+			}
+			got, err := Polygon(boundary, 5)
+			if err != nil {
+				t.Fatalf("Polygon() = _, %v want _, <nil>", err)
+			}
+			if len(got) != tt.wantPoints {
+				t.Errorf("Polygon() = %d points want %d", len(got), tt.wantPoints)
+			}
+			if !geo.NewPolygon(got).Contains(tt.wantInside) {
+				t.Errorf("Polygon() does not contain %v", tt.wantInside)
+			}
+		})
+	}
+}
+
+func TestPolygonErrors(t *testing.T) {
+	circle := &arinc.AirspaceBoundaryPoint{Sequence: 10, Via: arinc.BoundaryCircle, End: true, ArcOriginLat: 37, ArcOriginLon: -121, ArcDistance: 5}
+	point := func(seq int, end bool) *arinc.AirspaceBoundaryPoint {
+		return &arinc.AirspaceBoundaryPoint{Sequence: seq, Via: arinc.BoundaryGreatCircle, End: end, Lat: 37, Lon: -121 + float64(seq)/100}
+	}
+	for _, tt := range []struct {
+		name     string
+		boundary []*arinc.AirspaceBoundaryPoint
+		arcStep  float64
+	}{
+		{"Empty", nil, 5},
+		{"ArcStep", []*arinc.AirspaceBoundaryPoint{circle}, 0},
+		{"CircleFirst", []*arinc.AirspaceBoundaryPoint{circle, point(20, true)}, 5},
+		{"CircleLater", []*arinc.AirspaceBoundaryPoint{point(10, false), circle}, 5},
+		{"AfterEnd", []*arinc.AirspaceBoundaryPoint{point(10, false), point(20, true), point(30, true)}, 5},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := Polygon(tt.boundary, tt.arcStep); err == nil {
+				t.Errorf("Polygon() = %v, <nil> want _, <non-nil>", got)
+			}
+		})
+	}
+}
+
+func TestAddErrors(t *testing.T) {
+	a := NewAssembler(5)
+	if err := a.AddControlled(&arinc.ControlledAirspaceRecord{SequenceNumber: "0010", BoundaryVia: "X"}); err == nil {
+		t.Errorf("AddControlled() = <nil> want <non-nil>")
+	}
+	if err := a.AddRestrictive(&arinc.RestrictiveAirspaceRecord{SequenceNumber: "00A0", BoundaryVia: "G"}); err == nil {
+		t.Errorf("AddRestrictive() = <nil> want <non-nil>")
+	}
+	if _, err := NewAssembler(0).Airspaces(); err == nil {
+		t.Errorf("Airspaces() with no arc step = _, <nil> want _, <non-nil>")
+	}
+}
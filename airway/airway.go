@@ -0,0 +1,341 @@
+// Package airway assembles enroute airway records into a directed graph of
+// fixes, and expands airways between two fixes for flight planning.
+//
+// Each airway is a sequence of fixes. Consecutive fixes are connected by a
+// segment in each direction that the airway may be flown, unless the first fix
+// ends a continuous section of the airway.
+package airway
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+)
+
+// Level is the altitude structure that an airway segment belongs to.
+// See 5.19 Level
+type Level string
+
+const (
+	LevelAll  Level = "B"
+	LevelHigh Level = "H"
+	LevelLow  Level = "L"
+)
+
+// Direction is the direction that an airway segment may be flown in,
+// relative to the sequence of the fixes in the airway.
+// See 5.115 Direction Restriction
+type Direction string
+
+const (
+	DirectionAny      Direction = ""
+	DirectionForward  Direction = "F"
+	DirectionBackward Direction = "B"
+)
+
+// unknownAltitudes are the minimum altitude values that mean that the
+// altitude is unknown or not established.
+var unknownAltitudes = map[string]bool{
+	"":      true,
+	"UNKNN": true,
+	"NESTB": true,
+}
+
+// Fix is a fix on an airway. Fix identifiers are only unique within an ICAO
+// region and section, so all of the fields identify the fix.
+type Fix struct {
+	ID             string
+	ICAOCode       string
+	SectionCode    string
+	SubsectionCode string
+}
+
+// String returns the identifier of the fix.
+func (f Fix) String() string {
+	return f.ID
+}
+
+// Airway identifies an airway. Route identifiers are only unique within a
+// customer area and ICAO region, so all of the fields identify the airway.
+type Airway struct {
+	CustomerArea string
+	// ICAOCode is the ICAO code of the fixes on the airway. The numbered
+	// areas of a country, such as K1 to K7 in the United States, are combined
+	// into the letter of the country (K), since airways cross them.
+	ICAOCode string
+	RouteID  string
+}
+
+// String returns the route identifier of the airway.
+func (a Airway) String() string {
+	return a.RouteID
+}
+
+// airwayICAOCode returns the ICAO code of the airway that a fix with the given
+// ICAO code is on.
+func airwayICAOCode(fixICAOCode string) string {
+	if len(fixICAOCode) == 2 && fixICAOCode[1] >= '0' && fixICAOCode[1] <= '9' {
+		return fixICAOCode[:1]
+	}
+	return fixICAOCode
+}
+
+// Segment is a directed leg of an airway between two consecutive fixes.
+type Segment struct {
+	Airway    Airway
+	From, To  Fix
+	Level     Level
+	Direction Direction
+	// MinimumAltitude is the minimum enroute altitude (MEA) in the direction
+	// of the segment. It is zero if it is unknown.
+	MinimumAltitude arinc.Altitude
+	// MaximumAltitude is the maximum authorized altitude. It is zero if there
+	// is none.
+	MaximumAltitude arinc.Altitude
+	// Course is the course from From to To, which is magnetic unless the
+	// published course is true. For segments that are flown against the
+	// sequence of the airway, it is the published inbound course of the fix
+	// that the segment ends at. It is zero if there is no published course.
+	Course arinc.Bearing
+	// Distance is the length of the segment in nautical miles.
+	Distance float64
+}
+
+// airwayFix is a fix in an airway along with the attributes of the segment to
+// the next fix.
+type airwayFix struct {
+	seq  int
+	fix  Fix
+	end  bool
+	next Segment
+	// backwardMinimumAltitude is the minimum enroute altitude of the segment
+	// from the next fix back to this one.
+	backwardMinimumAltitude arinc.Altitude
+	// inboundCourse is the published course of the segment from the next fix
+	// back to this one.
+	inboundCourse arinc.Bearing
+}
+
+// airway is an assembled airway. forward[i] is the segment from fixes[i] to
+// fixes[i+1], and backward[i] is the segment from fixes[i+1] to fixes[i]. A
+// segment is nil if it cannot be flown.
+type airway struct {
+	fixes    []*airwayFix
+	forward  []*Segment
+	backward []*Segment
+}
+
+// index returns the index of the first fix with the given identifier, or -1
+// if there is none.
+func (a *airway) index(fixID string) int {
+	for i, f := range a.fixes {
+		if f.fix.ID == fixID {
+			return i
+		}
+	}
+	return -1
+}
+
+// Graph is a directed graph of airway segments. Records may be added in any
+// order. A Graph is not safe for concurrent use.
+type Graph struct {
+	fixes   map[Airway][]*airwayFix
+	airways map[Airway]*airway
+	from    map[Fix][]*Segment
+}
+
+// NewGraph returns a new, empty Graph.
+func NewGraph() *Graph {
+	return &Graph{fixes: make(map[Airway][]*airwayFix)}
+}
+
+// Add adds the fix in the airway record to its airway. Continuation records
+// are ignored. If a field of the record is malformed, an error is returned.
+func (g *Graph) Add(rec *arinc.EnrouteAirwayRecord) error {
+	if rec.ContinuationRecordNumber != "0" && rec.ContinuationRecordNumber != "1" {
+		return nil
+	}
+	seq, err := strconv.Atoi(rec.SequenceNumber)
+	if err != nil {
+		return fmt.Errorf("invalid sequence number %q on airway %q", rec.SequenceNumber, rec.RouteID)
+	}
+	id := Airway{
+		CustomerArea: rec.CustomerAreaCode,
+		ICAOCode:     airwayICAOCode(rec.FixICAOCode),
+		RouteID:      rec.RouteID,
+	}
+	f := &airwayFix{
+		seq: seq,
+		fix: Fix{
+			ID:             rec.FixID,
+			ICAOCode:       rec.FixICAOCode,
+			SectionCode:    rec.FixSectionCode,
+			SubsectionCode: rec.FixSubsectionCode,
+		},
+		end: rec.IsEndOfAirway(),
+		next: Segment{
+			Airway:    id,
+			Level:     Level(rec.Level),
+			Direction: Direction(rec.DirectionRestriction),
+		},
+	}
+	switch f.next.Level {
+	case LevelAll, LevelHigh, LevelLow:
+	default:
+		return fmt.Errorf("invalid level %q at %q on airway %q", rec.Level, rec.FixID, rec.RouteID)
+	}
+	switch f.next.Direction {
+	case DirectionAny, DirectionForward, DirectionBackward:
+	default:
+		return fmt.Errorf("invalid direction restriction %q at %q on airway %q", rec.DirectionRestriction, rec.FixID, rec.RouteID)
+	}
+	if !unknownAltitudes[rec.MinimumAltitude] {
+		if f.next.MinimumAltitude, err = arinc.ParseAltitude(rec.MinimumAltitude); err != nil {
+			return fmt.Errorf("invalid minimum altitude at %q on airway %q: %v", rec.FixID, rec.RouteID, err)
+		}
+	}
+	// The second minimum altitude is for the opposite direction when the
+	// minimum altitudes differ by direction.
+	switch {
+	case rec.MinimumAltitude2 == "":
+		f.backwardMinimumAltitude = f.next.MinimumAltitude
+	case !unknownAltitudes[rec.MinimumAltitude2]:
+		if f.backwardMinimumAltitude, err = arinc.ParseAltitude(rec.MinimumAltitude2); err != nil {
+			return fmt.Errorf("invalid second minimum altitude at %q on airway %q: %v", rec.FixID, rec.RouteID, err)
+		}
+	}
+	if !unknownAltitudes[rec.MaximumAltitude] {
+		if f.next.MaximumAltitude, err = arinc.ParseAltitude(rec.MaximumAltitude); err != nil {
+			return fmt.Errorf("invalid maximum altitude at %q on airway %q: %v", rec.FixID, rec.RouteID, err)
+		}
+	}
+	f.next.Course = rec.OutboundMagneticCourse.Bearing
+	f.inboundCourse = rec.InboundMagneticCourse.Bearing
+	if rec.RouteDistanceFrom != "" {
+		if f.next.Distance, _, err = arinc.ParseDistanceOrTime(rec.RouteDistanceFrom); err != nil {
+			return fmt.Errorf("invalid distance at %q on airway %q: %v", rec.FixID, rec.RouteID, err)
+		}
+	}
+	g.fixes[id] = append(g.fixes[id], f)
+	g.airways = nil
+	return nil
+}
+
+// build assembles the airways from the fixes that have been added, if they
+// have changed since the last time.
+func (g *Graph) build() {
+	if g.airways != nil {
+		return
+	}
+	g.airways = make(map[Airway]*airway)
+	g.from = make(map[Fix][]*Segment)
+	for id, fixes := range g.fixes {
+		sort.SliceStable(fixes, func(i, j int) bool { return fixes[i].seq < fixes[j].seq })
+		a := &airway{fixes: fixes}
+		for i := 0; i < len(fixes)-1; i++ {
+			var fwd, bwd *Segment
+			if f := fixes[i]; !f.end {
+				if f.next.Direction != DirectionBackward {
+					s := f.next
+					s.From, s.To = f.fix, fixes[i+1].fix
+					fwd = &s
+					g.from[s.From] = append(g.from[s.From], fwd)
+				}
+				if f.next.Direction != DirectionForward {
+					s := f.next
+					s.From, s.To = fixes[i+1].fix, f.fix
+					s.MinimumAltitude = f.backwardMinimumAltitude
+					s.Course = f.inboundCourse
+					bwd = &s
+					g.from[s.From] = append(g.from[s.From], bwd)
+				}
+			}
+			a.forward = append(a.forward, fwd)
+			a.backward = append(a.backward, bwd)
+		}
+		g.airways[id] = a
+	}
+}
+
+// Airways returns all of the airways, sorted by customer area, ICAO code, and
+// route identifier.
+func (g *Graph) Airways() []Airway {
+	var ids []Airway
+	for id := range g.fixes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].CustomerArea != ids[j].CustomerArea {
+			return ids[i].CustomerArea < ids[j].CustomerArea
+		}
+		if ids[i].ICAOCode != ids[j].ICAOCode {
+			return ids[i].ICAOCode < ids[j].ICAOCode
+		}
+		return ids[i].RouteID < ids[j].RouteID
+	})
+	return ids
+}
+
+// Fixes returns the fixes of the airway in sequence order, or nil if there is
+// no such airway.
+func (g *Graph) Fixes(airwayID Airway) []Fix {
+	g.build()
+	a, ok := g.airways[airwayID]
+	if !ok {
+		return nil
+	}
+	fixes := make([]Fix, 0, len(a.fixes))
+	for _, f := range a.fixes {
+		fixes = append(fixes, f.fix)
+	}
+	return fixes
+}
+
+// From returns the segments that can be flown from the fix on any airway.
+func (g *Graph) From(fix Fix) []*Segment {
+	g.build()
+	return g.from[fix]
+}
+
+// Expand returns the segments of the airway from the fix with identifier from
+// to the fix with identifier to, in the order that they are flown. An error
+// is returned if either fix is not on the airway, or if the airway cannot be
+// flown between them because it is discontinuous or one way in the other
+// direction.
+func (g *Graph) Expand(airwayID Airway, from, to string) ([]*Segment, error) {
+	g.build()
+	a, ok := g.airways[airwayID]
+	if !ok {
+		return nil, fmt.Errorf("unknown airway %q", airwayID)
+	}
+	i := a.index(from)
+	if i < 0 {
+		return nil, fmt.Errorf("fix %q is not on airway %q", from, airwayID)
+	}
+	j := a.index(to)
+	if j < 0 {
+		return nil, fmt.Errorf("fix %q is not on airway %q", to, airwayID)
+	}
+	var route []*Segment
+	for i != j {
+		var s *Segment
+		var gap int
+		if i < j {
+			s, gap = a.forward[i], i
+			i++
+		} else {
+			s, gap = a.backward[i-1], i-1
+			i--
+		}
+		if s == nil {
+			if a.fixes[gap].end {
+				return nil, fmt.Errorf("airway %q is not continuous between %q and %q", airwayID, a.fixes[gap].fix, a.fixes[gap+1].fix)
+			}
+			return nil, fmt.Errorf("airway %q is one way between %q and %q", airwayID, a.fixes[gap].fix, a.fixes[gap+1].fix)
+		}
+		route = append(route, s)
+	}
+	return route, nil
+}
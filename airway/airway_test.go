@@ -0,0 +1,280 @@
+package airway
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+)
+
+// airwayRecord returns a primary record for the fix on the airway with the
+// attributes of the segment to the next fix.
+func airwayRecord(route, seq, fixID, desc, dir, mea, mea2, course, dist, inbound string) *arinc.EnrouteAirwayRecord {
+	return &arinc.EnrouteAirwayRecord{
+		Record:                   arinc.Record{RecordType: "S", CustomerAreaCode: "USA", SectionCode: "E", SubsectionCode: "R"},
+		RouteID:                  route,
+		SequenceNumber:           seq,
+		FixID:                    fixID,
+		FixICAOCode:              "K2",
+		FixSectionCode:           "D",
+		ContinuationRecordNumber: "0",
+		WaypointDescriptionCode:  desc,
+		Level:                    "L",
+		DirectionRestriction:     dir,
+		MinimumAltitude:          mea,
+		MinimumAltitude2:         mea2,
+		OutboundMagneticCourse:   bearing(course),
+		RouteDistanceFrom:        dist,
+		InboundMagneticCourse:    bearing(inbound),
+	}
+}
+
+// bearing decodes the course field, which must be valid or blank.
+func bearing(course string) arinc.NullBearing {
+	var b arinc.NullBearing
+	if err := b.UnmarshalText([]byte(course)); err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func fix(id string) Fix {
+	return Fix{ID: id, ICAOCode: "K2", SectionCode: "D"}
+}
+
+func airwayID(route string) Airway {
+	return Airway{CustomerArea: "USA", ICAOCode: "K", RouteID: route}
+}
+
+// testGraph returns a graph with a two way airway that has a gap after SUNOL,
+// and a one way airway. The MEA between OAK and SUNOL is higher towards OAK.
+// The records are out of order.
+func testGraph(t *testing.T) *Graph {
+	t.Helper()
+	g := NewGraph()
+	for _, rec := range []*arinc.EnrouteAirwayRecord{
+		airwayRecord("V25", "0120", "OAK", "V", "", "05000", "06000", "1100", "0150", "2904"),
+		airwayRecord("V25", "0100", "PYE", "V", "", "04000", "", "1150", "0208", "2947"),
+		airwayRecord("V25", "0110", "SAU", "V", "", "UNKNN", "", "0900", "0120", "2703"),
+		airwayRecord("V25", "0130", "SUNOL", "VE", "", "", "", "", "", ""),
+		airwayRecord("V25", "0140", "ECA", "V", "", "FL180", "", "0450", "0300", "2252"),
+		airwayRecord("V25", "0150", "LIN", "VE", "", "", "", "", "", ""),
+		airwayRecord("J501", "0010", "PYE", "V", "F", "FL180", "", "0900", "0500", "2700"),
+		airwayRecord("J501", "0020", "LIN", "VE", "", "", "", "", "", ""),
+	} {
+		if err := g.Add(rec); err != nil {
+			t.Fatalf("Add() = %v want <nil>", err)
+		}
+	}
+	return g
+}
+
+func TestExpand(t *testing.T) {
+	g := testGraph(t)
+	for _, tt := range []struct {
+		name    string
+		airway  string
+		from    string
+		to      string
+		want    []*Segment
+		wantErr bool
+	}{
+		{
+			name:   "Forward",
+			airway: "V25",
+			from:   "PYE",
+			to:     "OAK",
+			want: []*Segment{
+				{Airway: airwayID("V25"), From: fix("PYE"), To: fix("SAU"), Level: LevelLow, MinimumAltitude: arinc.Altitude{Feet: 4000}, Course: arinc.Bearing{Value: 115}, Distance: 20.8},
+				{Airway: airwayID("V25"), From: fix("SAU"), To: fix("OAK"), Level: LevelLow, Course: arinc.Bearing{Value: 90}, Distance: 12},
+			},
+		},
+		{
+			name:   "Backward",
+			airway: "V25",
+			from:   "SUNOL",
+			to:     "SAU",
+			want: []*Segment{
+				{Airway: airwayID("V25"), From: fix("SUNOL"), To: fix("OAK"), Level: LevelLow, MinimumAltitude: arinc.Altitude{Feet: 6000}, Course: arinc.Bearing{Value: 290.4}, Distance: 15},
+				{Airway: airwayID("V25"), From: fix("OAK"), To: fix("SAU"), Level: LevelLow, Course: arinc.Bearing{Value: 270.3}, Distance: 12},
+			},
+		},
+		{
+			name:   "OneWay",
+			airway: "J501",
+			from:   "PYE",
+			to:     "LIN",
+			want: []*Segment{
+				{Airway: airwayID("J501"), From: fix("PYE"), To: fix("LIN"), Level: LevelLow, Direction: DirectionForward, MinimumAltitude: arinc.Altitude{Feet: 18000, Reference: arinc.FlightLevel}, Course: arinc.Bearing{Value: 90}, Distance: 50},
+			},
+		},
+		{
+			name:   "SameFix",
+			airway: "V25",
+			from:   "OAK",
+			to:     "OAK",
+		},
+		{
+			name:    "OneWayWrongDirection",
+			airway:  "J501",
+			from:    "LIN",
+			to:      "PYE",
+			wantErr: true,
+		},
+		{
+			name:    "Discontinuous",
+			airway:  "V25",
+			from:    "PYE",
+			to:      "LIN",
+			wantErr: true,
+		},
+		{
+			name:    "UnknownAirway",
+			airway:  "V999",
+			from:    "PYE",
+			to:      "SAU",
+			wantErr: true,
+		},
+		{
+			name:    "FixNotOnAirway",
+			airway:  "J501",
+			from:    "PYE",
+			to:      "SAU",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := g.Expand(airwayID(tt.airway), tt.from, tt.to)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expand(%q, %q, %q) = _, <nil> want _, <non-nil>", tt.airway, tt.from, tt.to)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expand(%q, %q, %q) = _, %v want _, <nil>", tt.airway, tt.from, tt.to, err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Expand(%q, %q, %q) had diffs (-want +got): %s", tt.airway, tt.from, tt.to, diff)
+			}
+		})
+	}
+}
+
+func TestGraph(t *testing.T) {
+	g := testGraph(t)
+	if diff := cmp.Diff([]Airway{airwayID("J501"), airwayID("V25")}, g.Airways()); diff != "" {
+		t.Errorf("Airways() had diffs (-want +got): %s", diff)
+	}
+	want := []Fix{fix("PYE"), fix("SAU"), fix("OAK"), fix("SUNOL"), fix("ECA"), fix("LIN")}
+	if diff := cmp.Diff(want, g.Fixes(airwayID("V25"))); diff != "" {
+		t.Errorf("Fixes() had diffs (-want +got): %s", diff)
+	}
+	if got := g.Fixes(airwayID("V999")); got != nil {
+		t.Errorf("Fixes() = %v want <nil>", got)
+	}
+	var gotTo []string
+	for _, s := range g.From(fix("LIN")) {
+		gotTo = append(gotTo, s.Airway.RouteID+" "+s.To.ID)
+	}
+	// J501 is one way towards LIN, so the only segment from LIN is on V25.
+	if diff := cmp.Diff([]string{"V25 ECA"}, gotTo); diff != "" {
+		t.Errorf("From() had diffs (-want +got): %s", diff)
+	}
+
+	// Adding a record after a query rebuilds the graph.
+	if err := g.Add(airwayRecord("J501", "0030", "ECA", "VE", "", "", "", "", "", "")); err != nil {
+		t.Fatalf("Add() = %v want <nil>", err)
+	}
+	if diff := cmp.Diff([]Fix{fix("PYE"), fix("LIN"), fix("ECA")}, g.Fixes(airwayID("J501"))); diff != "" {
+		t.Errorf("Fixes() after Add() had diffs (-want +got): %s", diff)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		record  *arinc.EnrouteAirwayRecord
+		wantErr bool
+	}{
+		{
+			name:   "Good",
+			record: airwayRecord("V25", "0100", "PYE", "V", "", "04000", "", "1150", "0208", "2947"),
+		},
+		{
+			name:   "Continuation",
+			record: &arinc.EnrouteAirwayRecord{RouteID: "V25", ContinuationRecordNumber: "2"},
+		},
+		{
+			name:    "InvalidSequenceNumber",
+			record:  airwayRecord("V25", "01X0", "PYE", "V", "", "04000", "", "1150", "0208", "2947"),
+			wantErr: true,
+		},
+		{
+			name:    "InvalidDirection",
+			record:  airwayRecord("V25", "0100", "PYE", "V", "X", "04000", "", "1150", "0208", "2947"),
+			wantErr: true,
+		},
+		{
+			name:    "InvalidMinimumAltitude",
+			record:  airwayRecord("V25", "0100", "PYE", "V", "", "4000", "", "1150", "0208", "2947"),
+			wantErr: true,
+		},
+		{
+			name:    "InvalidSecondMinimumAltitude",
+			record:  airwayRecord("V25", "0100", "PYE", "V", "", "04000", "5000", "1150", "0208", "2947"),
+			wantErr: true,
+		},
+		{
+			name:    "InvalidDistance",
+			record:  airwayRecord("V25", "0100", "PYE", "V", "", "04000", "", "1150", "02X8", "2947"),
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewGraph().Add(tt.record)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Add() = <nil> want <non-nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Add() = %v want <nil>", err)
+			}
+		})
+	}
+}
+
+func TestAirwayKey(t *testing.T) {
+	g := NewGraph()
+	hawaii := airwayRecord("V2", "0010", "LNY", "V", "", "06000", "", "1200", "0300", "3000")
+	hawaii.FixICAOCode = "PH"
+	oregon := airwayRecord("V2", "0020", "DSD", "V", "", "06000", "", "0900", "0400", "2700")
+	oregon.FixICAOCode = "K1"
+	for _, rec := range []*arinc.EnrouteAirwayRecord{
+		airwayRecord("V2", "0010", "PYE", "V", "", "05000", "", "0450", "0500", "2250"),
+		oregon,
+		hawaii,
+	} {
+		if err := g.Add(rec); err != nil {
+			t.Fatalf("Add() = %v want <nil>", err)
+		}
+	}
+	want := []Airway{
+		{CustomerArea: "USA", ICAOCode: "K", RouteID: "V2"},
+		{CustomerArea: "USA", ICAOCode: "PH", RouteID: "V2"},
+	}
+	if diff := cmp.Diff(want, g.Airways()); diff != "" {
+		t.Errorf("Airways() had diffs (-want +got): %s", diff)
+	}
+	// The airway crosses from K2 to K1, so both fixes are on the same airway.
+	wantFixes := []Fix{fix("PYE"), {ID: "DSD", ICAOCode: "K1", SectionCode: "D"}}
+	if diff := cmp.Diff(wantFixes, g.Fixes(want[0])); diff != "" {
+		t.Errorf("Fixes(%v) had diffs (-want +got): %s", want[0], diff)
+	}
+	wantFixes = []Fix{{ID: "LNY", ICAOCode: "PH", SectionCode: "D"}}
+	if diff := cmp.Diff(wantFixes, g.Fixes(want[1])); diff != "" {
+		t.Errorf("Fixes(%v) had diffs (-want +got): %s", want[1], diff)
+	}
+}
@@ -0,0 +1,214 @@
+package arinc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BoundaryVia is the path that an airspace boundary follows from a point to
+// the next point.
+// See 5.118 Boundary Via
+type BoundaryVia string
+
+const (
+	// BoundaryCircle is a circle around the arc origin. It is the only point
+	// of its boundary, and the point has no position of its own.
+	BoundaryCircle BoundaryVia = "C"
+	// BoundaryGreatCircle is a great circle line to the next point.
+	BoundaryGreatCircle BoundaryVia = "G"
+	// BoundaryRhumbLine is a rhumb line to the next point.
+	BoundaryRhumbLine BoundaryVia = "H"
+	// BoundaryCounterClockwiseArc is an arc around the arc origin,
+	// counterclockwise to the next point.
+	BoundaryCounterClockwiseArc BoundaryVia = "L"
+	// BoundaryClockwiseArc is an arc around the arc origin, clockwise to the
+	// next point.
+	BoundaryClockwiseArc BoundaryVia = "R"
+)
+
+// IsArc returns true if the boundary follows an arc or circle around the arc
+// origin.
+func (v BoundaryVia) IsArc() bool {
+	return v == BoundaryCircle || v == BoundaryCounterClockwiseArc || v == BoundaryClockwiseArc
+}
+
+// AirspaceLimit is the lower or upper limit of an airspace. At most one of
+// Ground, Unlimited, and NotSpecified is true, and if none of them are, the
+// limit is Altitude.
+type AirspaceLimit struct {
+	Altitude     Altitude
+	Ground       bool
+	Unlimited    bool
+	NotSpecified bool
+}
+
+// String returns the limit as it is written on charts, such as "GND",
+// "UNLTD", "FL180", "10000", or "1500 AGL".
+func (l AirspaceLimit) String() string {
+	switch {
+	case l.Ground:
+		return "GND"
+	case l.Unlimited:
+		return "UNLTD"
+	case l.NotSpecified:
+		return "NOTSP"
+	}
+	return l.Altitude.String()
+}
+
+// ParseAirspaceLimit returns the airspace limit of the provided five character
+// limit and its one character unit indicator, which is "M" for mean sea level
+// or "A" for above ground level. If any error occurs, an error is returned.
+// See 5.121 Lower/Upper Limit, 5.133 Unit Indicator
+func ParseAirspaceLimit(limit, unit string) (AirspaceLimit, error) {
+	switch strings.TrimSpace(limit) {
+	case "GND":
+		return AirspaceLimit{Ground: true}, nil
+	case "UNLTD":
+		return AirspaceLimit{Unlimited: true}, nil
+	case "NOTSP":
+		return AirspaceLimit{NotSpecified: true}, nil
+	}
+	alt, err := ParseAltitude(limit)
+	if err != nil {
+		return AirspaceLimit{}, err
+	}
+	l := AirspaceLimit{Altitude: alt}
+	switch unit {
+	case "M":
+	case "A":
+		if alt.Reference == FlightLevel {
+			return AirspaceLimit{}, fmt.Errorf("flight level %q cannot be above ground level", limit)
+		}
+		l.Altitude.Reference = AboveGroundLevel
+	default:
+		if alt.Reference != FlightLevel {
+			return AirspaceLimit{}, fmt.Errorf("invalid unit indicator %q", unit)
+		}
+	}
+	return l, nil
+}
+
+// AirspaceBoundaryPoint is a decoded point on the boundary of a controlled or
+// restrictive airspace. Positions are in decimal degrees, and fields that are
+// blank in the record are zero.
+type AirspaceBoundaryPoint struct {
+	Sequence int
+	Via      BoundaryVia
+	// End is true if the point is the last one of its boundary, and the
+	// boundary returns from it to the first point.
+	End bool
+	// Lat and Lon are the position of the point. They are zero for circles.
+	Lat, Lon float64
+	// ArcOriginLat and ArcOriginLon are the center of an arc or circle.
+	ArcOriginLat, ArcOriginLon float64
+	// ArcDistance is the radius of an arc or circle in nautical miles.
+	ArcDistance float64
+	// ArcBearing is the true bearing in degrees from the arc origin to the
+	// start of an arc.
+	ArcBearing float64
+	// Lower and Upper are the limits of the airspace, or nil if they are not
+	// given on this record.
+	Lower, Upper *AirspaceLimit
+	Name         string
+}
+
+// BoundaryPoint decodes the boundary fields of the controlled airspace
+// record. If any field is malformed, an error naming the field is returned.
+func (r *ControlledAirspaceRecord) BoundaryPoint() (*AirspaceBoundaryPoint, error) {
+	return boundaryFields{
+		sequence:   r.SequenceNumber,
+		via:        r.BoundaryVia,
+		position:   r.Position,
+		arcOrigin:  r.ArcOrigin,
+		arcDist:    r.ArcDistance,
+		arcBearing: r.ArcBearing,
+		lower:      r.LowerLimit,
+		lowerUnit:  r.LowerLimitUnitIndicator,
+		upper:      r.UpperLimit,
+		upperUnit:  r.UpperLimitUnitIndicator,
+		name:       r.ControlledAirspaceName,
+	}.decode()
+}
+
+// BoundaryPoint decodes the boundary fields of the restrictive airspace
+// record. If any field is malformed, an error naming the field is returned.
+func (r *RestrictiveAirspaceRecord) BoundaryPoint() (*AirspaceBoundaryPoint, error) {
+	return boundaryFields{
+		sequence:   r.SequenceNumber,
+		via:        r.BoundaryVia,
+		position:   r.Position,
+		arcOrigin:  r.ArcOrigin,
+		arcDist:    r.ArcDistance,
+		arcBearing: r.ArcBearing,
+		lower:      r.LowerLimit,
+		lowerUnit:  r.LowerLimitUnitIndicator,
+		upper:      r.UpperLimit,
+		upperUnit:  r.UpperLimitUnitIndicator,
+		name:       r.RestrictiveAirspaceName,
+	}.decode()
+}
+
+// boundaryFields are the fields that controlled and restrictive airspace
+// records have in common.
+type boundaryFields struct {
+	sequence, via                      string
+	position, arcOrigin                NullCoordinate
+	arcDist, arcBearing                string
+	lower, lowerUnit, upper, upperUnit string
+	name                               string
+}
+
+func (f boundaryFields) decode() (*AirspaceBoundaryPoint, error) {
+	seq, err := strconv.Atoi(f.sequence)
+	if err != nil {
+		return nil, fmt.Errorf("SequenceNumber: invalid sequence number %q", f.sequence)
+	}
+	p := &AirspaceBoundaryPoint{Sequence: seq, Name: f.name}
+	if len(f.via) == 0 || len(f.via) > 2 || (len(f.via) == 2 && f.via[1] != 'E') {
+		return nil, fmt.Errorf("BoundaryVia: invalid boundary via %q", f.via)
+	}
+	p.Via = BoundaryVia(f.via[:1])
+	p.End = len(f.via) == 2
+	switch p.Via {
+	case BoundaryCircle, BoundaryGreatCircle, BoundaryRhumbLine, BoundaryCounterClockwiseArc, BoundaryClockwiseArc:
+	default:
+		return nil, fmt.Errorf("BoundaryVia: invalid boundary via %q", f.via)
+	}
+	if p.Via != BoundaryCircle {
+		if !f.position.Valid {
+			return nil, fmt.Errorf("Position: missing position")
+		}
+		p.Lat, p.Lon = f.position.Coordinate.Lat, f.position.Coordinate.Lon
+	}
+	if p.Via.IsArc() {
+		if !f.arcOrigin.Valid {
+			return nil, fmt.Errorf("ArcOrigin: missing arc origin")
+		}
+		p.ArcOriginLat, p.ArcOriginLon = f.arcOrigin.Coordinate.Lat, f.arcOrigin.Coordinate.Lon
+		if p.ArcDistance, err = parseTenths(f.arcDist, "arc distance"); err != nil || p.ArcDistance == 0 {
+			return nil, fmt.Errorf("ArcDistance: invalid arc distance %q", f.arcDist)
+		}
+		if f.arcBearing != "" {
+			if p.ArcBearing, err = parseTenths(f.arcBearing, "arc bearing"); err != nil || p.ArcBearing > 360 {
+				return nil, fmt.Errorf("ArcBearing: invalid arc bearing %q", f.arcBearing)
+			}
+		}
+	}
+	if f.lower != "" {
+		l, err := ParseAirspaceLimit(f.lower, f.lowerUnit)
+		if err != nil {
+			return nil, fmt.Errorf("LowerLimit: %v", err)
+		}
+		p.Lower = &l
+	}
+	if f.upper != "" {
+		l, err := ParseAirspaceLimit(f.upper, f.upperUnit)
+		if err != nil {
+			return nil, fmt.Errorf("UpperLimit: %v", err)
+		}
+		p.Upper = &l
+	}
+	return p, nil
+}
@@ -0,0 +1,186 @@
+package arinc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseAirspaceLimit(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		limit   string
+		unit    string
+		want    AirspaceLimit
+		wantStr string
+		wantErr bool
+	}{
+		{name: "Ground", limit: "GND", unit: "M", want: AirspaceLimit{Ground: true}, wantStr: "GND"},
+		{name: "Unlimited", limit: "UNLTD", want: AirspaceLimit{Unlimited: true}, wantStr: "UNLTD"},
+		{name: "NotSpecified", limit: "NOTSP", want: AirspaceLimit{NotSpecified: true}, wantStr: "NOTSP"},
+		{name: "MSL", limit: "04000", unit: "M", want: AirspaceLimit{Altitude: Altitude{Feet: 4000}}, wantStr: "4000"},
+		{name: "AGL", limit: "01500", unit: "A", want: AirspaceLimit{Altitude: Altitude{Feet: 1500, Reference: AboveGroundLevel}}, wantStr: "1500 AGL"},
+		{name: "FlightLevel", limit: "FL180", unit: "M", want: AirspaceLimit{Altitude: Altitude{Feet: 18000, Reference: FlightLevel}}, wantStr: "FL180"},
+		{name: "FlightLevelNoUnit", limit: "FL600", want: AirspaceLimit{Altitude: Altitude{Feet: 60000, Reference: FlightLevel}}, wantStr: "FL600"},
+		{name: "FlightLevelAGL", limit: "FL180", unit: "A", wantErr: true},
+		{name: "NoUnit", limit: "04000", wantErr: true},
+		{name: "BadUnit", limit: "04000", unit: "X", wantErr: true},
+		{name: "BadAltitude", limit: "4000", unit: "M", wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAirspaceLimit(tt.limit, tt.unit)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseAirspaceLimit(%q, %q) = %v, <nil> want _, <non-nil>", tt.limit, tt.unit, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAirspaceLimit(%q, %q) = _, %v want _, <nil>", tt.limit, tt.unit, err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseAirspaceLimit(%q, %q) had diffs (-want +got): %s", tt.limit, tt.unit, diff)
+			}
+			if got.String() != tt.wantStr {
+				t.Errorf("ParseAirspaceLimit(%q, %q).String() = %q want %q", tt.limit, tt.unit, got.String(), tt.wantStr)
+			}
+		})
+	}
+}
+
+func TestBoundaryPoint(t *testing.T) {
+	approx := cmp.Comparer(func(x, y float64) bool { return math.Abs(x-y) < 0.00001 })
+	t.Run("ControlledArc", func(t *testing.T) {
+		rec, err := Parse([]byte("SUSAUCK2AKOAK PAC  A00101BC   R N37500000W122120000N37400000W12212000001000000   GND  M04000MOAKLAND CLASS C               123452002"))
+		if err != nil {
+			t.Fatalf("Parse() = %v want <nil>", err)
+		}
+		got, err := rec.(*ControlledAirspaceRecord).BoundaryPoint()
+		if err != nil {
+			t.Fatalf("BoundaryPoint() = _, %v want _, <nil>", err)
+		}
+		want := &AirspaceBoundaryPoint{
+			Sequence:     10,
+			Via:          BoundaryClockwiseArc,
+			Lat:          37 + 50.0/60,
+			Lon:          -(122 + 12.0/60),
+			ArcOriginLat: 37 + 40.0/60,
+			ArcOriginLon: -(122 + 12.0/60),
+			ArcDistance:  10,
+			Lower:        &AirspaceLimit{Ground: true},
+			Upper:        &AirspaceLimit{Altitude: Altitude{Feet: 4000}},
+			Name:         "OAKLAND CLASS C",
+		}
+		if diff := cmp.Diff(want, got, approx); diff != "" {
+			t.Errorf("BoundaryPoint() had diffs (-want +got): %s", diff)
+		}
+	})
+	t.Run("RestrictiveCircle", func(t *testing.T) {
+		rec, err := Parse([]byte("SUSAURK2R2531      A00101BC   CE                   N37000000W1210000000050       GND  MFL180MR-2531 TEST                   123452002"))
+		if err != nil {
+			t.Fatalf("Parse() = %v want <nil>", err)
+		}
+		got, err := rec.(*RestrictiveAirspaceRecord).BoundaryPoint()
+		if err != nil {
+			t.Fatalf("BoundaryPoint() = _, %v want _, <nil>", err)
+		}
+		want := &AirspaceBoundaryPoint{
+			Sequence:     10,
+			Via:          BoundaryCircle,
+			End:          true,
+			ArcOriginLat: 37,
+			ArcOriginLon: -121,
+			ArcDistance:  5,
+			Lower:        &AirspaceLimit{Ground: true},
+			Upper:        &AirspaceLimit{Altitude: Altitude{Feet: 18000, Reference: FlightLevel}},
+			Name:         "R-2531 TEST",
+		}
+		if diff := cmp.Diff(want, got, approx); diff != "" {
+			t.Errorf("BoundaryPoint() had diffs (-want +got): %s", diff)
+		}
+	})
+}
+
+func TestBoundaryPointErrors(t *testing.T) {
+	good := func() *ControlledAirspaceRecord {
+		return &ControlledAirspaceRecord{
+			SequenceNumber:          "0010",
+			BoundaryVia:             "L",
+			Position:                NullCoordinate{Coordinate: Coordinate{Lat: 37 + 50.0/60, Lon: -(122 + 12.0/60)}, Valid: true},
+			ArcOrigin:               NullCoordinate{Coordinate: Coordinate{Lat: 37 + 40.0/60, Lon: -(122 + 12.0/60)}, Valid: true},
+			ArcDistance:             "0100",
+			ArcBearing:              "3600",
+			LowerLimit:              "GND",
+			LowerLimitUnitIndicator: "M",
+			UpperLimit:              "04000",
+			UpperLimitUnitIndicator: "M",
+		}
+	}
+	for _, tt := range []struct {
+		name   string
+		modify func(r *ControlledAirspaceRecord)
+	}{
+		{
+			name:   "SequenceNumber",
+			modify: func(r *ControlledAirspaceRecord) { r.SequenceNumber = "" },
+		},
+		{
+			name:   "BoundaryVia",
+			modify: func(r *ControlledAirspaceRecord) { r.BoundaryVia = "A" },
+		},
+		{
+			name:   "BoundaryViaEnd",
+			modify: func(r *ControlledAirspaceRecord) { r.BoundaryVia = "LX" },
+		},
+		{
+			name:   "Position",
+			modify: func(r *ControlledAirspaceRecord) { r.Position = NullCoordinate{} },
+		},
+		{
+			name:   "ArcOrigin",
+			modify: func(r *ControlledAirspaceRecord) { r.ArcOrigin = NullCoordinate{} },
+		},
+		{
+			name:   "ArcDistance",
+			modify: func(r *ControlledAirspaceRecord) { r.ArcDistance = "0000" },
+		},
+		{
+			name:   "ArcBearing",
+			modify: func(r *ControlledAirspaceRecord) { r.ArcBearing = "3601" },
+		},
+		{
+			name:   "LowerLimit",
+			modify: func(r *ControlledAirspaceRecord) { r.LowerLimit = "SFC" },
+		},
+		{
+			name:   "UpperLimit",
+			modify: func(r *ControlledAirspaceRecord) { r.UpperLimit = "4000" },
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := good()
+			if _, err := r.BoundaryPoint(); err != nil {
+				t.Fatalf("BoundaryPoint() before modification = _, %v want _, <nil>", err)
+			}
+			tt.modify(r)
+			if _, err := r.BoundaryPoint(); err == nil {
+				t.Errorf("BoundaryPoint() = _, <nil> want _, <non-nil>")
+			}
+		})
+	}
+}
+
+func TestBoundaryViaIsArc(t *testing.T) {
+	for via, want := range map[BoundaryVia]bool{
+		BoundaryCircle:              true,
+		BoundaryGreatCircle:         false,
+		BoundaryRhumbLine:           false,
+		BoundaryCounterClockwiseArc: true,
+		BoundaryClockwiseArc:        true,
+	} {
+		if got := via.IsArc(); got != want {
+			t.Errorf("BoundaryVia(%q).IsArc() = %t want %t", via, got, want)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package arinc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CommunicationType is the type of facility or service on a communication
+// frequency.
+// See 5.101 Communication Type
+type CommunicationType string
+
+const (
+	CommunicationApproach          CommunicationType = "APP"
+	CommunicationASOS              CommunicationType = "ASO"
+	CommunicationATIS              CommunicationType = "ATI"
+	CommunicationAWOS              CommunicationType = "AWO"
+	CommunicationClearanceDelivery CommunicationType = "CLD"
+	CommunicationDeparture         CommunicationType = "DEP"
+	CommunicationGround            CommunicationType = "GND"
+	CommunicationTower             CommunicationType = "TWR"
+	CommunicationUNICOM            CommunicationType = "UNI"
+)
+
+// FrequencyUnits is the band of a communication frequency.
+// See 5.104 Frequency Units
+type FrequencyUnits string
+
+const (
+	FrequencyUnitsHF  FrequencyUnits = "H"
+	FrequencyUnitsVHF FrequencyUnits = "V"
+	FrequencyUnitsUHF FrequencyUnits = "U"
+	// FrequencyUnitsVHFChannel is a VHF channel with 8.33 kHz spacing. Its
+	// frequency is the channel name, such as 118.005, rather than the
+	// frequency that is transmitted.
+	FrequencyUnitsVHFChannel FrequencyUnits = "C"
+)
+
+// Frequency is a radio frequency in hertz.
+type Frequency int64
+
+// String returns the frequency as it is written on charts, in kHz for HF
+// frequencies and in MHz otherwise, such as "8891.0 kHz" or "120.200 MHz".
+func (f Frequency) String() string {
+	if f < 30000000 {
+		return fmt.Sprintf("%.1f kHz", float64(f)/1e3)
+	}
+	return fmt.Sprintf("%.3f MHz", float64(f)/1e6)
+}
+
+// ParseFrequency returns the frequency of the provided seven character
+// communication frequency in the given units. The frequency is in units of
+// 100 Hz, which is tenths of a kHz for HF and ten thousandths of a MHz
+// otherwise. For example, "1202000" in VHF units is 120.200 MHz. If any error
+// occurs, an error is returned.
+// See 5.103 Communication Frequency
+func ParseFrequency(freq string, units FrequencyUnits) (Frequency, error) {
+	switch units {
+	case FrequencyUnitsHF, FrequencyUnitsVHF, FrequencyUnitsUHF, FrequencyUnitsVHFChannel:
+	default:
+		return 0, fmt.Errorf("invalid frequency units %q", units)
+	}
+	n, err := strconv.Atoi(freq)
+	if err != nil || n <= 0 || len(freq) != 7 {
+		return 0, fmt.Errorf("invalid frequency %q", freq)
+	}
+	return Frequency(n) * 100, nil
+}
+
+// CommunicationSector is the part of the airspace around an airport in which
+// a sectorized frequency is used.
+type CommunicationSector struct {
+	// The sector extends clockwise from FromBearing to ToBearing, which are
+	// bearings from Facility, or from the airport reference point if it is
+	// empty.
+	FromBearing float64
+	ToBearing   float64
+	Facility    string
+	Altitude    AltitudeConstraint
+}
+
+// Communication is a decoded airport communication frequency.
+type Communication struct {
+	AirportID string
+	Type      CommunicationType
+	Frequency Frequency
+	Units     FrequencyUnits
+	// ServiceIndicator holds up to three codes for the services provided on
+	// the frequency, one from each of the lists in the specification.
+	// See 5.106 Service Indicator
+	ServiceIndicator string
+	// Radar is true if radar service is provided on the frequency.
+	Radar bool
+	// H24 is true if the frequency is available 24 hours a day.
+	H24 bool
+	// Sector is nil if the frequency is used in all directions.
+	Sector   *CommunicationSector
+	CallSign string
+	// Narratives are the sectorization narratives of the continuation
+	// records, in order.
+	Narratives []string
+}
+
+// Communication decodes the fields of the communication record. If any field
+// is malformed, an error naming the field is returned.
+func (r *AirportCommunicationPrimaryRecord) Communication() (*Communication, error) {
+	c := &Communication{
+		AirportID:        r.AirportID,
+		Type:             CommunicationType(r.CommunicationType),
+		Units:            FrequencyUnits(r.FrequencyUnits),
+		ServiceIndicator: r.ServiceIndicator,
+		Radar:            r.RadarService == "R",
+		H24:              r.H24Indicator == "Y",
+		CallSign:         r.CallSign,
+	}
+	var err error
+	if c.Frequency, err = ParseFrequency(r.CommunicationFrequency, c.Units); err != nil {
+		return nil, fmt.Errorf("CommunicationFrequency: %v", err)
+	}
+	if r.Sectorization == "" {
+		return c, nil
+	}
+	from, to, err := parseSectorization(r.Sectorization)
+	if err != nil {
+		return nil, fmt.Errorf("Sectorization: %v", err)
+	}
+	alt, err := NewAltitudeConstraint(r.AltitudeDescription, r.CommunicationAltitude1, r.CommunicationAltitude2)
+	if err != nil {
+		return nil, fmt.Errorf("CommunicationAltitude: %v", err)
+	}
+	c.Sector = &CommunicationSector{
+		FromBearing: from,
+		ToBearing:   to,
+		Facility:    r.SectorFacility,
+		Altitude:    alt,
+	}
+	return c, nil
+}
+
+// parseSectorization parses a six character pair of whole degree bearings.
+// See 5.183 Sectorization
+func parseSectorization(s string) (from, to float64, _ error) {
+	if len(s) != 6 {
+		return 0, 0, fmt.Errorf("invalid sectorization %q, want 6 characters", s)
+	}
+	f, err1 := strconv.Atoi(s[:3])
+	t, err2 := strconv.Atoi(s[3:])
+	if err1 != nil || err2 != nil || f < 0 || f > 360 || t < 0 || t > 360 {
+		return 0, 0, fmt.Errorf("invalid sectorization %q", s)
+	}
+	return float64(f), float64(t), nil
+}
+
+type communicationKey struct {
+	commType  CommunicationType
+	frequency string
+	units     FrequencyUnits
+}
+
+// CommunicationDirectory lists the communication frequencies of each airport.
+type CommunicationDirectory struct {
+	airports map[string][]*Communication
+	// last is the most recently added communication of each airport, keyed
+	// by the fields that identify it, for attaching continuation records.
+	last map[string]map[communicationKey]*Communication
+}
+
+// NewCommunicationDirectory returns a new, empty CommunicationDirectory.
+func NewCommunicationDirectory() *CommunicationDirectory {
+	return &CommunicationDirectory{
+		airports: make(map[string][]*Communication),
+		last:     make(map[string]map[communicationKey]*Communication),
+	}
+}
+
+// Add decodes the communication record and adds it to its airport. If the
+// record is malformed, an error is returned.
+func (d *CommunicationDirectory) Add(rec *AirportCommunicationPrimaryRecord) error {
+	c, err := rec.Communication()
+	if err != nil {
+		return fmt.Errorf("could not decode %s communication at %q: %v", rec.CommunicationType, rec.AirportID, err)
+	}
+	d.airports[rec.AirportID] = append(d.airports[rec.AirportID], c)
+	if d.last[rec.AirportID] == nil {
+		d.last[rec.AirportID] = make(map[communicationKey]*Communication)
+	}
+	k := communicationKey{c.Type, rec.CommunicationFrequency, c.Units}
+	d.last[rec.AirportID][k] = c
+	return nil
+}
+
+// AddContinuation adds the narrative of the continuation record to the
+// communication that it continues, which must have been added already. If
+// there is no such communication, an error is returned.
+func (d *CommunicationDirectory) AddContinuation(rec *AirportCommunicationContinuationRecord) error {
+	k := communicationKey{CommunicationType(rec.CommunicationType), rec.CommunicationFrequency, FrequencyUnits(rec.FrequencyUnits)}
+	c, ok := d.last[rec.AirportID][k]
+	if !ok {
+		return fmt.Errorf("no %s communication on %q at %q for continuation record", rec.CommunicationType, rec.CommunicationFrequency, rec.AirportID)
+	}
+	if n := strings.TrimSpace(rec.Narrative); n != "" {
+		c.Narratives = append(c.Narratives, n)
+	}
+	return nil
+}
+
+// Airport returns the communications of the airport in the order that they
+// were added, or nil if there are none.
+func (d *CommunicationDirectory) Airport(airportID string) []*Communication {
+	return d.airports[airportID]
+}
+
+// Airports returns the identifiers of the airports that have communications,
+// sorted.
+func (d *CommunicationDirectory) Airports() []string {
+	ids := make([]string, 0, len(d.airports))
+	for id := range d.airports {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,197 @@
+package arinc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	testTowerRecord        = "SUSAP KHWDK2VTWR1202000V0   NA3N37393214W122071825E015000052N                                     HAYWARD TOWER            109012002"
+	testApproachRecord     = "SUSAP KHWDK2VAPP1348500V1   RA3N37393214W122071825E015000052Y000180B0800001500OAK K2D             NORCAL APPROACH          109022002"
+	testApproachContRecord = "SUSAP KHWDK2VAPP1348500V2NNORTH OF THE AIRPORT BELOW 8000                                                                  109032002"
+)
+
+func TestParseFrequency(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		freq    string
+		units   FrequencyUnits
+		want    Frequency
+		wantStr string
+		wantErr bool
+	}{
+		{name: "VHF", freq: "1202000", units: FrequencyUnitsVHF, want: 120200000, wantStr: "120.200 MHz"},
+		{name: "VHFChannel", freq: "1180050", units: FrequencyUnitsVHFChannel, want: 118005000, wantStr: "118.005 MHz"},
+		{name: "UHF", freq: "2511500", units: FrequencyUnitsUHF, want: 251150000, wantStr: "251.150 MHz"},
+		{name: "HF", freq: "0088910", units: FrequencyUnitsHF, want: 8891000, wantStr: "8891.0 kHz"},
+		{name: "BadUnits", freq: "1202000", units: "X", wantErr: true},
+		{name: "BadLength", freq: "120200", units: FrequencyUnitsVHF, wantErr: true},
+		{name: "NotNumber", freq: "120.200", units: FrequencyUnitsVHF, wantErr: true},
+		{name: "Zero", freq: "0000000", units: FrequencyUnitsVHF, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFrequency(tt.freq, tt.units)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseFrequency(%q, %q) = %v, <nil> want _, <non-nil>", tt.freq, tt.units, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFrequency(%q, %q) = _, %v want _, <nil>", tt.freq, tt.units, err)
+			}
+			if got != tt.want || got.String() != tt.wantStr {
+				t.Errorf("ParseFrequency(%q, %q) = %d (%s) want %d (%s)", tt.freq, tt.units, got, got, tt.want, tt.wantStr)
+			}
+		})
+	}
+}
+
+func TestCommunication(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		record string
+		want   *Communication
+	}{
+		{
+			name:   "Tower",
+			record: testTowerRecord,
+			want: &Communication{
+				AirportID: "KHWD",
+				Type:      CommunicationTower,
+				Frequency: 120200000,
+				Units:     FrequencyUnitsVHF,
+				CallSign:  "HAYWARD TOWER",
+			},
+		},
+		{
+			name:   "SectorizedApproach",
+			record: testApproachRecord,
+			want: &Communication{
+				AirportID: "KHWD",
+				Type:      CommunicationApproach,
+				Frequency: 134850000,
+				Units:     FrequencyUnitsVHF,
+				Radar:     true,
+				H24:       true,
+				Sector: &CommunicationSector{
+					FromBearing: 0,
+					ToBearing:   180,
+					Facility:    "OAK",
+					Altitude: AltitudeConstraint{
+						Type:  ConstraintBetween,
+						Lower: Altitude{Feet: 1500},
+						Upper: Altitude{Feet: 8000},
+					},
+				},
+				CallSign: "NORCAL APPROACH",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := Parse([]byte(tt.record))
+			if err != nil {
+				t.Fatalf("Parse() = %v want <nil>", err)
+			}
+			got, err := rec.(*AirportCommunicationPrimaryRecord).Communication()
+			if err != nil {
+				t.Fatalf("Communication() = _, %v want _, <nil>", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Communication() had diffs (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestCommunicationErrors(t *testing.T) {
+	good := func() *AirportCommunicationPrimaryRecord {
+		return &AirportCommunicationPrimaryRecord{
+			CommunicationType:      "APP",
+			CommunicationFrequency: "1348500",
+			FrequencyUnits:         "V",
+			Sectorization:          "000180",
+			CommunicationAltitude1: NullAltitude{Altitude: Altitude{Feet: 8000}, Valid: true},
+		}
+	}
+	for _, tt := range []struct {
+		name   string
+		modify func(r *AirportCommunicationPrimaryRecord)
+	}{
+		{
+			name:   "Frequency",
+			modify: func(r *AirportCommunicationPrimaryRecord) { r.CommunicationFrequency = "134850" },
+		},
+		{
+			name:   "FrequencyUnits",
+			modify: func(r *AirportCommunicationPrimaryRecord) { r.FrequencyUnits = "" },
+		},
+		{
+			name:   "Sectorization",
+			modify: func(r *AirportCommunicationPrimaryRecord) { r.Sectorization = "000400" },
+		},
+		{
+			name:   "Altitude",
+			modify: func(r *AirportCommunicationPrimaryRecord) { r.AltitudeDescription = "Q" },
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := good()
+			if _, err := r.Communication(); err != nil {
+				t.Fatalf("Communication() before modification = _, %v want _, <nil>", err)
+			}
+			tt.modify(r)
+			if _, err := r.Communication(); err == nil {
+				t.Errorf("Communication() = _, <nil> want _, <non-nil>")
+			}
+		})
+	}
+}
+
+func TestCommunicationDirectory(t *testing.T) {
+	d := NewCommunicationDirectory()
+	for _, record := range []string{testTowerRecord, testApproachRecord, testApproachContRecord} {
+		rec, err := Parse([]byte(record))
+		if err != nil {
+			t.Fatalf("Parse() = %v want <nil>", err)
+		}
+		switch rec := rec.(type) {
+		case *AirportCommunicationPrimaryRecord:
+			err = d.Add(rec)
+		case *AirportCommunicationContinuationRecord:
+			err = d.AddContinuation(rec)
+		default:
+			t.Fatalf("Parse() = %T want a communication record", rec)
+		}
+		if err != nil {
+			t.Fatalf("adding %q = %v want <nil>", record, err)
+		}
+	}
+	if diff := cmp.Diff([]string{"KHWD"}, d.Airports()); diff != "" {
+		t.Errorf("Airports() had diffs (-want +got): %s", diff)
+	}
+	var got []string
+	for _, c := range d.Airport("KHWD") {
+		got = append(got, c.CallSign)
+	}
+	if diff := cmp.Diff([]string{"HAYWARD TOWER", "NORCAL APPROACH"}, got); diff != "" {
+		t.Errorf("Airport(\"KHWD\") call signs had diffs (-want +got): %s", diff)
+	}
+	if diff := cmp.Diff([]string{"NORTH OF THE AIRPORT BELOW 8000"}, d.Airport("KHWD")[1].Narratives); diff != "" {
+		t.Errorf("Airport(\"KHWD\") narratives had diffs (-want +got): %s", diff)
+	}
+	if got := d.Airport("KOAK"); got != nil {
+		t.Errorf("Airport(\"KOAK\") = %v want nil", got)
+	}
+
+	orphan := &AirportCommunicationContinuationRecord{
+		AirportEnrouteRecord:   AirportEnrouteRecord{AirportID: "KHWD"},
+		CommunicationType:      "GND",
+		CommunicationFrequency: "1217000",
+		FrequencyUnits:         "V",
+	}
+	if err := d.AddContinuation(orphan); err == nil {
+		t.Errorf("AddContinuation() without a primary record = <nil> want <non-nil>")
+	}
+}
@@ -0,0 +1,296 @@
+package arinc
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strings"
+)
+
+// crc32QPolynomial is the CRC-32Q generator polynomial used for the FAS data
+// block, without its x^32 term.
+const crc32QPolynomial = 0x814141AB
+
+// FASDataBlockLength is the length in bytes of a FAS data block without its
+// CRC.
+const FASDataBlockLength = 36
+
+// CRCMismatchError is returned when the CRC published in a path point record
+// does not match the CRC of its FAS data block.
+type CRCMismatchError struct {
+	Published, Computed uint32
+}
+
+func (e *CRCMismatchError) Error() string {
+	return fmt.Sprintf("published CRC %08X does not match computed CRC %08X", e.Published, e.Computed)
+}
+
+// FASDataCRC returns the CRC-32Q remainder of the FAS data block, as it is
+// published in path point records. The block is sent least significant bit
+// first in each byte, and the CRC is computed over the bits in that order. The
+// remainder is sent after the block most significant bit first, so the
+// published CRC is the bytes as sent, which have the bits of each byte of the
+// remainder reversed.
+// See RTCA DO-229 Appendix D, Final Approach Segment Data Block
+func FASDataCRC(block []byte) uint32 {
+	return bits.Reverse32(bits.ReverseBytes32(crc32Q(block, true)))
+}
+
+// crc32Q returns the CRC-32Q remainder of data, with no initial value or
+// final exclusive or. If lsbFirst is true, the bits of each byte are taken
+// least significant bit first.
+func crc32Q(data []byte, lsbFirst bool) uint32 {
+	var crc uint32
+	for _, b := range data {
+		for i := 0; i < 8; i++ {
+			shift := uint(7 - i)
+			if lsbFirst {
+				shift = uint(i)
+			}
+			bit := uint32(b>>shift) & 1
+			feedback := crc>>31 ^ bit
+			crc <<= 1
+			if feedback == 1 {
+				crc ^= crc32QPolynomial
+			}
+		}
+	}
+	return crc
+}
+
+// fasWriter packs fields into a FAS data block, least significant bit first,
+// starting at the least significant bit of the first byte.
+type fasWriter struct {
+	block [FASDataBlockLength]byte
+	bit   uint
+}
+
+func (w *fasWriter) write(v uint32, bits uint) {
+	for i := uint(0); i < bits; i++ {
+		if v>>i&1 == 1 {
+			w.block[w.bit/8] |= 1 << (w.bit % 8)
+		}
+		w.bit++
+	}
+}
+
+// writeRange writes v as an unsigned field of the given number of bits. If v
+// does not fit in the field, an error naming the field is returned.
+func (w *fasWriter) writeRange(field string, v int64, bits uint) error {
+	if v < 0 || v >= 1<<bits {
+		return fmt.Errorf("%s: value %d does not fit in %d bits", field, v, bits)
+	}
+	w.write(uint32(v), bits)
+	return nil
+}
+
+// writeSigned writes v as a two's complement field of the given number of
+// bits. If v does not fit in the field, an error naming the field is
+// returned.
+func (w *fasWriter) writeSigned(field string, v int64, bits uint) error {
+	if v < -(1<<(bits-1)) || v >= 1<<(bits-1) {
+		return fmt.Errorf("%s: value %d does not fit in %d bits", field, v, bits)
+	}
+	w.write(uint32(v), bits)
+	return nil
+}
+
+// writeIdentifier writes the four character identifier as the low six bits
+// of each character in eight bits, padded with spaces. The characters are
+// written last character first.
+func (w *fasWriter) writeIdentifier(field, id string) error {
+	if len(id) > 4 {
+		return fmt.Errorf("%s: identifier %q is longer than 4 characters", field, id)
+	}
+	id += strings.Repeat(" ", 4-len(id))
+	for i := len(id) - 1; i >= 0; i-- {
+		c := id[i]
+		if c != ' ' && (c < '0' || c > '9') && (c < 'A' || c > 'Z') {
+			return fmt.Errorf("%s: invalid character %q in identifier %q", field, c, id)
+		}
+		w.write(uint32(c&0x3F), 8)
+	}
+	return nil
+}
+
+// FASDataBlock returns the FAS data block of the path point record without
+// its CRC, as it is broadcast to the aircraft. If any field is malformed or
+// out of range, an error naming the field is returned.
+// See RTCA DO-229 Appendix D, Final Approach Segment Data Block
+func (r *AirportPathPointPrimaryRecord) FASDataBlock() ([]byte, error) {
+	p, err := r.PathPoint()
+	if err != nil {
+		return nil, err
+	}
+	w := &fasWriter{}
+	if err := w.writeRange("OperationType", int64(p.OperationType), 4); err != nil {
+		return nil, err
+	}
+	if err := w.writeRange("SBASServiceProviderID", int64(p.SBASProvider), 4); err != nil {
+		return nil, err
+	}
+	if err := w.writeIdentifier("AirportID", p.AirportID); err != nil {
+		return nil, err
+	}
+	number, letter, err := fasRunway(p.RunwayID)
+	if err != nil {
+		return nil, fmt.Errorf("RunwayOrHelipadID: %v", err)
+	}
+	w.write(number, 6)
+	w.write(letter, 2)
+	apd, err := parseDigits(r.ApproachPerformanceDesignator, 1)
+	if err != nil {
+		return nil, fmt.Errorf("ApproachPerformanceDesignator: %v", err)
+	}
+	if err := w.writeRange("ApproachPerformanceDesignator", int64(apd), 3); err != nil {
+		return nil, err
+	}
+	route, err := fasRouteIndicator(r.RouteIndicator)
+	if err != nil {
+		return nil, fmt.Errorf("RouteIndicator: %v", err)
+	}
+	w.write(route, 5)
+	if err := w.writeRange("ReferencePathDataSelector", int64(p.ReferencePathDataSelector), 8); err != nil {
+		return nil, err
+	}
+	if err := w.writeIdentifier("ReferencePathID", p.ReferencePathID); err != nil {
+		return nil, err
+	}
+	// Positions are in units of 0.0005 arc seconds.
+	const unitsPerDegree = 3600 / 0.0005
+	if err := w.writeSigned("LTPLatitude", round(p.LTPLat*unitsPerDegree), 32); err != nil {
+		return nil, err
+	}
+	if err := w.writeSigned("LTPLongitude", round(p.LTPLon*unitsPerDegree), 32); err != nil {
+		return nil, err
+	}
+	if err := w.writeRange("LTPEllipsoidHeight", round((p.LTPEllipsoidHeight+512)*10), 16); err != nil {
+		return nil, err
+	}
+	if err := w.writeSigned("FPAPLatitude", round(p.FPAPLat*unitsPerDegree)-round(p.LTPLat*unitsPerDegree), 24); err != nil {
+		return nil, err
+	}
+	if err := w.writeSigned("FPAPLongitude", round(p.FPAPLon*unitsPerDegree)-round(p.LTPLon*unitsPerDegree), 24); err != nil {
+		return nil, err
+	}
+	// The TCH is in units of 0.1 feet, or 0.05 meters.
+	tchUnit, tchStep := uint32(0), 0.1
+	if p.TCHInMeters {
+		tchUnit, tchStep = 1, 0.05
+	}
+	if err := w.writeRange("PathPointTCH", round(p.TCH/tchStep), 15); err != nil {
+		return nil, err
+	}
+	w.write(tchUnit, 1)
+	if err := w.writeRange("GlidePathAngle", round(p.GlidePathAngle*100), 16); err != nil {
+		return nil, err
+	}
+	if err := w.writeRange("CourseWidthAtThreshold", round((p.CourseWidth-80)/0.25), 8); err != nil {
+		return nil, err
+	}
+	// A blank length offset is sent as all ones.
+	lengthOffset := int64(255)
+	if r.LengthOffset != "" {
+		lengthOffset = round(float64(p.LengthOffset) / 8)
+		if lengthOffset == 255 {
+			return nil, fmt.Errorf("LengthOffset: value %d is out of range", p.LengthOffset)
+		}
+	}
+	if err := w.writeRange("LengthOffset", lengthOffset, 8); err != nil {
+		return nil, err
+	}
+	if err := w.writeRange("HAL", round(p.HAL/0.2), 8); err != nil {
+		return nil, err
+	}
+	if err := w.writeRange("VAL", round(p.VAL/0.2), 8); err != nil {
+		return nil, err
+	}
+	return w.block[:], nil
+}
+
+// FASDataCRC returns the CRC of the FAS data block of the path point record.
+// If the block cannot be built, an error is returned.
+func (r *AirportPathPointPrimaryRecord) FASDataCRC() (uint32, error) {
+	block, err := r.FASDataBlock()
+	if err != nil {
+		return 0, err
+	}
+	return FASDataCRC(block), nil
+}
+
+// ValidateCRC checks the published CRC of the path point record against the
+// CRC of its FAS data block. If the CRC does not match, a *CRCMismatchError is
+// returned. If the record is malformed, another error is returned.
+func (r *AirportPathPointPrimaryRecord) ValidateCRC() error {
+	published, err := r.CRC()
+	if err != nil {
+		return err
+	}
+	computed, err := r.FASDataCRC()
+	if err != nil {
+		return err
+	}
+	if published != computed {
+		return &CRCMismatchError{Published: published, Computed: computed}
+	}
+	return nil
+}
+
+// UpdateCRC sets the published CRC of the path point record to the CRC of its
+// FAS data block. It is used after the record has been edited. If the record
+// is malformed, an error is returned and the record is not changed.
+func (r *AirportPathPointPrimaryRecord) UpdateCRC() error {
+	crc, err := r.FASDataCRC()
+	if err != nil {
+		return err
+	}
+	r.FASDataCRCRemainder = fmt.Sprintf("%08X", crc)
+	return nil
+}
+
+// fasRunway returns the runway number and the coded runway letter of the
+// runway identifier, such as "RW28L". Helipads have runway number zero and no
+// letter.
+func fasRunway(id string) (uint32, uint32, error) {
+	if !strings.HasPrefix(id, "RW") {
+		return 0, 0, nil
+	}
+	if len(id) < 4 || len(id) > 5 {
+		return 0, 0, fmt.Errorf("invalid runway %q", id)
+	}
+	number, err := parseDigits(id[2:4], 2)
+	if err != nil || number < 1 || number > 36 {
+		return 0, 0, fmt.Errorf("invalid runway %q", id)
+	}
+	var letter uint32
+	if len(id) == 5 {
+		switch id[4] {
+		case 'R':
+			letter = 1
+		case 'C':
+			letter = 2
+		case 'L':
+			letter = 3
+		default:
+			return 0, 0, fmt.Errorf("invalid runway %q", id)
+		}
+	}
+	return uint32(number), letter, nil
+}
+
+// fasRouteIndicator returns the coded route indicator, which is zero for a
+// blank indicator and from 1 to 26 for the letters A to Z.
+func fasRouteIndicator(route string) (uint32, error) {
+	switch {
+	case route == "":
+		return 0, nil
+	case len(route) == 1 && route[0] >= 'A' && route[0] <= 'Z':
+		return uint32(route[0]-'A') + 1, nil
+	}
+	return 0, fmt.Errorf("invalid route indicator %q", route)
+}
+
+// round returns v rounded to the nearest integer.
+func round(v float64) int64 {
+	return int64(math.Round(v))
+}
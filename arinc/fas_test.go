@@ -0,0 +1,209 @@
+package arinc
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCRC32Q(t *testing.T) {
+	// The check value of CRC-32Q is the CRC of "123456789" taken most
+	// significant bit first.
+	if got, want := crc32Q([]byte("123456789"), false), uint32(0x3010BF7F); got != want {
+		t.Errorf("crc32Q(%q) = %08X want %08X", "123456789", got, want)
+	}
+}
+
+func TestFASDataCRCRemainder(t *testing.T) {
+	block, err := hex.DecodeString("000417080BDC080101383217D0B9291060F296CB7614B025EE00000026022C016801C8FA")
+	if err != nil {
+		t.Fatalf("hex.DecodeString() = _, %v want _, <nil>", err)
+	}
+	crc := FASDataCRC(block)
+	// The published CRC is the bytes sent after the block, and the CRC of
+	// the block followed by them is zero.
+	block = append(block, byte(crc>>24), byte(crc>>16), byte(crc>>8), byte(crc))
+	if got := FASDataCRC(block); got != 0 {
+		t.Errorf("FASDataCRC() of block with CRC %08X = %08X want 0", crc, got)
+	}
+}
+
+func TestValidateCRCPublished(t *testing.T) {
+	// Path point records from the FAA CIFP, whose CRCs were computed by the
+	// procedure designer.
+	for _, tt := range []struct {
+		name   string
+		record string
+	}{
+		{
+			name:   "KHWDRunway28L",
+			record: "SUSAP KHWDK2PR28L  RW28L001 0000W28A0N3739186640W12206531315-001720310N3740030660W12208304530106751224000350F40050040227B2E108911212",
+		},
+		{
+			name:   "KBURRunway08",
+			record: "SUSAP KBURK2PR08-Z RW08 001Z0000W08A0N3411524790W11822089145+018740300N3411510215W11820215105106750984000600F40000097C8DB7B365481903",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := Parse([]byte(tt.record))
+			if err != nil {
+				t.Fatalf("Parse() = %v want <nil>", err)
+			}
+			if err := rec.(*AirportPathPointPrimaryRecord).ValidateCRC(); err != nil {
+				t.Errorf("ValidateCRC() = %v want <nil>", err)
+			}
+		})
+	}
+}
+
+func TestFASDataBlock(t *testing.T) {
+	// Each want is the block in hexadecimal with a space between the bytes of
+	// different fields, and fields that share a byte kept together.
+	for _, tt := range []struct {
+		name   string
+		modify func(r *AirportPathPointPrimaryRecord)
+		want   string
+	}{
+		{
+			name:   "Runway",
+			modify: func(r *AirportPathPointPrimaryRecord) {},
+			want:   "00 0417080B DC 08 01 01383217 D0B92910 60F296CB 7614 B025EE 000000 2602 2C01 68 01 C8 FA",
+		},
+		{
+			name: "Helipad",
+			modify: func(r *AirportPathPointPrimaryRecord) {
+				r.RunwayOrHelipadID = "H1"
+				r.RouteIndicator = ""
+				r.LengthOffset = ""
+				r.VAL = ""
+			},
+			want: "00 0417080B 00 00 01 01383217 D0B92910 60F296CB 7614 B025EE 000000 2602 2C01 68 FF C8 00",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := Parse([]byte(testPathPointRecord))
+			if err != nil {
+				t.Fatalf("Parse() = %v want <nil>", err)
+			}
+			r := rec.(*AirportPathPointPrimaryRecord)
+			tt.modify(r)
+			got, err := r.FASDataBlock()
+			if err != nil {
+				t.Fatalf("FASDataBlock() = _, %v want _, <nil>", err)
+			}
+			if got, want := hex.EncodeToString(got), strings.ToLower(strings.Replace(tt.want, " ", "", -1)); got != want {
+				t.Errorf("FASDataBlock() = %s want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestFASDataBlockErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		modify func(r *AirportPathPointPrimaryRecord)
+	}{
+		{
+			name:   "PathPoint",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.LTPLatitude = "" },
+		},
+		{
+			name:   "RunwayNumber",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.RunwayOrHelipadID = "RW40" },
+		},
+		{
+			name:   "RunwayLetter",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.RunwayOrHelipadID = "RW28X" },
+		},
+		{
+			name:   "ApproachPerformanceDesignator",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.ApproachPerformanceDesignator = "8" },
+		},
+		{
+			name:   "RouteIndicator",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.RouteIndicator = "1" },
+		},
+		{
+			name:   "ReferencePathID",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.ReferencePathID = "W28-" },
+		},
+		{
+			name:   "FPAPLatitude",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.FPAPLatitude = "N3930000000" },
+		},
+		{
+			name:   "PathPointTCH",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.PathPointTCH = "999999" },
+		},
+		{
+			name:   "CourseWidthAtThreshold",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.CourseWidthAtThreshold = "07000" },
+		},
+		{
+			name:   "LengthOffset",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.LengthOffset = "2040" },
+		},
+		{
+			name:   "HAL",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.HAL = "600" },
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := Parse([]byte(testPathPointRecord))
+			if err != nil {
+				t.Fatalf("Parse() = %v want <nil>", err)
+			}
+			r := rec.(*AirportPathPointPrimaryRecord)
+			tt.modify(r)
+			if got, err := r.FASDataBlock(); err == nil {
+				t.Errorf("FASDataBlock() = %X, <nil> want _, <non-nil>", got)
+			}
+		})
+	}
+}
+
+func TestValidateCRC(t *testing.T) {
+	rec, err := Parse([]byte(testPathPointRecord))
+	if err != nil {
+		t.Fatalf("Parse() = %v want <nil>", err)
+	}
+	r := rec.(*AirportPathPointPrimaryRecord)
+	if err := r.ValidateCRC(); err != nil {
+		t.Fatalf("ValidateCRC() = %v want <nil>", err)
+	}
+
+	// Editing the path point invalidates the CRC until it is updated.
+	r.GlidePathAngle = "0290"
+	err = r.ValidateCRC()
+	var mismatch *CRCMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("ValidateCRC() after edit = %v want *CRCMismatchError", err)
+	}
+	if mismatch.Published != 0xB0B82C38 || mismatch.Computed == mismatch.Published {
+		t.Errorf("ValidateCRC() after edit = %v want published CRC B0B82C38 and a different computed CRC", mismatch)
+	}
+	if err := r.UpdateCRC(); err != nil {
+		t.Fatalf("UpdateCRC() = %v want <nil>", err)
+	}
+	if got, want := r.FASDataCRCRemainder, fmt.Sprintf("%08X", mismatch.Computed); got != want {
+		t.Errorf("UpdateCRC() set FASDataCRCRemainder = %q want %q", got, want)
+	}
+	if err := r.ValidateCRC(); err != nil {
+		t.Errorf("ValidateCRC() after UpdateCRC() = %v want <nil>", err)
+	}
+
+	// Malformed records cannot be validated or updated.
+	r.HAL = ""
+	if err := r.ValidateCRC(); err == nil || errors.As(err, &mismatch) {
+		t.Errorf("ValidateCRC() of malformed record = %v want non-mismatch error", err)
+	}
+	crc := r.FASDataCRCRemainder
+	if err := r.UpdateCRC(); err == nil {
+		t.Errorf("UpdateCRC() of malformed record = <nil> want <non-nil>")
+	}
+	if r.FASDataCRCRemainder != crc {
+		t.Errorf("UpdateCRC() of malformed record changed FASDataCRCRemainder to %q want %q", r.FASDataCRCRemainder, crc)
+	}
+}
@@ -0,0 +1,59 @@
+package arinc
+
+import "fmt"
+
+// GLS is a decoded GBAS landing system approach.
+type GLS struct {
+	AirportID       string
+	ReferencePathID string
+	RunwayID        string
+	StationID       string
+	Category        string
+	// Channel is the five digit channel number that selects the approach,
+	// from 20001 to 39999.
+	Channel int
+	// ApproachBearing is the published bearing of the final approach course.
+	ApproachBearing Bearing
+	// Station is the position of the ground station.
+	Station Coordinate
+	// GlidePathAngle is in degrees.
+	GlidePathAngle float64
+	// MagVar is the magnetic variation at the station.
+	MagVar MagVar
+}
+
+// GLS decodes the fields of the GLS record. If any field is malformed, an
+// error naming the field is returned.
+func (r *AirportGLSRecord) GLS() (*GLS, error) {
+	g := &GLS{
+		AirportID:       r.AirportID,
+		ReferencePathID: r.GLSReferencePathID,
+		RunwayID:        r.RunwayIdentifier,
+		StationID:       r.GLSStationID,
+		Category:        r.GLSCategory,
+		ApproachBearing: r.GLSApproachBearing,
+		Station:         r.Station,
+		MagVar:          r.MagneticVar,
+	}
+	var err error
+	if g.Channel, err = parseDigits(r.GLSChannel, 5); err != nil || g.Channel < 20001 || g.Channel > 39999 {
+		return nil, fmt.Errorf("GLSChannel: invalid channel %q", r.GLSChannel)
+	}
+	angle, err := parseDigits(r.GlidePathAngle, 3)
+	if err != nil || angle == 0 {
+		return nil, fmt.Errorf("GlidePathAngle: invalid glide path angle %q", r.GlidePathAngle)
+	}
+	g.GlidePathAngle = float64(angle) / 100
+	return g, nil
+}
+
+// TrueApproachBearing returns the true bearing of the final approach course in
+// degrees in the range [0, 360). If a magnetic bearing cannot be converted
+// with the magnetic variation of the station, an error is returned.
+func (g *GLS) TrueApproachBearing() (float64, error) {
+	b, err := g.ApproachBearing.True(g.MagVar)
+	if err != nil {
+		return 0, err
+	}
+	return b.Value, nil
+}
@@ -0,0 +1,110 @@
+package arinc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testGLSRecord = "SUSAP KHWDK2TG28L1   021473RW28L                   2950N37393214W122071825G28L   20AB300E0150    00052NARLG                123472002"
+
+func TestGLS(t *testing.T) {
+	rec, err := Parse([]byte(testGLSRecord))
+	if err != nil {
+		t.Fatalf("Parse() = %v want <nil>", err)
+	}
+	got, err := rec.(*AirportGLSRecord).GLS()
+	if err != nil {
+		t.Fatalf("GLS() = _, %v want _, <nil>", err)
+	}
+	want := &GLS{
+		AirportID:       "KHWD",
+		ReferencePathID: "G28L",
+		RunwayID:        "RW28L",
+		StationID:       "G28L",
+		Category:        "1",
+		Channel:         21473,
+		ApproachBearing: Bearing{Value: 295},
+		Station:         got.Station,
+		GlidePathAngle:  3,
+		MagVar:          MagVar{Value: -15},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GLS() had diffs (-want +got): %s", diff)
+	}
+	if got, err := got.TrueApproachBearing(); err != nil || got != 310.0 {
+		t.Errorf("TrueApproachBearing() = %f, %v want %f, <nil>", got, err, 310.0)
+	}
+}
+
+func TestGLSTrueBearing(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		gls     *GLS
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "Magnetic",
+			gls:  &GLS{ApproachBearing: Bearing{Value: 5}, MagVar: MagVar{Value: 15}},
+			want: 350,
+		},
+		{
+			name: "True",
+			gls:  &GLS{ApproachBearing: Bearing{Value: 295, Reference: TrueNorth}, MagVar: MagVar{Value: 15}},
+			want: 295,
+		},
+		{
+			name:    "MagneticWhereBearingsAreTrue",
+			gls:     &GLS{ApproachBearing: Bearing{Value: 295}, MagVar: MagVar{Reference: TrueNorth}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.gls.TrueApproachBearing()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("TrueApproachBearing() = _, <nil> want _, <non-nil>")
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("TrueApproachBearing() = %f, %v want %f, <nil>", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGLSErrors(t *testing.T) {
+	good := func() *AirportGLSRecord {
+		rec, err := Parse([]byte(testGLSRecord))
+		if err != nil {
+			t.Fatalf("Parse() = %v want <nil>", err)
+		}
+		return rec.(*AirportGLSRecord)
+	}
+	for _, tt := range []struct {
+		name   string
+		modify func(r *AirportGLSRecord)
+	}{
+		{
+			name:   "GLSChannel",
+			modify: func(r *AirportGLSRecord) { r.GLSChannel = "10000" },
+		},
+		{
+			name:   "GlidePathAngle",
+			modify: func(r *AirportGLSRecord) { r.GlidePathAngle = "000" },
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := good()
+			if _, err := r.GLS(); err != nil {
+				t.Fatalf("GLS() before modification = _, %v want _, <nil>", err)
+			}
+			tt.modify(r)
+			if _, err := r.GLS(); err == nil {
+				t.Errorf("GLS() = _, <nil> want _, <non-nil>")
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package arinc
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RegionCodeEnroute is the region code of enroute holding patterns.
+const RegionCodeEnroute = "ENRT"
+
+// HoldingPattern is a decoded holding pattern. Numerical fields are zero if
+// they are blank in the record.
+type HoldingPattern struct {
+	// RegionCode is the airport identifier for terminal holding patterns, and
+	// RegionCodeEnroute for enroute ones.
+	RegionCode string
+	FixID      string
+	// InboundCourse is the course of the inbound leg to the fix.
+	InboundCourse Bearing
+	// TurnDirection is TurnLeft or TurnRight.
+	TurnDirection TurnDirection
+	// LegLength is the length of the inbound leg in nautical miles.
+	LegLength float64
+	// LegTime is the time of the inbound leg.
+	LegTime         time.Duration
+	MinimumAltitude Altitude
+	MaximumAltitude Altitude
+	// Speed is the maximum holding speed in knots.
+	Speed int
+}
+
+// IsEnroute returns true if the holding pattern is an enroute holding
+// pattern.
+func (h *HoldingPattern) IsEnroute() bool {
+	return h.RegionCode == RegionCodeEnroute
+}
+
+// HoldingPattern decodes the fields of the holding pattern record. If any
+// field is malformed, an error naming the field is returned.
+func (r *HoldingPatternRecord) HoldingPattern() (*HoldingPattern, error) {
+	h := &HoldingPattern{
+		RegionCode:      r.RegionCode,
+		FixID:           r.FixID,
+		InboundCourse:   r.InboundHoldingCourse,
+		MinimumAltitude: r.MinimumAltitude.Altitude,
+		MaximumAltitude: r.MaximumAltitude.Altitude,
+	}
+	switch h.TurnDirection = TurnDirection(r.TurnDirection); h.TurnDirection {
+	case TurnLeft, TurnRight:
+	default:
+		return nil, fmt.Errorf("TurnDirection: invalid holding turn direction %q", r.TurnDirection)
+	}
+	if r.LegLength != "" {
+		n, err := strconv.Atoi(r.LegLength)
+		if err != nil || n < 0 || len(r.LegLength) != 3 {
+			return nil, fmt.Errorf("LegLength: invalid leg length %q", r.LegLength)
+		}
+		h.LegLength = float64(n) / 10
+	}
+	if r.LegTime != "" {
+		n, err := strconv.Atoi(r.LegTime)
+		if err != nil || n < 0 || len(r.LegTime) != 2 {
+			return nil, fmt.Errorf("LegTime: invalid leg time %q", r.LegTime)
+		}
+		h.LegTime = time.Duration(n) * time.Minute / 10
+	}
+	if r.HoldingSpeed != "" {
+		n, err := strconv.Atoi(r.HoldingSpeed)
+		if err != nil || n <= 0 || len(r.HoldingSpeed) != 3 {
+			return nil, fmt.Errorf("HoldingSpeed: invalid holding speed %q", r.HoldingSpeed)
+		}
+		h.Speed = n
+	}
+	return h, nil
+}
@@ -0,0 +1,101 @@
+package arinc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHoldingPattern(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		record string
+		want   *HoldingPattern
+	}{
+		{
+			name:   "EnrouteLegTime",
+			record: "SUSAEPENRTK2                 SUNOLK2EA01150R   100500017000230                                    SUNOL                    459892002",
+			want: &HoldingPattern{
+				RegionCode:      "ENRT",
+				FixID:           "SUNOL",
+				InboundCourse:   Bearing{Value: 115},
+				TurnDirection:   TurnRight,
+				LegTime:         time.Minute,
+				MinimumAltitude: Altitude{Feet: 5000},
+				MaximumAltitude: Altitude{Feet: 17000},
+				Speed:           230,
+			},
+		},
+		{
+			name:   "TerminalLegLength",
+			record: "SUSAEPKHWDK2                 BOGREK2PC02880L040  03000                                            BOGRE                    459892002",
+			want: &HoldingPattern{
+				RegionCode:      "KHWD",
+				FixID:           "BOGRE",
+				InboundCourse:   Bearing{Value: 288},
+				TurnDirection:   TurnLeft,
+				LegLength:       4,
+				MinimumAltitude: Altitude{Feet: 3000},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := Parse([]byte(tt.record))
+			if err != nil {
+				t.Fatalf("Parse() = %v want <nil>", err)
+			}
+			got, err := rec.(*HoldingPatternRecord).HoldingPattern()
+			if err != nil {
+				t.Fatalf("HoldingPattern() = _, %v want _, <nil>", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("HoldingPattern() had diffs (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestHoldingPatternErrors(t *testing.T) {
+	good := func() *HoldingPatternRecord {
+		return &HoldingPatternRecord{
+			RegionCode:           "ENRT",
+			FixID:                "SUNOL",
+			InboundHoldingCourse: Bearing{Value: 115},
+			TurnDirection:        "R",
+			LegTime:              "10",
+		}
+	}
+	for _, tt := range []struct {
+		name   string
+		modify func(r *HoldingPatternRecord)
+	}{
+		{
+			name:   "TurnDirection",
+			modify: func(r *HoldingPatternRecord) { r.TurnDirection = "E" },
+		},
+		{
+			name:   "LegLength",
+			modify: func(r *HoldingPatternRecord) { r.LegLength = "4.0" },
+		},
+		{
+			name:   "LegTime",
+			modify: func(r *HoldingPatternRecord) { r.LegTime = "1" },
+		},
+		{
+			name:   "HoldingSpeed",
+			modify: func(r *HoldingPatternRecord) { r.HoldingSpeed = "000" },
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := good()
+			if _, err := r.HoldingPattern(); err != nil {
+				t.Fatalf("HoldingPattern() before modification = _, %v want _, <nil>", err)
+			}
+			tt.modify(r)
+			if _, err := r.HoldingPattern(); err == nil {
+				t.Errorf("HoldingPattern() = _, <nil> want _, <non-nil>")
+			}
+		})
+	}
+}
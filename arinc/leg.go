@@ -0,0 +1,345 @@
+package arinc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PathTerminator is the path and termination of a procedure leg.
+// See 5.21 Path and Termination
+type PathTerminator string
+
+const (
+	PathTerminatorIF PathTerminator = "IF" // Initial fix
+	PathTerminatorTF PathTerminator = "TF" // Track to a fix
+	PathTerminatorCF PathTerminator = "CF" // Course to a fix
+	PathTerminatorDF PathTerminator = "DF" // Direct to a fix
+	PathTerminatorFA PathTerminator = "FA" // Fix to an altitude
+	PathTerminatorFC PathTerminator = "FC" // Track from a fix for a distance
+	PathTerminatorFD PathTerminator = "FD" // Track from a fix to a DME distance
+	PathTerminatorFM PathTerminator = "FM" // From a fix to a manual termination
+	PathTerminatorCA PathTerminator = "CA" // Course to an altitude
+	PathTerminatorCD PathTerminator = "CD" // Course to a DME distance
+	PathTerminatorCI PathTerminator = "CI" // Course to an intercept
+	PathTerminatorCR PathTerminator = "CR" // Course to a radial termination
+	PathTerminatorRF PathTerminator = "RF" // Constant radius arc
+	PathTerminatorAF PathTerminator = "AF" // Arc to a fix
+	PathTerminatorVA PathTerminator = "VA" // Heading to an altitude
+	PathTerminatorVD PathTerminator = "VD" // Heading to a DME distance
+	PathTerminatorVI PathTerminator = "VI" // Heading to an intercept
+	PathTerminatorVM PathTerminator = "VM" // Heading to a manual termination
+	PathTerminatorVR PathTerminator = "VR" // Heading to a radial termination
+	PathTerminatorPI PathTerminator = "PI" // Procedure turn
+	PathTerminatorHA PathTerminator = "HA" // Holding to an altitude
+	PathTerminatorHF PathTerminator = "HF" // Holding to a fix
+	PathTerminatorHM PathTerminator = "HM" // Holding to a manual termination
+)
+
+var pathTerminators = map[PathTerminator]bool{
+	PathTerminatorIF: true, PathTerminatorTF: true, PathTerminatorCF: true,
+	PathTerminatorDF: true, PathTerminatorFA: true, PathTerminatorFC: true,
+	PathTerminatorFD: true, PathTerminatorFM: true, PathTerminatorCA: true,
+	PathTerminatorCD: true, PathTerminatorCI: true, PathTerminatorCR: true,
+	PathTerminatorRF: true, PathTerminatorAF: true, PathTerminatorVA: true,
+	PathTerminatorVD: true, PathTerminatorVI: true, PathTerminatorVM: true,
+	PathTerminatorVR: true, PathTerminatorPI: true, PathTerminatorHA: true,
+	PathTerminatorHF: true, PathTerminatorHM: true,
+}
+
+// ParsePathTerminator returns the path terminator of the provided string. If
+// it is not one of the 23 path terminators, an error is returned.
+func ParsePathTerminator(s string) (PathTerminator, error) {
+	pt := PathTerminator(s)
+	if !pathTerminators[pt] {
+		return "", fmt.Errorf("invalid path terminator %q", s)
+	}
+	return pt, nil
+}
+
+// IsHolding returns true if the leg is a holding pattern.
+func (pt PathTerminator) IsHolding() bool {
+	return pt == PathTerminatorHA || pt == PathTerminatorHF || pt == PathTerminatorHM
+}
+
+// TurnDirection is the direction of a turn onto a procedure leg.
+// See 5.20 Turn Direction
+type TurnDirection string
+
+const (
+	TurnNone   TurnDirection = ""
+	TurnLeft   TurnDirection = "L"
+	TurnRight  TurnDirection = "R"
+	TurnEither TurnDirection = "E"
+)
+
+// ParseTurnDirection returns the turn direction of the provided string. If
+// it is not a valid turn direction, an error is returned.
+func ParseTurnDirection(s string) (TurnDirection, error) {
+	switch td := TurnDirection(strings.TrimSpace(s)); td {
+	case TurnNone, TurnLeft, TurnRight, TurnEither:
+		return td, nil
+	}
+	return "", fmt.Errorf("invalid turn direction %q", s)
+}
+
+// ConstraintType is the type of an altitude or speed constraint.
+type ConstraintType int
+
+const (
+	// ConstraintNone means that there is no constraint.
+	ConstraintNone ConstraintType = iota
+	// ConstraintAt means that the value must be equal to the constraint.
+	ConstraintAt
+	// ConstraintAtOrAbove means that the value must be at or above the lower
+	// bound.
+	ConstraintAtOrAbove
+	// ConstraintAtOrBelow means that the value must be at or below the upper
+	// bound.
+	ConstraintAtOrBelow
+	// ConstraintBetween means that the value must be between the lower and
+	// upper bounds, inclusive.
+	ConstraintBetween
+)
+
+// String returns the name of the constraint type.
+func (c ConstraintType) String() string {
+	switch c {
+	case ConstraintNone:
+		return "none"
+	case ConstraintAt:
+		return "at"
+	case ConstraintAtOrAbove:
+		return "at or above"
+	case ConstraintAtOrBelow:
+		return "at or below"
+	case ConstraintBetween:
+		return "between"
+	}
+	return fmt.Sprintf("ConstraintType(%d)", c)
+}
+
+// AltitudeConstraint is the altitude constraint of a procedure leg. Lower is
+// set for ConstraintAt, ConstraintAtOrAbove, and ConstraintBetween, and Upper
+// is set for ConstraintAt, ConstraintAtOrBelow, and ConstraintBetween.
+type AltitudeConstraint struct {
+	Type  ConstraintType
+	Lower Altitude
+	Upper Altitude
+	// Secondary is the glide slope or step down altitude for the altitude
+	// descriptions that have one, and zero otherwise.
+	Secondary Altitude
+}
+
+// ParseAltitudeConstraint returns the altitude constraint of the provided
+// altitude description and the two altitude fields of a procedure record.
+// See 5.29 Altitude Description
+func ParseAltitudeConstraint(description, altitude1, altitude2 string) (AltitudeConstraint, error) {
+	var alt1, alt2 NullAltitude
+	if err := alt1.UnmarshalText([]byte(strings.TrimSpace(altitude1))); err != nil {
+		return AltitudeConstraint{}, err
+	}
+	if err := alt2.UnmarshalText([]byte(strings.TrimSpace(altitude2))); err != nil {
+		return AltitudeConstraint{}, err
+	}
+	return NewAltitudeConstraint(description, alt1, alt2)
+}
+
+// NewAltitudeConstraint returns the altitude constraint of the provided
+// altitude description and the two decoded altitude fields of a procedure
+// record.
+func NewAltitudeConstraint(description string, altitude1, altitude2 NullAltitude) (AltitudeConstraint, error) {
+	alt1, alt2 := altitude1.Altitude, altitude2.Altitude
+	if !altitude1.Valid && !altitude2.Valid {
+		if strings.TrimSpace(description) != "" {
+			return AltitudeConstraint{}, fmt.Errorf("altitude description %q has no altitude", description)
+		}
+		return AltitudeConstraint{}, nil
+	}
+
+	switch strings.TrimSpace(description) {
+	case "", "@":
+		return AltitudeConstraint{Type: ConstraintAt, Lower: alt1, Upper: alt1}, nil
+	case "+":
+		return AltitudeConstraint{Type: ConstraintAtOrAbove, Lower: alt1}, nil
+	case "-":
+		return AltitudeConstraint{Type: ConstraintAtOrBelow, Upper: alt1}, nil
+	case "B":
+		return AltitudeConstraint{Type: ConstraintBetween, Lower: alt2, Upper: alt1}, nil
+	case "C":
+		return AltitudeConstraint{Type: ConstraintAtOrAbove, Lower: alt2}, nil
+	case "G", "I", "X":
+		return AltitudeConstraint{Type: ConstraintAt, Lower: alt1, Upper: alt1, Secondary: alt2}, nil
+	case "H", "J", "V":
+		return AltitudeConstraint{Type: ConstraintAtOrAbove, Lower: alt1, Secondary: alt2}, nil
+	case "Y":
+		return AltitudeConstraint{Type: ConstraintAtOrBelow, Upper: alt1, Secondary: alt2}, nil
+	}
+	return AltitudeConstraint{}, fmt.Errorf("invalid altitude description %q", description)
+}
+
+// SpeedConstraint is the speed limit of a procedure leg.
+type SpeedConstraint struct {
+	// Type is ConstraintNone, ConstraintAt, ConstraintAtOrAbove, or
+	// ConstraintAtOrBelow.
+	Type  ConstraintType
+	Knots int
+}
+
+// ParseSpeedConstraint returns the speed constraint of the provided speed
+// limit and speed limit description. A blank description is a mandatory
+// speed.
+// See 5.72 Speed Limit and 5.261 Speed Limit Description
+func ParseSpeedConstraint(speedLimit, description string) (SpeedConstraint, error) {
+	if strings.TrimSpace(speedLimit) == "" {
+		if strings.TrimSpace(description) != "" {
+			return SpeedConstraint{}, fmt.Errorf("speed limit description %q has no speed limit", description)
+		}
+		return SpeedConstraint{}, nil
+	}
+	knots, err := strconv.Atoi(speedLimit)
+	if err != nil || len(speedLimit) != 3 || knots <= 0 {
+		return SpeedConstraint{}, fmt.Errorf("invalid speed limit %q", speedLimit)
+	}
+	var t ConstraintType
+	switch strings.TrimSpace(description) {
+	case "", "@":
+		t = ConstraintAt
+	case "+":
+		t = ConstraintAtOrAbove
+	case "-":
+		t = ConstraintAtOrBelow
+	default:
+		return SpeedConstraint{}, fmt.Errorf("invalid speed limit description %q", description)
+	}
+	return SpeedConstraint{Type: t, Knots: knots}, nil
+}
+
+// ParseVerticalAngle returns the vertical angle in degrees of the provided
+// four character string, where the last three characters are hundredths of a
+// degree. Descending angles are negative.
+// Example: ParseVerticalAngle("-300") = -3.0
+// See 5.70 Vertical Angle
+func ParseVerticalAngle(s string) (float64, error) {
+	if len(s) != 4 || (s[0] != '-' && s[0] != '+' && s[0] != ' ') {
+		return 0, fmt.Errorf("invalid vertical angle %q", s)
+	}
+	hundredths, err := strconv.Atoi(s[1:])
+	if err != nil || hundredths < 0 {
+		return 0, fmt.Errorf("invalid vertical angle %q", s)
+	}
+	angle := float64(hundredths) / 100
+	if s[0] == '-' {
+		angle = -angle
+	}
+	return angle, nil
+}
+
+// parseTenths returns the value of a string of digits with an implied decimal
+// point before the last digit.
+func parseTenths(s, name string) (float64, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || len(s) != 4 {
+		return 0, fmt.Errorf("invalid %s %q", name, s)
+	}
+	return float64(n) / 10, nil
+}
+
+// ParseDistanceOrTime returns the distance in nautical miles or the time of
+// the provided four character string. Times start with a "T" and are in
+// minutes and tenths of a minute, and distances are in tenths of a nautical
+// mile. Exactly one of the return values is non-zero for valid, non-zero
+// values.
+// See 5.27 Route Distance From, Holding Distance/Time
+func ParseDistanceOrTime(s string) (distance float64, _ time.Duration, _ error) {
+	if strings.HasPrefix(s, "T") {
+		minutes, err := parseTenths("0"+s[1:], "holding time")
+		if err != nil {
+			return 0, 0, err
+		}
+		return 0, time.Duration(minutes * float64(time.Minute)), nil
+	}
+	distance, err := parseTenths(s, "distance")
+	if err != nil {
+		return 0, 0, err
+	}
+	return distance, 0, nil
+}
+
+// Leg is a decoded procedure leg. Numerical fields are zero if they are blank
+// in the record.
+type Leg struct {
+	FixID             string
+	PathTerminator    PathTerminator
+	TurnDirection     TurnDirection
+	RecommendedNavaid string
+	// ArcRadius is the radius of an RF leg in nautical miles.
+	ArcRadius float64
+	// Theta is the magnetic bearing from the recommended navaid to the fix in
+	// degrees.
+	Theta float64
+	// Rho is the distance from the recommended navaid to the fix in
+	// nautical miles.
+	Rho float64
+	// Course is the course of the leg.
+	Course Bearing
+	// Distance is the length of the leg or holding leg in nautical miles.
+	Distance float64
+	// Time is the length of a holding leg, if it is specified as a time.
+	Time          time.Duration
+	Altitude      AltitudeConstraint
+	Speed         SpeedConstraint
+	VerticalAngle float64
+}
+
+// Leg decodes the fields of the procedure record. If any field is malformed,
+// an error naming the field is returned.
+func (p *AirportProcedurePrimaryRecord) Leg() (*Leg, error) {
+	leg := &Leg{
+		FixID:             p.FixID,
+		RecommendedNavaid: p.RecommendedNavaid,
+	}
+	var err error
+	if leg.PathTerminator, err = ParsePathTerminator(p.PathAndTermination); err != nil {
+		return nil, fmt.Errorf("PathAndTermination: %v", err)
+	}
+	if leg.TurnDirection, err = ParseTurnDirection(p.TurnDirection); err != nil {
+		return nil, fmt.Errorf("TurnDirection: %v", err)
+	}
+	if p.ArcRadius != "" {
+		r, err := strconv.Atoi(p.ArcRadius)
+		if err != nil || r < 0 || len(p.ArcRadius) != 6 {
+			return nil, fmt.Errorf("ArcRadius: invalid arc radius %q", p.ArcRadius)
+		}
+		leg.ArcRadius = float64(r) / 1000
+	}
+	if p.Theta != "" {
+		if leg.Theta, err = parseTenths(p.Theta, "theta"); err != nil {
+			return nil, fmt.Errorf("Theta: %v", err)
+		}
+	}
+	if p.Rho != "" {
+		if leg.Rho, err = parseTenths(p.Rho, "rho"); err != nil {
+			return nil, fmt.Errorf("Rho: %v", err)
+		}
+	}
+	leg.Course = p.MagneticCourse.Bearing
+	if p.RouteOrHoldingDistanceOrTime != "" {
+		if leg.Distance, leg.Time, err = ParseDistanceOrTime(p.RouteOrHoldingDistanceOrTime); err != nil {
+			return nil, fmt.Errorf("RouteOrHoldingDistanceOrTime: %v", err)
+		}
+	}
+	if leg.Altitude, err = NewAltitudeConstraint(p.AltitudeDescription, p.Altitude1, p.Altitude2); err != nil {
+		return nil, fmt.Errorf("Altitude: %v", err)
+	}
+	if leg.Speed, err = ParseSpeedConstraint(p.SpeedLimit, p.SpeedLimitDescription); err != nil {
+		return nil, fmt.Errorf("SpeedLimit: %v", err)
+	}
+	if p.VerticalAngle != "" {
+		if leg.VerticalAngle, err = ParseVerticalAngle(p.VerticalAngle); err != nil {
+			return nil, fmt.Errorf("VerticalAngle: %v", err)
+		}
+	}
+	return leg, nil
+}
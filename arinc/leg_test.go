@@ -0,0 +1,411 @@
+package arinc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsePathTerminator(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		s       string
+		want    PathTerminator
+		wantErr bool
+	}{
+		{
+			name: "TrackToFix",
+			s:    "TF",
+			want: PathTerminatorTF,
+		},
+		{
+			name: "HoldingToManualTermination",
+			s:    "HM",
+			want: PathTerminatorHM,
+		},
+		{
+			name:    "Invalid",
+			s:       "XX",
+			wantErr: true,
+		},
+		{
+			name:    "Blank",
+			s:       "",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePathTerminator(tt.s)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParsePathTerminator(%q) = _, <nil> want _, <non-nil>", tt.s)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePathTerminator(%q) = _, %v want _, <nil>", tt.s, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePathTerminator(%q) = %q, _ want %q, _", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAltitudeConstraint(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		description string
+		altitude1   string
+		altitude2   string
+		want        AltitudeConstraint
+		wantErr     bool
+	}{
+		{
+			name: "None",
+		},
+		{
+			name:      "At",
+			altitude1: "03700",
+			want:      AltitudeConstraint{Type: ConstraintAt, Lower: Altitude{Feet: 3700}, Upper: Altitude{Feet: 3700}},
+		},
+		{
+			name:        "AtExplicit",
+			description: "@",
+			altitude1:   "05000",
+			want:        AltitudeConstraint{Type: ConstraintAt, Lower: Altitude{Feet: 5000}, Upper: Altitude{Feet: 5000}},
+		},
+		{
+			name:        "AtOrAbove",
+			description: "+",
+			altitude1:   "02500",
+			want:        AltitudeConstraint{Type: ConstraintAtOrAbove, Lower: Altitude{Feet: 2500}},
+		},
+		{
+			name:        "AtOrBelowFlightLevel",
+			description: "-",
+			altitude1:   "FL200",
+			want:        AltitudeConstraint{Type: ConstraintAtOrBelow, Upper: Altitude{Feet: 20000, Reference: FlightLevel}},
+		},
+		{
+			name:        "Between",
+			description: "B",
+			altitude1:   "FL240",
+			altitude2:   "17000",
+			want: AltitudeConstraint{
+				Type:  ConstraintBetween,
+				Lower: Altitude{Feet: 17000},
+				Upper: Altitude{Feet: 24000, Reference: FlightLevel},
+			},
+		},
+		{
+			name:        "GlideSlopeIntercept",
+			description: "J",
+			altitude1:   "02100",
+			altitude2:   "02126",
+			want: AltitudeConstraint{
+				Type:      ConstraintAtOrAbove,
+				Lower:     Altitude{Feet: 2100},
+				Secondary: Altitude{Feet: 2126},
+			},
+		},
+		{
+			name:        "InvalidDescription",
+			description: "Z",
+			altitude1:   "02100",
+			wantErr:     true,
+		},
+		{
+			name:        "DescriptionWithoutAltitude",
+			description: "+",
+			wantErr:     true,
+		},
+		{
+			name:      "InvalidAltitude",
+			altitude1: "0210A",
+			wantErr:   true,
+		},
+		{
+			name:      "InvalidFlightLevel",
+			altitude1: "FLABC",
+			wantErr:   true,
+		},
+		{
+			name:      "InvalidLength",
+			altitude1: "2100",
+			wantErr:   true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAltitudeConstraint(tt.description, tt.altitude1, tt.altitude2)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseAltitudeConstraint(%q, %q, %q) = _, <nil> want _, <non-nil>", tt.description, tt.altitude1, tt.altitude2)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAltitudeConstraint(%q, %q, %q) = _, %v want _, <nil>", tt.description, tt.altitude1, tt.altitude2, err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseAltitudeConstraint(%q, %q, %q) had diffs (-want +got): %s", tt.description, tt.altitude1, tt.altitude2, diff)
+			}
+		})
+	}
+}
+
+func TestParseSpeedConstraint(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		speedLimit  string
+		description string
+		want        SpeedConstraint
+		wantErr     bool
+	}{
+		{
+			name: "None",
+		},
+		{
+			name:       "Mandatory",
+			speedLimit: "250",
+			want:       SpeedConstraint{Type: ConstraintAt, Knots: 250},
+		},
+		{
+			name:        "AtOrBelow",
+			speedLimit:  "280",
+			description: "-",
+			want:        SpeedConstraint{Type: ConstraintAtOrBelow, Knots: 280},
+		},
+		{
+			name:        "AtOrAbove",
+			speedLimit:  "210",
+			description: "+",
+			want:        SpeedConstraint{Type: ConstraintAtOrAbove, Knots: 210},
+		},
+		{
+			name:       "InvalidSpeed",
+			speedLimit: "2X0",
+			wantErr:    true,
+		},
+		{
+			name:        "InvalidDescription",
+			speedLimit:  "250",
+			description: "B",
+			wantErr:     true,
+		},
+		{
+			name:        "DescriptionWithoutSpeed",
+			description: "-",
+			wantErr:     true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSpeedConstraint(tt.speedLimit, tt.description)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseSpeedConstraint(%q, %q) = _, <nil> want _, <non-nil>", tt.speedLimit, tt.description)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSpeedConstraint(%q, %q) = _, %v want _, <nil>", tt.speedLimit, tt.description, err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseSpeedConstraint(%q, %q) had diffs (-want +got): %s", tt.speedLimit, tt.description, diff)
+			}
+		})
+	}
+}
+
+func TestParseVerticalAngle(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		s       string
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "Descending",
+			s:    "-344",
+			want: -3.44,
+		},
+		{
+			name: "Zero",
+			s:    " 000",
+			want: 0,
+		},
+		{
+			name:    "InvalidSign",
+			s:       "X300",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidDigits",
+			s:       "-3A0",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidLength",
+			s:       "-30",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVerticalAngle(tt.s)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseVerticalAngle(%q) = _, <nil> want _, <non-nil>", tt.s)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseVerticalAngle(%q) = _, %v want _, <nil>", tt.s, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVerticalAngle(%q) = %f, _ want %f, _", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDistanceOrTime(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		s            string
+		wantDistance float64
+		wantTime     time.Duration
+		wantErr      bool
+	}{
+		{
+			name:         "Distance",
+			s:            "0053",
+			wantDistance: 5.3,
+		},
+		{
+			name:     "Time",
+			s:        "T010",
+			wantTime: time.Minute,
+		},
+		{
+			name:     "TimeWithTenths",
+			s:        "T015",
+			wantTime: 90 * time.Second,
+		},
+		{
+			name:    "InvalidDistance",
+			s:       "00A3",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidTime",
+			s:       "T0A0",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidLength",
+			s:       "053",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDistance, gotTime, err := ParseDistanceOrTime(tt.s)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseDistanceOrTime(%q) = _, _, <nil> want _, _, <non-nil>", tt.s)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDistanceOrTime(%q) = _, _, %v want _, _, <nil>", tt.s, err)
+			}
+			if gotDistance != tt.wantDistance || gotTime != tt.wantTime {
+				t.Errorf("ParseDistanceOrTime(%q) = %f, %v, _ want %f, %v, _", tt.s, gotDistance, gotTime, tt.wantDistance, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestLeg(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		record  string
+		want    *Leg
+		wantErr bool
+	}{
+		{
+			name:   "FinalApproachFix",
+			record: "SUSAP KHWDK2FL28L  L      020FERNEK2PC0E  F    CF IHWDK2      1079007428800053PI  + 02500                 OAK   K2D 0 DS   108521310",
+			want: &Leg{
+				FixID:             "FERNE",
+				PathTerminator:    PathTerminatorCF,
+				RecommendedNavaid: "IHWD",
+				Theta:             107.9,
+				Rho:               7.4,
+				Course:            Bearing{Value: 288.0},
+				Distance:          5.3,
+				Altitude:          AltitudeConstraint{Type: ConstraintAtOrAbove, Lower: Altitude{Feet: 2500}},
+			},
+		},
+		{
+			name:   "MissedApproachPoint",
+			record: "SUSAP KHWDK2FL28L  L      030RW28LK2PG0GY M    CF IHWDK2      1079000828800040PI    00105             -344          0 DS   108541212",
+			want: &Leg{
+				FixID:             "RW28L",
+				PathTerminator:    PathTerminatorCF,
+				RecommendedNavaid: "IHWD",
+				Theta:             107.9,
+				Rho:               0.8,
+				Course:            Bearing{Value: 288.0},
+				Distance:          4.0,
+				Altitude:          AltitudeConstraint{Type: ConstraintAt, Lower: Altitude{Feet: 105}, Upper: Altitude{Feet: 105}},
+				VerticalAngle:     -3.44,
+			},
+		},
+		{
+			name:   "STARWithSpeed",
+			record: "SUSAP KHWDK2ESHARR14MRLET 030BIFFYK2EA0E       TF                                   FL200          280                     108271707",
+			want: &Leg{
+				FixID:          "BIFFY",
+				PathTerminator: PathTerminatorTF,
+				Altitude:       AltitudeConstraint{Type: ConstraintAt, Lower: Altitude{Feet: 20000, Reference: FlightLevel}, Upper: Altitude{Feet: 20000, Reference: FlightLevel}},
+				Speed:          SpeedConstraint{Type: ConstraintAt, Knots: 280},
+			},
+		},
+		{
+			name:    "InvalidPathTerminator",
+			record:  "SUSAP KHWDK2ESHARR14MRLET 030BIFFYK2EA0E       XX                                   FL200          280                     108271707",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidTurnDirection",
+			record:  "SUSAP KHWDK2ESHARR14MRLET 030BIFFYK2EA0E   X   TF                                   FL200          280                     108271707",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidAltitudeDescription",
+			record:  "SUSAP KHWDK2ESHARR14MRLET 030BIFFYK2EA0E       TF                                 Q FL200          280                     108271707",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := Parse([]byte(tt.record))
+			if err != nil {
+				t.Fatalf("Parse() = %v want <nil>", err)
+			}
+			got, err := rec.(*AirportProcedurePrimaryRecord).Leg()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Leg() = _, <nil> want _, <non-nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Leg() = _, %v want _, <nil>", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Leg() had diffs (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package arinc
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	fixedwidth "github.com/ianlopshire/go-fixedwidth"
+)
+
+// RecordLength is the length of an ARINC 424 record in characters.
+const RecordLength = 132
+
+// Marshal returns the fixed width encoding of the record. Columns that are not
+// fields of the record type are copied from original, which is the line that
+// the record was parsed from, so that marshalling a record parsed by Parse
+// returns the line unchanged. If original is nil, those columns are blank and
+// the record is RecordLength characters long.
+func Marshal(rec TypedRecord, original []byte) ([]byte, error) {
+	enc, err := fixedwidth.Marshal(rec)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal %T: %v", rec, err)
+	}
+	cols := fieldColumns(reflect.TypeOf(rec))
+	var out []byte
+	if original == nil {
+		out = bytes.Repeat([]byte(" "), RecordLength)
+	} else {
+		out = append([]byte(nil), original...)
+	}
+	for i, ok := range cols {
+		if !ok || i >= len(enc) {
+			continue
+		}
+		if i >= len(out) {
+			// Fields past the end of a short original are only written if
+			// they have been set.
+			if enc[i] == ' ' {
+				continue
+			}
+			out = append(out, bytes.Repeat([]byte(" "), i-len(out)+1)...)
+		}
+		out[i] = enc[i]
+	}
+	return out, nil
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// columnCache holds the result of fieldColumns for each record type.
+var columnCache sync.Map
+
+// fieldColumns returns which zero based columns are written by the fields of
+// the struct type, following the fixed tags of embedded structs.
+func fieldColumns(t reflect.Type) []bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if cols, ok := columnCache.Load(t); ok {
+		return cols.([]bool)
+	}
+	var cols []bool
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		start, end, ok := parseFixedTag(f.Tag.Get("fixed"))
+		if !ok {
+			continue
+		}
+		for len(cols) < end {
+			cols = append(cols, false)
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && !ft.Implements(textMarshalerType) {
+			// A struct field is encoded by its own fields, and truncated to
+			// the columns of the field.
+			for j, ok := range fieldColumns(ft) {
+				if ok && start-1+j < end {
+					cols[start-1+j] = true
+				}
+			}
+			continue
+		}
+		for j := start - 1; j < end; j++ {
+			cols[j] = true
+		}
+	}
+	columnCache.Store(t, cols)
+	return cols
+}
+
+// parseFixedTag returns the one based, inclusive start and end columns of a
+// fixed struct tag such as "14,17,left".
+func parseFixedTag(tag string) (start, end int, ok bool) {
+	parts := strings.Split(tag, ",")
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	start, err1 := strconv.Atoi(parts[0])
+	end, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || start < 1 || end < start {
+		return 0, 0, false
+	}
+	return start, end, true
+}
@@ -0,0 +1,287 @@
+package arinc
+
+import (
+	"bytes"
+	"encoding"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// randomRecord returns a random record line with the section and subsection
+// codes, whose other columns are drawn mostly from the characters used in
+// ARINC data. Spaces are common so that fields have leading and trailing
+// blanks.
+func randomRecord(r *rand.Rand, section, subsection string) []byte {
+	const chars = "      ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789+-./"
+	line := make([]byte, RecordLength)
+	for i := range line {
+		line[i] = chars[r.Intn(len(chars))]
+	}
+	copy(line, "SUSA")
+	line[4] = section[0]
+	sub := byte(' ')
+	if subsection != "" {
+		sub = subsection[0]
+	}
+	if airportLikeSections[section] {
+		line[12] = sub
+	} else {
+		line[5] = sub
+	}
+	return line
+}
+
+// randomValues fills the columns of the value type fields of the record, a
+// pointer to a fixed width struct, with random valid encodings. Fields that may
+// be blank are blank a quarter of the time.
+func randomValues(t *testing.T, r *rand.Rand, line []byte, rec interface{}) {
+	t.Helper()
+	rt := reflect.TypeOf(rec).Elem()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		start, end, ok := parseFixedTag(f.Tag.Get("fixed"))
+		if !ok || !f.Type.Implements(textMarshalerType) {
+			continue
+		}
+		v := randomValue(r, f.Type)
+		if v == nil {
+			t.Fatalf("randomValue() of %v = <nil>", f.Type)
+		}
+		enc, err := v.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText(%v) = _, %v want _, <nil>", v, err)
+		}
+		copy(line[start-1:end], append(enc, bytes.Repeat([]byte(" "), end-start+1-len(enc))...))
+	}
+}
+
+// randomValue returns a random value of the value type t, or nil if t is not
+// a value type.
+func randomValue(r *rand.Rand, t reflect.Type) encoding.TextMarshaler {
+	blank := r.Intn(4) == 0
+	switch reflect.Zero(t).Interface().(type) {
+	case Coordinate:
+		return randomCoordinate(r)
+	case NullCoordinate:
+		if blank {
+			return NullCoordinate{}
+		}
+		return NullCoordinate{Coordinate: randomCoordinate(r), Valid: true}
+	case Bearing:
+		return randomBearing(r)
+	case NullBearing:
+		if blank {
+			return NullBearing{}
+		}
+		return NullBearing{Bearing: randomBearing(r), Valid: true}
+	case TrueBearing:
+		return TrueBearing{Bearing{Value: float64(r.Intn(36000)) / 100, Reference: TrueNorth}}
+	case MagVar:
+		return randomMagVar(r)
+	case NullMagVar:
+		if blank {
+			return NullMagVar{}
+		}
+		return NullMagVar{MagVar: randomMagVar(r), Valid: true}
+	case Altitude:
+		return randomAltitude(r)
+	case NullAltitude:
+		if blank {
+			return NullAltitude{}
+		}
+		return NullAltitude{Altitude: randomAltitude(r), Valid: true}
+	}
+	return nil
+}
+
+func randomCoordinate(r *rand.Rand) Coordinate {
+	const perDegree = 360000
+	return Coordinate{
+		Lat: float64(r.Int63n(180*perDegree+1)-90*perDegree) / perDegree,
+		Lon: float64(r.Int63n(360*perDegree+1)-180*perDegree) / perDegree,
+	}
+}
+
+func randomBearing(r *rand.Rand) Bearing {
+	switch r.Intn(4) {
+	case 0:
+		return Bearing{Value: float64(r.Intn(361)), Reference: TrueNorth}
+	case 1:
+		return Bearing{Value: 360}
+	}
+	return Bearing{Value: float64(r.Intn(3600)) / 10}
+}
+
+func randomMagVar(r *rand.Rand) MagVar {
+	switch r.Intn(8) {
+	case 0:
+		return MagVar{Reference: TrueNorth}
+	case 1:
+		return MagVar{Value: math.Copysign(0, -1)}
+	}
+	return MagVar{Value: float64(r.Intn(3601)-1800) / 10}
+}
+
+func randomAltitude(r *rand.Rand) Altitude {
+	if r.Intn(2) == 0 {
+		return Altitude{Feet: r.Intn(1000) * 100, Reference: FlightLevel}
+	}
+	return Altitude{Feet: r.Intn(109999) - 9999}
+}
+
+// blankUnmodelled returns a copy of the line with the columns that are not
+// fields of the record, a pointer to a fixed width struct, replaced by spaces.
+func blankUnmodelled(line []byte, rec interface{}) []byte {
+	cols := fieldColumns(reflect.TypeOf(rec))
+	out := append([]byte(nil), line...)
+	for i := range out {
+		if i >= len(cols) || !cols[i] {
+			out[i] = ' '
+		}
+	}
+	return out
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	type recordType struct {
+		section, subsection string
+		newRecord           func() TypedRecord
+	}
+	var types []recordType
+	for k, regs := range registry {
+		for _, reg := range regs {
+			types = append(types, recordType{k.section, k.subsection, reg.newRecord})
+		}
+	}
+	// Records of unknown types are parsed into the base record types.
+	types = append(types,
+		recordType{"R", "A", func() TypedRecord { return &Record{} }},
+		recordType{SectionCodeAirport, "Y", func() TypedRecord { return &AirportEnrouteRecord{} }},
+	)
+	// The corpus is generated in a fixed order so that it is the same on
+	// every run.
+	sort.Slice(types, func(i, j int) bool {
+		x, y := types[i], types[j]
+		if x.section != y.section {
+			return x.section < y.section
+		}
+		if x.subsection != y.subsection {
+			return x.subsection < y.subsection
+		}
+		return reflect.TypeOf(x.newRecord()).String() < reflect.TypeOf(y.newRecord()).String()
+	})
+
+	const recordsPerType = 200
+	r := rand.New(rand.NewSource(1))
+	for _, rt := range types {
+		want := reflect.TypeOf(rt.newRecord())
+		t.Run(rt.section+rt.subsection+want.Elem().Name(), func(t *testing.T) {
+			for n, attempts := 0, 0; n < recordsPerType; attempts++ {
+				if attempts > 1000*recordsPerType {
+					t.Fatalf("could not generate %d records of type %v, only %d", recordsPerType, want, n)
+				}
+				line := randomRecord(r, rt.section, rt.subsection)
+				if reflect.TypeOf(newRecord(line, rt.section, rt.subsection)) != want {
+					// The line is for a different record type in the same
+					// subsection, such as a continuation record.
+					continue
+				}
+				randomValues(t, r, line, rt.newRecord())
+				rec, err := Parse(line)
+				if err != nil {
+					t.Fatalf("Parse(%q) = %v want <nil>", line, err)
+				}
+				n++
+				got, err := Marshal(rec, line)
+				if err != nil {
+					t.Fatalf("Marshal(%q) = _, %v want _, <nil>", line, err)
+				}
+				if diff := cmp.Diff(string(line), string(got)); diff != "" {
+					t.Fatalf("Marshal() of parsed record had diffs (-want +got): %s", diff)
+				}
+				// Without the original line, the fields of the record alone
+				// must reproduce the columns that they cover.
+				got, err = Marshal(rec, nil)
+				if err != nil {
+					t.Fatalf("Marshal(%q, nil) = _, %v want _, <nil>", line, err)
+				}
+				if diff := cmp.Diff(string(blankUnmodelled(line, rec)), string(got)); diff != "" {
+					t.Fatalf("Marshal(nil) of parsed record had diffs (-want +got): %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	const record = "SUSAP KHWDK2IIHWD0   011150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212"
+	for _, tt := range []struct {
+		name     string
+		original string
+		modify   func(r *AirportLocGSPrimaryRecord)
+		want     string
+	}{
+		{
+			name:     "Unmodified",
+			original: record,
+			modify:   func(r *AirportLocGSPrimaryRecord) {},
+			want:     record,
+		},
+		{
+			name:     "Modified",
+			original: record,
+			modify:   func(r *AirportLocGSPrimaryRecord) { r.ContinuationRecordNumber = "1" },
+			want:     record[:21] + "1" + record[22:],
+		},
+		{
+			// Columns 111 to 123 are not fields of the record type.
+			name:     "Unmodelled",
+			original: record[:110] + "UNMODELLED XX" + record[123:],
+			modify:   func(r *AirportLocGSPrimaryRecord) {},
+			want:     record[:110] + "UNMODELLED XX" + record[123:],
+		},
+		{
+			name:     "NoOriginal",
+			original: record[:110] + "UNMODELLED XX" + record[123:],
+			modify:   func(r *AirportLocGSPrimaryRecord) {},
+			want:     record,
+		},
+		{
+			name:     "Short",
+			original: record[:60],
+			modify:   func(r *AirportLocGSPrimaryRecord) {},
+			want:     record[:60],
+		},
+		{
+			name:     "ShortModified",
+			original: record[:60],
+			modify:   func(r *AirportLocGSPrimaryRecord) { r.GlideSlopeAngle = "300" },
+			want:     record[:60] + "                           300",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := Parse([]byte(tt.original))
+			if err != nil {
+				t.Fatalf("Parse() = %v want <nil>", err)
+			}
+			loc := rec.(*AirportLocGSPrimaryRecord)
+			tt.modify(loc)
+			var original []byte
+			if tt.name != "NoOriginal" {
+				original = []byte(tt.original)
+			}
+			got, err := Marshal(loc, original)
+			if err != nil {
+				t.Fatalf("Marshal() = _, %v want _, <nil>", err)
+			}
+			if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+				t.Errorf("Marshal() had diffs (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -3,20 +3,38 @@ package arinc
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 const (
-	SectionCodeNavaid  string = "D"
-	SectionCodeEnroute string = "E"
-	SectionCodeAirport string = "P"
+	SectionCodeNavaid   string = "D"
+	SectionCodeEnroute  string = "E"
+	SectionCodeAirport  string = "P"
+	SectionCodeAirspace string = "U"
+	SectionCodeHeliport string = "H"
 
 	SubsectionCodeNavaidNDB         = "B"
 	SubsectionCodeNavaidVHF         = ""
 	SubsectionCodeAirportRefPoint   = "A"
 	SubsectionCodeEnrouteWaypoint   = "A"
+	SubsectionCodeEnrouteAirway     = "R"
+	SubsectionCodeEnrouteHolding    = "P"
 	SubsectionCodeTerminalWaypoint  = "C"
+	SubsectionCodeTerminalNDB       = "N"
+	SubsectionCodeLocalizerMarker   = "M"
+	SubsectionCodeSID               = "D"
+	SubsectionCodeSTAR              = "E"
 	SubsectionCodeApproachProcedure = "F"
+	SubsectionCodeRunway            = "G"
 	SubsectionCodeLocGS             = "I"
+	SubsectionCodeMSA               = "S"
+	SubsectionCodeTAA               = "K"
+	SubsectionCodeCommunication     = "V"
+	SubsectionCodeControlled        = "C"
+	SubsectionCodeRestrictive       = "R"
+	SubsectionCodeHelipad           = "H"
+	SubsectionCodePathPoint         = "P"
+	SubsectionCodeGLS               = "T"
 
 	ContinuationRecordSimulation  = "S"
 	LocalizerBearingSourceNotGovt = "N"
@@ -36,42 +54,76 @@ type Record struct {
 // VHFNavaidRecord is a record for a VHF navaid.
 type VHFNavaidRecord struct {
 	Record                   `fixed:"1,6,left"`
-	AirportICAOID            string `fixed:"7,10,left"`
-	ICAOCode1                string `fixed:"11,12,left"`
-	VORID                    string `fixed:"14,17,left"`
-	ICAOCode2                string `fixed:"20,21,left"`
-	ContinuationRecordNumber string `fixed:"22,22,left"`
-	VORFrequency             string `fixed:"23,27,left"`
-	NavaidClass              string `fixed:"28,32,left"`
-	VORLatitude              string `fixed:"33,41,left"`
-	VORLongitude             string `fixed:"42,51,left"`
-	DMEID                    string `fixed:"52,55,left"`
-	DMELatitude              string `fixed:"56,64,left"`
-	DMELongitude             string `fixed:"65,74,left"`
-	StationDeclination       string `fixed:"75,79,left"`
-	DMEElevation             string `fixed:"80,84,left"`
-	FigureOfMerit            string `fixed:"85,85,left"`
-	ILSDMEBias               string `fixed:"86,87,left"`
-	FrequencyProtection      string `fixed:"88,90,left"`
-	DatumCode                string `fixed:"91,93,left"`
-	VORName                  string `fixed:"94,123,left"`
+	AirportICAOID            string         `fixed:"7,10,left"`
+	ICAOCode1                string         `fixed:"11,12,left"`
+	VORID                    string         `fixed:"14,17,left"`
+	ICAOCode2                string         `fixed:"20,21,left"`
+	ContinuationRecordNumber string         `fixed:"22,22,left"`
+	VORFrequency             string         `fixed:"23,27,left"`
+	NavaidClass              string         `fixed:"28,32,left"`
+	VOR                      NullCoordinate `fixed:"33,51,left"`
+	DMEID                    string         `fixed:"52,55,left"`
+	DME                      NullCoordinate `fixed:"56,74,left"`
+	StationDeclination       NullMagVar     `fixed:"75,79,left"`
+	DMEElevation             string         `fixed:"80,84,left"`
+	FigureOfMerit            string         `fixed:"85,85,left"`
+	ILSDMEBias               string         `fixed:"86,87,left"`
+	FrequencyProtection      string         `fixed:"88,90,left"`
+	DatumCode                string         `fixed:"91,93,left"`
+	VORName                  string         `fixed:"94,123,left"`
 }
 
 // NDBNavaidRecord is a record for an NDB.
 type NDBNavaidRecord struct {
 	Record                   `fixed:"1,6,left"`
-	AirportID                string `fixed:"7,10,left"`
-	ICAOCode1                string `fixed:"11,12,left"`
-	NDBID                    string `fixed:"14,17,left"`
-	ICAOCode2                string `fixed:"20,21,left"`
-	ContinuationRecordNumber string `fixed:"22,22,left"`
-	NDBFrequency             string `fixed:"23,27,left"`
-	NDBClass                 string `fixed:"28,32,left"`
-	NDBLatitude              string `fixed:"33,41,left"`
-	NDBLongitude             string `fixed:"42,51,left"`
-	MagneticVar              string `fixed:"75,79,left"`
-	DatumCode                string `fixed:"91,93,left"`
-	NDBName                  string `fixed:"94,123,left"`
+	AirportID                string     `fixed:"7,10,left"`
+	ICAOCode1                string     `fixed:"11,12,left"`
+	NDBID                    string     `fixed:"14,17,left"`
+	ICAOCode2                string     `fixed:"20,21,left"`
+	ContinuationRecordNumber string     `fixed:"22,22,left"`
+	NDBFrequency             string     `fixed:"23,27,left"`
+	NDBClass                 string     `fixed:"28,32,left"`
+	NDB                      Coordinate `fixed:"33,51,left"`
+	MagneticVar              NullMagVar `fixed:"75,79,left"`
+	DatumCode                string     `fixed:"91,93,left"`
+	NDBName                  string     `fixed:"94,123,left"`
+}
+
+// TerminalNDBRecord is a record for an NDB that belongs to an airport, such as
+// a compass locator.
+// See 4.1.3.1 Airport and Heliport Terminal NDB Primary Records
+type TerminalNDBRecord struct {
+	AirportEnrouteRecord     `fixed:"1,13,left"`
+	NDBID                    string     `fixed:"14,17,left"`
+	ICAOCode2                string     `fixed:"20,21,left"`
+	ContinuationRecordNumber string     `fixed:"22,22,left"`
+	NDBFrequency             string     `fixed:"23,27,left"`
+	NDBClass                 string     `fixed:"28,32,left"`
+	NDB                      Coordinate `fixed:"33,51,left"`
+	MagneticVar              NullMagVar `fixed:"75,79,left"`
+	DatumCode                string     `fixed:"91,93,left"`
+	NDBName                  string     `fixed:"94,123,left"`
+}
+
+// AirportLocalizerMarkerRecord is a record for a marker beacon on a localizer
+// course. If a compass locator is collocated with the marker, such as at a
+// locator outer marker, the locator fields are set.
+// See 4.1.13.1 Airport and Heliport Localizer Marker Records
+type AirportLocalizerMarkerRecord struct {
+	AirportEnrouteRecord          `fixed:"1,13,left"`
+	LocalizerID                   string         `fixed:"14,17,left"`
+	MarkerType                    string         `fixed:"18,20,left"`
+	ContinuationRecordNumber      string         `fixed:"22,22,left"`
+	LocatorFrequency              string         `fixed:"23,27,left"`
+	RunwayIdentifier              string         `fixed:"28,32,left"`
+	Marker                        NullCoordinate `fixed:"33,51,left"`
+	MinorAxisBearing              NullBearing    `fixed:"52,55,left"`
+	Locator                       NullCoordinate `fixed:"56,74,left"`
+	LocatorClass                  string         `fixed:"75,79,left"`
+	LocatorFacilityCharacteristic string         `fixed:"80,84,left"`
+	LocatorID                     string         `fixed:"85,88,left"`
+	MagneticVar                   NullMagVar     `fixed:"91,95,left"`
+	FacilityElevation             string         `fixed:"98,102,left"`
 }
 
 // AirportEnrouteRecord is a record associated with an airport or enroute.
@@ -86,27 +138,61 @@ type AirportEnrouteRecord struct {
 // AirportPrimaryRecord is a record associated with an airport.
 type AirportPrimaryRecord struct {
 	AirportEnrouteRecord     `fixed:"1,13,left"`
-	AtaIataDesignator        string `fixed:"14,16,left"`
-	ContinuationRecordNumber string `fixed:"17,18,left"`
-	SpeedLimitAltitude       string `fixed:"23,27,left"`
-	LongestRunway            string `fixed:"28,30,left"`
-	IFRCapability            string `fixed:"31,31,left"`
-	LongestRunwaySurfaceCode string `fixed:"32,32,left"`
-	AirportRefPointLatitude  string `fixed:"33,41,left"`
-	AirportRefPointLongitude string `fixed:"42,51,left"`
-	MagneticVar              string `fixed:"52,56,left"`
-	AirportElevation         string `fixed:"57,61,left"`
-	SpeedLimit               string `fixed:"62,64,left"`
-	RecommendedNavaid        string `fixed:"65,68,left"`
-	ICAOCode                 string `fixed:"69,70,left"`
-	TransitionsAltitude      string `fixed:"71,75,left"`
-	TransitionLevel          string `fixed:"76,80,left"`
-	PublicMilitaryIndicator  string `fixed:"81,81,left"`
-	TimeZone                 string `fixed:"82,84,left"`
-	DaylightIndicator        string `fixed:"85,85,left"`
-	MagneticTrueIndicator    string `fixed:"86,86,left"`
-	DatumCode                string `fixed:"87,89,left"`
-	Name                     string `fixed:"94,123,left"`
+	AtaIataDesignator        string       `fixed:"14,16,left"`
+	ContinuationRecordNumber string       `fixed:"17,18,left"`
+	SpeedLimitAltitude       NullAltitude `fixed:"23,27,left"`
+	LongestRunway            string       `fixed:"28,30,left"`
+	IFRCapability            string       `fixed:"31,31,left"`
+	LongestRunwaySurfaceCode string       `fixed:"32,32,left"`
+	AirportRefPoint          Coordinate   `fixed:"33,51,left"`
+	MagneticVar              MagVar       `fixed:"52,56,left"`
+	AirportElevation         string       `fixed:"57,61,left"`
+	SpeedLimit               string       `fixed:"62,64,left"`
+	RecommendedNavaid        string       `fixed:"65,68,left"`
+	ICAOCode                 string       `fixed:"69,70,left"`
+	TransitionsAltitude      NullAltitude `fixed:"71,75,left"`
+	TransitionLevel          NullAltitude `fixed:"76,80,left"`
+	PublicMilitaryIndicator  string       `fixed:"81,81,left"`
+	TimeZone                 string       `fixed:"82,84,left"`
+	DaylightIndicator        string       `fixed:"85,85,left"`
+	MagneticTrueIndicator    string       `fixed:"86,86,left"`
+	DatumCode                string       `fixed:"87,89,left"`
+	Name                     string       `fixed:"94,123,left"`
+}
+
+// HeliportPrimaryRecord is a record associated with a heliport. Heliport
+// records have the same layout as the corresponding airport records, with the
+// heliport identifier in place of the airport identifier, so terminal
+// waypoints, procedures, MSAs, and communications at a heliport are parsed
+// into the airport record types.
+// See 4.2.1.1 Heliport Primary Records
+type HeliportPrimaryRecord struct {
+	AirportEnrouteRecord     `fixed:"1,13,left"`
+	AtaIataDesignator        string       `fixed:"14,16,left"`
+	PadIdentifier            string       `fixed:"17,21,left"`
+	ContinuationRecordNumber string       `fixed:"22,22,left"`
+	SpeedLimitAltitude       NullAltitude `fixed:"23,27,left"`
+	HeliportRefPoint         Coordinate   `fixed:"33,51,left"`
+	MagneticVar              MagVar       `fixed:"52,56,left"`
+	HeliportElevation        string       `fixed:"57,61,left"`
+	SpeedLimit               string       `fixed:"62,64,left"`
+	RecommendedNavaid        string       `fixed:"65,68,left"`
+	ICAOCode                 string       `fixed:"69,70,left"`
+	TransitionsAltitude      NullAltitude `fixed:"71,75,left"`
+	TransitionLevel          NullAltitude `fixed:"76,80,left"`
+	PublicMilitaryIndicator  string       `fixed:"81,81,left"`
+	TimeZone                 string       `fixed:"82,84,left"`
+	DaylightIndicator        string       `fixed:"85,85,left"`
+	Name                     string       `fixed:"94,123,left"`
+}
+
+// HelipadRecord is a record for a helipad at a heliport. The latitude and
+// longitude are those of the center of the pad.
+type HelipadRecord struct {
+	AirportEnrouteRecord     `fixed:"1,13,left"`
+	HelipadID                string     `fixed:"14,18,left"`
+	ContinuationRecordNumber string     `fixed:"22,22,left"`
+	Helipad                  Coordinate `fixed:"33,51,left"`
 }
 
 // ParseMagneticVar returns the magnetic variation as a floating
@@ -114,43 +200,92 @@ type AirportPrimaryRecord struct {
 // negative for east variation. If the magnetic variation indicates
 // that values are to be referenced to "true north", then the isTrue
 // boolean is set to true. If any error occurs, then values are undefined
-// and an error is returned.
+// and an error is returned. It is equivalent to ParseMagVar.
 func ParseMagneticVar(magVar string) (_ float64, isTrue bool, _ error) {
-	if len(magVar) != 5 {
-		return 0, false, fmt.Errorf("Could not parse magnetic variation, invalid length %d want 5.", len(magVar))
-	}
-	var eastWestFactor float64
-	switch magVar[0] {
-	case 'E':
-		eastWestFactor = -1.0
-	case 'W':
-		eastWestFactor = 1.0
-	case 'T':
-		return 0, true, nil
-	default:
-		return 0, false, fmt.Errorf("Could not parse magnetic variation, invalid direction indicator %q", magVar[0])
-	}
-	num, err := strconv.ParseFloat(magVar[1:5], 64)
-	if err != nil {
-		return 0, false, fmt.Errorf("Could not parse numerical portion of magnetic variation: %v", err)
-	}
-	return num * eastWestFactor / 10, false, nil
+	v, err := ParseMagVar(magVar)
+	return v.Value, v.Reference == TrueNorth, err
 }
 
 // WaypointPrimaryRecord is a record associated with a waypoint.
 type WaypointPrimaryRecord struct {
 	AirportEnrouteRecord     `fixed:"1,13,left"`
-	WaypointID               string `fixed:"14,18,left"`
-	ICAOCode                 string `fixed:"20,21,left"`
-	ContinuationRecordNumber string `fixed:"22,22,left"`
-	WaypointType             string `fixed:"27,29,left"`
-	WaypointUsage            string `fixed:"30,31,left"`
-	WaypointLatitude         string `fixed:"33,41,left"`
-	WaypointLongitude        string `fixed:"42,51,left"`
-	DynamicMagVar            string `fixed:"75,79,left"`
-	DatumCode                string `fixed:"85,87,left"`
-	NameFormatIndicator      string `fixed:"96,98,left"`
-	WaypointNameDesc         string `fixed:"99,123,left"`
+	WaypointID               string     `fixed:"14,18,left"`
+	ICAOCode                 string     `fixed:"20,21,left"`
+	ContinuationRecordNumber string     `fixed:"22,22,left"`
+	WaypointType             string     `fixed:"27,29,left"`
+	WaypointUsage            string     `fixed:"30,31,left"`
+	Waypoint                 Coordinate `fixed:"33,51,left"`
+	DynamicMagVar            NullMagVar `fixed:"75,79,left"`
+	DatumCode                string     `fixed:"85,87,left"`
+	NameFormatIndicator      string     `fixed:"96,98,left"`
+	WaypointNameDesc         string     `fixed:"99,123,left"`
+}
+
+// EnrouteAirwayRecord is a record for one fix on an enroute airway. The
+// course, distance, and altitude fields describe the segment from the fix to
+// the fix in the next record of the airway.
+// See 4.1.6.1 Enroute Airways Primary Records
+type EnrouteAirwayRecord struct {
+	Record                   `fixed:"1,6,left"`
+	RouteID                  string      `fixed:"14,18,left"`
+	SequenceNumber           string      `fixed:"26,29,left"`
+	FixID                    string      `fixed:"30,34,left"`
+	FixICAOCode              string      `fixed:"35,36,left"`
+	FixSectionCode           string      `fixed:"37,37,left"`
+	FixSubsectionCode        string      `fixed:"38,38,left"`
+	ContinuationRecordNumber string      `fixed:"39,39,left"`
+	WaypointDescriptionCode  string      `fixed:"40,43,left"`
+	BoundaryCode             string      `fixed:"44,44,left"`
+	RouteType                string      `fixed:"45,45,left"`
+	Level                    string      `fixed:"46,46,left"`
+	DirectionRestriction     string      `fixed:"47,47,left"`
+	CruiseTableIndicator     string      `fixed:"48,49,left"`
+	EUIndicator              string      `fixed:"50,50,left"`
+	RecommendedNavaid        string      `fixed:"51,54,left"`
+	RecommendedNavaidICAO    string      `fixed:"55,56,left"`
+	RNP                      string      `fixed:"57,59,left"`
+	Theta                    string      `fixed:"63,66,left"`
+	Rho                      string      `fixed:"67,70,left"`
+	OutboundMagneticCourse   NullBearing `fixed:"71,74,left"`
+	RouteDistanceFrom        string      `fixed:"75,78,left"`
+	InboundMagneticCourse    NullBearing `fixed:"79,82,left"`
+	MinimumAltitude          string      `fixed:"84,88,left"`
+	MinimumAltitude2         string      `fixed:"89,93,left"`
+	MaximumAltitude          string      `fixed:"94,98,left"`
+	FixRadiusTransition      string      `fixed:"99,101,left"`
+}
+
+// IsEndOfAirway returns true if the fix is the last one of a continuous
+// section of the airway, so there is no segment to the fix in the next record.
+func (r *EnrouteAirwayRecord) IsEndOfAirway() bool {
+	d := r.WaypointDescriptionCode
+	return len(d) >= 2 && d[1] == 'E'
+}
+
+// HoldingPatternRecord is a record for an enroute or terminal holding
+// pattern. The region code is the airport identifier for terminal holding
+// patterns, and "ENRT" for enroute ones.
+// See 4.1.5.1 Holding Pattern Primary Records
+type HoldingPatternRecord struct {
+	Record                   `fixed:"1,6,left"`
+	RegionCode               string       `fixed:"7,10,left"`
+	ICAOCode                 string       `fixed:"11,12,left"`
+	DuplicateIdentifier      string       `fixed:"28,29,left"`
+	FixID                    string       `fixed:"30,34,left"`
+	FixICAOCode              string       `fixed:"35,36,left"`
+	FixSectionCode           string       `fixed:"37,37,left"`
+	FixSubsectionCode        string       `fixed:"38,38,left"`
+	ContinuationRecordNumber string       `fixed:"39,39,left"`
+	InboundHoldingCourse     Bearing      `fixed:"40,43,left"`
+	TurnDirection            string       `fixed:"44,44,left"`
+	LegLength                string       `fixed:"45,47,left"`
+	LegTime                  string       `fixed:"48,49,left"`
+	MinimumAltitude          NullAltitude `fixed:"50,54,left"`
+	MaximumAltitude          NullAltitude `fixed:"55,59,left"`
+	HoldingSpeed             string       `fixed:"60,62,left"`
+	RNP                      string       `fixed:"63,65,left"`
+	ArcRadius                string       `fixed:"66,71,left"`
+	Name                     string       `fixed:"99,123,left"`
 }
 
 // parsePoint parses a latitude or longitude string into degrees, minutes, and
@@ -184,146 +319,277 @@ func parsePoint(point string) (int, int, float64, error) {
 	return dir * degrees, dir * minutes, float64(dir) * seconds, nil
 }
 
-// LatLon calculates the numerical latitude and longitude for the provided
-// latitude and longitude strings. If the data is invalid, an error is returned.
-func LatLon(latitude, longitude string) (float64, float64, error) {
-	latDeg, latMin, latSec, err := parsePoint(latitude)
+// ParseLatitude calculates the numerical latitude for the provided latitude
+// string. If the data is invalid, an error is returned.
+func ParseLatitude(latitude string) (float64, error) {
+	deg, min, sec, err := parsePoint(latitude)
 	if err != nil {
-		return 0.0, 0.0, fmt.Errorf("could not calculate latitude: %v", err)
+		return 0.0, fmt.Errorf("could not calculate latitude: %v", err)
 	}
-	lonDeg, lonMin, lonSec, err := parsePoint(longitude)
+	return float64(deg) + (float64(min) / 60.0) + (sec / 3600.0), nil
+}
+
+// ParseLongitude calculates the numerical longitude for the provided
+// longitude string. If the data is invalid, an error is returned.
+func ParseLongitude(longitude string) (float64, error) {
+	deg, min, sec, err := parsePoint(longitude)
 	if err != nil {
-		return 0.0, 0.0, fmt.Errorf("could not calculate longitude: %v", err)
+		return 0.0, fmt.Errorf("could not calculate longitude: %v", err)
 	}
-	lat := float64(latDeg) + (float64(latMin) / 60.0) + (latSec / 3600.0)
-	lon := float64(lonDeg) + (float64(lonMin) / 60.0) + (lonSec / 3600.0)
-	return lat, lon, nil
+	return float64(deg) + (float64(min) / 60.0) + (sec / 3600.0), nil
+}
+
+// LatLon calculates the numerical latitude and longitude for the provided
+// latitude and longitude strings. If the data is invalid, an error is returned.
+// It is equivalent to ParseCoordinate.
+func LatLon(latitude, longitude string) (float64, float64, error) {
+	c, err := ParseCoordinate(latitude, longitude)
+	return c.Lat, c.Lon, err
 }
 
 // ParseBearing returns the decimal bearing equivalent of the provided
 // string bearing. If the bearing is referenced to true north, then
 // isTrue is returned as true. If any error occurs, an error is returned.
+// It is equivalent to ParseBearingValue.
 func ParseBearing(bearing string) (_ float64, isTrue bool, _ error) {
-	if len(bearing) != 4 {
-		return 0, false, fmt.Errorf("Could not parse magnetic variation, invalid length %d want 4.", len(bearing))
-	}
-	if bearing[3] == 'T' {
-		num, err := strconv.ParseFloat(bearing[0:3], 64)
-		if err != nil {
-			return 0, false, fmt.Errorf("Could not parse bearing: %v", err)
-		}
-		return num, true, nil
-	}
-
-	num, err := strconv.ParseFloat(bearing[0:4], 64)
-	if err != nil {
-		return 0, false, fmt.Errorf("Could not parse bearing: %v", err)
-	}
-	return num / 10, false, nil
+	b, err := ParseBearingValue(bearing)
+	return b.Value, b.Reference == TrueNorth, err
 }
 
-// EncodeBearing encodes the specified bearing into a five character string,
-// where the decimal point is implied after the third character. If the provided
-// bearing is negative or greater than 360, then the output is undefined.
+// EncodeBearing encodes the specified true bearing into a five character
+// string, where the decimal point is implied after the third character. If the
+// bearing is out of range, an error is returned. It is equivalent to
+// Bearing.EncodeTrueBearing.
 // Example: EncodeBearing(190.123) = "19012"
-func EncodeBearing(bearing float64) string {
-	s := fmt.Sprintf("%06.2f", bearing)
-	return s[:3] + s[4:]
+func EncodeBearing(bearing float64) (string, error) {
+	return Bearing{Value: bearing, Reference: TrueNorth}.EncodeTrueBearing()
 }
 
 // AirportLocGSPrimaryRecord ia a record for a glideslope or localizer at an airport.
 // See 4.1.11.1 Airport and Heliport Localizer and Glide Slope Primary Records
 type AirportLocGSPrimaryRecord struct {
 	AirportEnrouteRecord             `fixed:"1,13,left"`
-	LocalizerID                      string `fixed:"14,17,left"`
-	ILSCategory                      string `fixed:"18,18,left"`
-	ContinuationRecordNumber         string `fixed:"22,22,left"`
-	LocalizerFrequency               string `fixed:"23,27,left"`
-	RunwayIdentifier                 string `fixed:"28,32,left"`
-	LocalizerLatitude                string `fixed:"33,41,left"`
-	LocalizerLongitude               string `fixed:"42,51,left"`
-	LocalizerBearing                 string `fixed:"52,55,left"`
-	GlideSlopeLatitude               string `fixed:"56,64,left"`
-	GlideSlopeLongitude              string `fixed:"65,74,left"`
-	LocalizerPosition                string `fixed:"75,78,left"`
-	LocalizerPositionReference       string `fixed:"79,79,left"`
-	GlideSlopePosition               string `fixed:"80,83,left"`
-	LocalizerWidth                   string `fixed:"84,87,left"`
-	GlideSlopeAngle                  string `fixed:"88,90,left"`
-	StationDeclination               string `fixed:"91,95,left"`
-	GlideSlopeHeightAtThreshold      string `fixed:"96,97,left"`
-	GlideSlopeElevation              string `fixed:"98,102,left"`
-	SupportingFacilityID             string `fixed:"103,106,left"`
-	SupportingFacilityICAOCode       string `fixed:"107,108,left"`
-	SupportingFacilitySectionCode    string `fixed:"109,109,left"`
-	SupportingFacilitySubsectionCode string `fixed:"110,110,left"`
-	Data                             string `fixed:"124,132,left"`
+	LocalizerID                      string         `fixed:"14,17,left"`
+	ILSCategory                      string         `fixed:"18,18,left"`
+	ContinuationRecordNumber         string         `fixed:"22,22,left"`
+	LocalizerFrequency               string         `fixed:"23,27,left"`
+	RunwayIdentifier                 string         `fixed:"28,32,left"`
+	Localizer                        Coordinate     `fixed:"33,51,left"`
+	LocalizerBearing                 Bearing        `fixed:"52,55,left"`
+	GlideSlope                       NullCoordinate `fixed:"56,74,left"`
+	LocalizerPosition                string         `fixed:"75,78,left"`
+	LocalizerPositionReference       string         `fixed:"79,79,left"`
+	GlideSlopePosition               string         `fixed:"80,83,left"`
+	LocalizerWidth                   string         `fixed:"84,87,left"`
+	GlideSlopeAngle                  string         `fixed:"88,90,left"`
+	StationDeclination               NullMagVar     `fixed:"91,95,left"`
+	GlideSlopeHeightAtThreshold      string         `fixed:"96,97,left"`
+	GlideSlopeElevation              string         `fixed:"98,102,left"`
+	SupportingFacilityID             string         `fixed:"103,106,left"`
+	SupportingFacilityICAOCode       string         `fixed:"107,108,left"`
+	SupportingFacilitySectionCode    string         `fixed:"109,109,left"`
+	SupportingFacilitySubsectionCode string         `fixed:"110,110,left"`
+	Data                             string         `fixed:"124,132,left"`
 }
 
 // AirportLocGSSimContinuationRecord is a continuation record for an AirportLocGSPrimaryRecord.
 // See 4.1.11.3 Airport and Heliport Localizer and Glide Slope Simulation Continuation Records
 type AirportLocGSSimContinuationRecord struct {
 	AirportEnrouteRecord     `fixed:"1,13,left"`
-	LocalizerID              string `fixed:"14,17,left"`
-	ILSCategory              string `fixed:"18,18,left"`
-	ContinuationRecordNumber string `fixed:"22,22,left"`
-	ApplicationType          string `fixed:"23,23,left"`
-	FacilityCharacteristics  string `fixed:"24,27,left"`
-	LocalizerTrueBearing     string `fixed:"52,56,left"`
-	LocalizerBearingSource   string `fixed:"57,57,left"`
-	GlideSlopeBeamWidth      string `fixed:"88,90,left"`
-	ApproachRouteIdent1      string `fixed:"91,96,left"`
-	ApproachRouteIdent2      string `fixed:"97,102,left"`
-	ApproachRouteIdent3      string `fixed:"103,108,left"`
-	ApproachRouteIdent4      string `fixed:"109,114,left"`
-	ApproachRouteIdent5      string `fixed:"115,120,left"`
-	Data                     string `fixed:"124,132,left"`
+	LocalizerID              string      `fixed:"14,17,left"`
+	ILSCategory              string      `fixed:"18,18,left"`
+	ContinuationRecordNumber string      `fixed:"22,22,left"`
+	ApplicationType          string      `fixed:"23,23,left"`
+	FacilityCharacteristics  string      `fixed:"24,27,left"`
+	LocalizerTrueBearing     TrueBearing `fixed:"52,56,left"`
+	LocalizerBearingSource   string      `fixed:"57,57,left"`
+	GlideSlopeBeamWidth      string      `fixed:"88,90,left"`
+	ApproachRouteIdent1      string      `fixed:"91,96,left"`
+	ApproachRouteIdent2      string      `fixed:"97,102,left"`
+	ApproachRouteIdent3      string      `fixed:"103,108,left"`
+	ApproachRouteIdent4      string      `fixed:"109,114,left"`
+	ApproachRouteIdent5      string      `fixed:"115,120,left"`
+	Data                     string      `fixed:"124,132,left"`
+}
+
+// AirportRunwayPrimaryRecord is a record for a runway at an airport. The
+// latitude and longitude are those of the landing threshold.
+// See 4.1.10.1 Airport Runway Primary Records
+type AirportRunwayPrimaryRecord struct {
+	AirportEnrouteRecord       `fixed:"1,13,left"`
+	RunwayID                   string     `fixed:"14,18,left"`
+	ContinuationRecordNumber   string     `fixed:"22,22,left"`
+	RunwayLength               string     `fixed:"23,27,left"`
+	RunwayMagneticBearing      Bearing    `fixed:"28,31,left"`
+	Runway                     Coordinate `fixed:"33,51,left"`
+	RunwayGradient             string     `fixed:"52,56,left"`
+	EllipsoidHeight            string     `fixed:"61,66,left"`
+	LandingThresholdElevation  string     `fixed:"67,71,left"`
+	DisplacedThresholdDistance string     `fixed:"72,75,left"`
+	ThresholdCrossingHeight    string     `fixed:"76,77,left"`
+	RunwayWidth                string     `fixed:"78,80,left"`
+	TCHValueIndicator          string     `fixed:"81,81,left"`
+	LocalizerID                string     `fixed:"82,85,left"`
+	LocalizerCategory          string     `fixed:"86,86,left"`
+	Stopway                    string     `fixed:"87,90,left"`
+	SecondLocalizerID          string     `fixed:"91,94,left"`
+	SecondLocalizerCategory    string     `fixed:"95,95,left"`
+	RunwayDescription          string     `fixed:"102,123,left"`
+}
+
+// ParseRunwayID returns the number and designator of the provided runway
+// identifier. The designator is "L", "R", "C", or empty if the runway has
+// none. For example, "RW28L" is runway 28 with designator "L". If the
+// identifier is not a valid runway identifier, an error is returned.
+func ParseRunwayID(runwayID string) (number int, designator string, _ error) {
+	if len(runwayID) < 4 || len(runwayID) > 5 || !strings.HasPrefix(runwayID, "RW") {
+		return 0, "", fmt.Errorf("invalid runway identifier %q", runwayID)
+	}
+	number, err := strconv.Atoi(runwayID[2:4])
+	if err != nil || number < 1 || number > 36 {
+		return 0, "", fmt.Errorf("invalid runway number in identifier %q", runwayID)
+	}
+	if len(runwayID) == 5 {
+		switch runwayID[4] {
+		case 'L', 'R', 'C':
+			designator = runwayID[4:]
+		case ' ':
+		default:
+			return 0, "", fmt.Errorf("invalid runway designator in identifier %q", runwayID)
+		}
+	}
+	return number, designator, nil
+}
+
+// EncodeRunwayID encodes the runway number and designator into a runway
+// identifier. If the number is not between 1 and 36, then the output is
+// undefined.
+// Example: EncodeRunwayID(28, "L") = "RW28L"
+func EncodeRunwayID(number int, designator string) string {
+	return fmt.Sprintf("RW%02d%s", number, designator)
+}
+
+// OppositeRunwayID returns the identifier of the runway at the opposite end of
+// the provided runway. For example, the opposite of "RW28L" is "RW10R". If the
+// identifier is not a valid runway identifier, an error is returned.
+func OppositeRunwayID(runwayID string) (string, error) {
+	num, designator, err := ParseRunwayID(runwayID)
+	if err != nil {
+		return "", err
+	}
+	switch designator {
+	case "L":
+		designator = "R"
+	case "R":
+		designator = "L"
+	}
+	return EncodeRunwayID((num+18-1)%36+1, designator), nil
+}
+
+// ParseRunwayLength returns the runway length in feet of the provided five
+// character string. If any error occurs, an error is returned.
+func ParseRunwayLength(length string) (int, error) {
+	if len(length) != 5 {
+		return 0, fmt.Errorf("invalid runway length %q, want 5 characters", length)
+	}
+	feet, err := strconv.Atoi(length)
+	if err != nil || feet < 0 {
+		return 0, fmt.Errorf("invalid runway length %q", length)
+	}
+	return feet, nil
+}
+
+// EncodeRunwayLength encodes the runway length in feet into a five character
+// string. If the length is negative or greater than 99999, then the output is
+// undefined.
+// Example: EncodeRunwayLength(3107) = "03107"
+func EncodeRunwayLength(feet int) string {
+	return fmt.Sprintf("%05d", feet)
+}
+
+// EncodeRunwayBearing encodes the runway bearing into the four character
+// string that ParseBearing accepts. Magnetic bearings are encoded in tenths of
+// a degree, and true bearings are encoded in whole degrees followed by a "T".
+// If the provided bearing is negative or greater than 360, then the output is
+// undefined. It is equivalent to Bearing.MarshalText.
+// Example: EncodeRunwayBearing(284.0, false) = "2840"
+func EncodeRunwayBearing(bearing float64, isTrue bool) string {
+	b := Bearing{Value: bearing}
+	if isTrue {
+		b.Reference = TrueNorth
+	}
+	text, _ := b.MarshalText()
+	return string(text)
+}
+
+// ParseElevation returns the elevation in feet of the provided five character
+// string, such as a landing threshold or airport elevation. Elevations below
+// sea level start with a "-". If any error occurs, an error is returned.
+func ParseElevation(elevation string) (int, error) {
+	if len(elevation) != 5 {
+		return 0, fmt.Errorf("invalid elevation %q, want 5 characters", elevation)
+	}
+	feet, err := strconv.Atoi(elevation)
+	if err != nil {
+		return 0, fmt.Errorf("invalid elevation %q", elevation)
+	}
+	return feet, nil
+}
+
+// EncodeElevation encodes the elevation in feet into a five character string.
+// If the elevation is less than -9999 or greater than 99999, then the output
+// is undefined.
+// Example: EncodeElevation(-24) = "-0024"
+func EncodeElevation(feet int) string {
+	return fmt.Sprintf("%05d", feet)
 }
 
 // AirportProcedurePrimaryRecord is a record for a SID, STAR, or approach procedure
-// at an airport.
+// at an airport. The subsection code is SubsectionCodeSID,
+// SubsectionCodeSTAR, or SubsectionCodeApproachProcedure, and determines how
+// the RouteType is interpreted.
 // See 4.1.9.1 Airport SID/STAR/Approach Primary Records
 type AirportProcedurePrimaryRecord struct {
 	AirportEnrouteRecord         `fixed:"1,13,left"`
-	ProcedureID                  string `fixed:"14,19,left"`
-	RouteType                    string `fixed:"20,20,left"`
-	TransitionID                 string `fixed:"21,25,left"`
-	SequenceNumber               string `fixed:"27,29,left"`
-	FixID                        string `fixed:"30,34,left"`
-	ProcedureICAOCode            string `fixed:"35,36,left"`
-	ProcedureSectionCode         string `fixed:"37,37,left"`
-	ProcedureSubsectionCode      string `fixed:"38,38,left"`
-	ContinuationRecordNumber     string `fixed:"39,39,left"`
-	WaypointDescriptionCode      string `fixed:"40,43,left"`
-	TurnDirection                string `fixed:"44,44,left"`
-	RNP                          string `fixed:"45,47,left"`
-	PathAndTermination           string `fixed:"48,49,left"`
-	TurnDirectionValid           string `fixed:"50,50,left"`
-	RecommendedNavaid            string `fixed:"51,54,left"`
-	RecommendedNavaidICAOCode    string `fixed:"55,56,left"`
-	ArcRadius                    string `fixed:"57,62,left"`
-	Theta                        string `fixed:"63,66,left"`
-	Rho                          string `fixed:"67,70,left"`
-	MagneticCourse               string `fixed:"71,74,left"`
-	RouteOrHoldingDistanceOrTime string `fixed:"75,78,left"`
-	RecommendedNavSection        string `fixed:"79,79,left"`
-	RecommendedNavSubsection     string `fixed:"80,80,left"`
-	AltitudeDescription          string `fixed:"83,83,left"`
-	ATCIndicator                 string `fixed:"84,84,left"`
-	Altitude1                    string `fixed:"85,89,left"`
-	Altitude2                    string `fixed:"90,94,left"`
-	TransitionAltitude           string `fixed:"95,99,left"`
-	SpeedLimit                   string `fixed:"100,102,left"`
-	VerticalAngle                string `fixed:"103,106,left"`
-	CenterFixOrTAASectorID       string `fixed:"107,111,left"`
-	MultipleCodeOrTAASectorID    string `fixed:"112,112,left"`
-	CenterFixICAOCode            string `fixed:"113,114,left"`
-	CenterFixSectionCode         string `fixed:"115,115,left"`
-	CenterFixSubsectionCode      string `fixed:"116,116,left"`
-	GpsFmsIndication             string `fixed:"117,117,left"`
-	SpeedLimitDescription        string `fixed:"118,118,left"`
-	ApproachRouteQualifier1      string `fixed:"119,119,left"`
-	ApproachRouteQualifier2      string `fixed:"120,120,left"`
+	ProcedureID                  string       `fixed:"14,19,left"`
+	RouteType                    string       `fixed:"20,20,left"`
+	TransitionID                 string       `fixed:"21,25,left"`
+	SequenceNumber               string       `fixed:"27,29,left"`
+	FixID                        string       `fixed:"30,34,left"`
+	ProcedureICAOCode            string       `fixed:"35,36,left"`
+	ProcedureSectionCode         string       `fixed:"37,37,left"`
+	ProcedureSubsectionCode      string       `fixed:"38,38,left"`
+	ContinuationRecordNumber     string       `fixed:"39,39,left"`
+	WaypointDescriptionCode      string       `fixed:"40,43,left"`
+	TurnDirection                string       `fixed:"44,44,left"`
+	RNP                          string       `fixed:"45,47,left"`
+	PathAndTermination           string       `fixed:"48,49,left"`
+	TurnDirectionValid           string       `fixed:"50,50,left"`
+	RecommendedNavaid            string       `fixed:"51,54,left"`
+	RecommendedNavaidICAOCode    string       `fixed:"55,56,left"`
+	ArcRadius                    string       `fixed:"57,62,left"`
+	Theta                        string       `fixed:"63,66,left"`
+	Rho                          string       `fixed:"67,70,left"`
+	MagneticCourse               NullBearing  `fixed:"71,74,left"`
+	RouteOrHoldingDistanceOrTime string       `fixed:"75,78,left"`
+	RecommendedNavSection        string       `fixed:"79,79,left"`
+	RecommendedNavSubsection     string       `fixed:"80,80,left"`
+	AltitudeDescription          string       `fixed:"83,83,left"`
+	ATCIndicator                 string       `fixed:"84,84,left"`
+	Altitude1                    NullAltitude `fixed:"85,89,left"`
+	Altitude2                    NullAltitude `fixed:"90,94,left"`
+	TransitionAltitude           NullAltitude `fixed:"95,99,left"`
+	SpeedLimit                   string       `fixed:"100,102,left"`
+	VerticalAngle                string       `fixed:"103,106,left"`
+	CenterFixOrTAASectorID       string       `fixed:"107,111,left"`
+	MultipleCodeOrTAASectorID    string       `fixed:"112,112,left"`
+	CenterFixICAOCode            string       `fixed:"113,114,left"`
+	CenterFixSectionCode         string       `fixed:"115,115,left"`
+	CenterFixSubsectionCode      string       `fixed:"116,116,left"`
+	GpsFmsIndication             string       `fixed:"117,117,left"`
+	SpeedLimitDescription        string       `fixed:"118,118,left"`
+	ApproachRouteQualifier1      string       `fixed:"119,119,left"`
+	ApproachRouteQualifier2      string       `fixed:"120,120,left"`
 }
 
 // IsLocalizerFrontCourseApproach returns true if the approach procedure is a
@@ -336,6 +602,16 @@ func (p *AirportProcedurePrimaryRecord) IsLocalizerFrontCourseApproach() bool {
 	return false
 }
 
+// IsMissedApproachPoint returns true if the record is for the missed approach
+// point on an approach procedure.
+func (p *AirportProcedurePrimaryRecord) IsMissedApproachPoint() bool {
+	d := p.WaypointDescriptionCode
+	if len(d) >= 4 && d[3] == 'M' {
+		return true
+	}
+	return false
+}
+
 // IsFinalApproachFix returns true if the record is for the final approach
 // fix on an approach procedure.
 func (p *AirportProcedurePrimaryRecord) IsFinalApproachFix() bool {
@@ -345,3 +621,257 @@ func (p *AirportProcedurePrimaryRecord) IsFinalApproachFix() bool {
 	}
 	return false
 }
+
+// AirportMSARecord is a record for a minimum sector altitude at an airport.
+// Each of the seven sectors has a pair of bearings, an altitude, and a radius,
+// and the fields of unused sectors are blank. The MSA center and multiple code
+// together identify the MSA when an airport has more than one.
+// See 4.1.20.1 Airport and Heliport MSA Primary Records
+type AirportMSARecord struct {
+	AirportEnrouteRecord     `fixed:"1,13,left"`
+	MSACenter                string `fixed:"14,18,left"`
+	MSACenterICAOCode        string `fixed:"19,20,left"`
+	MSACenterSectionCode     string `fixed:"21,21,left"`
+	MSACenterSubsectionCode  string `fixed:"22,22,left"`
+	MultipleCode             string `fixed:"23,23,left"`
+	ContinuationRecordNumber string `fixed:"39,39,left"`
+	SectorBearing1           string `fixed:"43,48,left"`
+	SectorAltitude1          string `fixed:"49,51,left"`
+	SectorRadius1            string `fixed:"52,53,left"`
+	SectorBearing2           string `fixed:"54,59,left"`
+	SectorAltitude2          string `fixed:"60,62,left"`
+	SectorRadius2            string `fixed:"63,64,left"`
+	SectorBearing3           string `fixed:"65,70,left"`
+	SectorAltitude3          string `fixed:"71,73,left"`
+	SectorRadius3            string `fixed:"74,75,left"`
+	SectorBearing4           string `fixed:"76,81,left"`
+	SectorAltitude4          string `fixed:"82,84,left"`
+	SectorRadius4            string `fixed:"85,86,left"`
+	SectorBearing5           string `fixed:"87,92,left"`
+	SectorAltitude5          string `fixed:"93,95,left"`
+	SectorRadius5            string `fixed:"96,97,left"`
+	SectorBearing6           string `fixed:"98,103,left"`
+	SectorAltitude6          string `fixed:"104,106,left"`
+	SectorRadius6            string `fixed:"107,108,left"`
+	SectorBearing7           string `fixed:"109,114,left"`
+	SectorAltitude7          string `fixed:"115,117,left"`
+	SectorRadius7            string `fixed:"118,119,left"`
+	MagneticTrueIndicator    string `fixed:"120,120,left"`
+}
+
+// AirportTAARecord is a record for one sector of the terminal arrival area
+// (TAA) of an approach. The sector lies between a pair of bearings to the IAF,
+// and each of the seven bands has a pair of radii and an altitude. The fields
+// of unused bands are blank.
+// See 4.1.31.1 Airport and Heliport TAA Primary Records
+type AirportTAARecord struct {
+	AirportEnrouteRecord     `fixed:"1,13,left"`
+	ApproachID               string `fixed:"14,19,left"`
+	SectorID                 string `fixed:"20,20,left"`
+	ProcedureTurn            string `fixed:"21,24,left"`
+	IAFWaypoint              string `fixed:"27,31,left"`
+	IAFICAOCode              string `fixed:"32,33,left"`
+	IAFSectionCode           string `fixed:"34,34,left"`
+	IAFSubsectionCode        string `fixed:"35,35,left"`
+	ContinuationRecordNumber string `fixed:"39,39,left"`
+	SectorBearing            string `fixed:"41,46,left"`
+	MagneticTrueIndicator    string `fixed:"47,47,left"`
+	SectorRadius1            string `fixed:"48,51,left"`
+	SectorAltitude1          string `fixed:"52,54,left"`
+	SectorRadius2            string `fixed:"55,58,left"`
+	SectorAltitude2          string `fixed:"59,61,left"`
+	SectorRadius3            string `fixed:"62,65,left"`
+	SectorAltitude3          string `fixed:"66,68,left"`
+	SectorRadius4            string `fixed:"69,72,left"`
+	SectorAltitude4          string `fixed:"73,75,left"`
+	SectorRadius5            string `fixed:"76,79,left"`
+	SectorAltitude5          string `fixed:"80,82,left"`
+	SectorRadius6            string `fixed:"83,86,left"`
+	SectorAltitude6          string `fixed:"87,89,left"`
+	SectorRadius7            string `fixed:"90,93,left"`
+	SectorAltitude7          string `fixed:"94,96,left"`
+}
+
+// AirportCommunicationPrimaryRecord is a record for a communication frequency
+// at an airport, such as the tower or ATIS frequency. If the frequency is
+// only used in a sector around the airport, the sectorization, altitude, and
+// distance fields describe the sector.
+// See 4.1.14.1 Airport Communications Primary Records
+type AirportCommunicationPrimaryRecord struct {
+	AirportEnrouteRecord     `fixed:"1,13,left"`
+	CommunicationType        string         `fixed:"14,16,left"`
+	CommunicationFrequency   string         `fixed:"17,23,left"`
+	FrequencyUnits           string         `fixed:"24,24,left"`
+	ContinuationRecordNumber string         `fixed:"25,25,left"`
+	ServiceIndicator         string         `fixed:"26,28,left"`
+	RadarService             string         `fixed:"29,29,left"`
+	Modulation               string         `fixed:"30,30,left"`
+	SignalEmission           string         `fixed:"31,31,left"`
+	Position                 NullCoordinate `fixed:"32,50,left"`
+	MagneticVar              NullMagVar     `fixed:"51,55,left"`
+	FacilityElevation        string         `fixed:"56,60,left"`
+	H24Indicator             string         `fixed:"61,61,left"`
+	Sectorization            string         `fixed:"62,67,left"`
+	AltitudeDescription      string         `fixed:"68,68,left"`
+	CommunicationAltitude1   NullAltitude   `fixed:"69,73,left"`
+	CommunicationAltitude2   NullAltitude   `fixed:"74,78,left"`
+	SectorFacility           string         `fixed:"79,82,left"`
+	SectorFacilityICAOCode   string         `fixed:"83,84,left"`
+	SectorFacilitySection    string         `fixed:"85,85,left"`
+	SectorFacilitySubsection string         `fixed:"86,86,left"`
+	DistanceDescription      string         `fixed:"87,87,left"`
+	CommunicationDistance    string         `fixed:"88,89,left"`
+	RemoteFacility           string         `fixed:"90,94,left"`
+	RemoteFacilityICAOCode   string         `fixed:"95,96,left"`
+	RemoteFacilitySection    string         `fixed:"97,97,left"`
+	RemoteFacilitySubsection string         `fixed:"98,98,left"`
+	CallSign                 string         `fixed:"99,123,left"`
+}
+
+// AirportCommunicationContinuationRecord is a continuation record for an
+// AirportCommunicationPrimaryRecord, which carries a narrative description of
+// the sectorization.
+// See 4.1.14.2 Airport Communications Continuation Records
+type AirportCommunicationContinuationRecord struct {
+	AirportEnrouteRecord     `fixed:"1,13,left"`
+	CommunicationType        string `fixed:"14,16,left"`
+	CommunicationFrequency   string `fixed:"17,23,left"`
+	FrequencyUnits           string `fixed:"24,24,left"`
+	ContinuationRecordNumber string `fixed:"25,25,left"`
+	ApplicationType          string `fixed:"26,26,left"`
+	Narrative                string `fixed:"27,86,left"`
+}
+
+// ControlledAirspaceRecord is a record for one point on the boundary of a
+// controlled airspace, such as a class B, C, or D airspace. The airspace
+// center identifies the airport or navaid that the airspace belongs to, and
+// the multiple code separates the parts of an airspace that have different
+// limits. The limits and name are only required on the first record of each
+// part.
+// See 4.1.25.1 Controlled Airspace Primary Records
+type ControlledAirspaceRecord struct {
+	Record                   `fixed:"1,6,left"`
+	ICAOCode                 string         `fixed:"7,8,left"`
+	AirspaceType             string         `fixed:"9,9,left"`
+	AirspaceCenter           string         `fixed:"10,14,left"`
+	AirspaceCenterSection    string         `fixed:"15,15,left"`
+	AirspaceCenterSubsection string         `fixed:"16,16,left"`
+	AirspaceClassification   string         `fixed:"17,17,left"`
+	MultipleCode             string         `fixed:"20,20,left"`
+	SequenceNumber           string         `fixed:"21,24,left"`
+	ContinuationRecordNumber string         `fixed:"25,25,left"`
+	Level                    string         `fixed:"26,26,left"`
+	TimeCode                 string         `fixed:"27,27,left"`
+	NOTAM                    string         `fixed:"28,28,left"`
+	BoundaryVia              string         `fixed:"31,32,left"`
+	Position                 NullCoordinate `fixed:"33,51,left"`
+	ArcOrigin                NullCoordinate `fixed:"52,70,left"`
+	ArcDistance              string         `fixed:"71,74,left"`
+	ArcBearing               string         `fixed:"75,78,left"`
+	RNP                      string         `fixed:"79,81,left"`
+	LowerLimit               string         `fixed:"82,86,left"`
+	LowerLimitUnitIndicator  string         `fixed:"87,87,left"`
+	UpperLimit               string         `fixed:"88,92,left"`
+	UpperLimitUnitIndicator  string         `fixed:"93,93,left"`
+	ControlledAirspaceName   string         `fixed:"94,123,left"`
+}
+
+// RestrictiveAirspaceRecord is a record for one point on the boundary of a
+// restrictive airspace, such as a restricted or prohibited area. The fields
+// have the same meaning as in ControlledAirspaceRecord.
+// See 4.1.18.1 Restrictive Airspace Primary Records
+type RestrictiveAirspaceRecord struct {
+	Record                   `fixed:"1,6,left"`
+	ICAOCode                 string         `fixed:"7,8,left"`
+	RestrictiveType          string         `fixed:"9,9,left"`
+	RestrictiveDesignation   string         `fixed:"10,19,left"`
+	MultipleCode             string         `fixed:"20,20,left"`
+	SequenceNumber           string         `fixed:"21,24,left"`
+	ContinuationRecordNumber string         `fixed:"25,25,left"`
+	Level                    string         `fixed:"26,26,left"`
+	TimeCode                 string         `fixed:"27,27,left"`
+	NOTAM                    string         `fixed:"28,28,left"`
+	BoundaryVia              string         `fixed:"31,32,left"`
+	Position                 NullCoordinate `fixed:"33,51,left"`
+	ArcOrigin                NullCoordinate `fixed:"52,70,left"`
+	ArcDistance              string         `fixed:"71,74,left"`
+	ArcBearing               string         `fixed:"75,78,left"`
+	LowerLimit               string         `fixed:"82,86,left"`
+	LowerLimitUnitIndicator  string         `fixed:"87,87,left"`
+	UpperLimit               string         `fixed:"88,92,left"`
+	UpperLimitUnitIndicator  string         `fixed:"93,93,left"`
+	RestrictiveAirspaceName  string         `fixed:"94,123,left"`
+}
+
+// AirportPathPointPrimaryRecord is a record for the final approach segment
+// of an SBAS or GBAS approach, such as an LPV approach. The final approach
+// segment is defined by the landing threshold point (LTP) and the flight path
+// alignment point (FPAP), whose positions are given to a ten thousandth of an
+// arc second.
+// See 4.1.28.1 Airport and Heliport Path Point Primary Records
+type AirportPathPointPrimaryRecord struct {
+	AirportEnrouteRecord          `fixed:"1,13,left"`
+	ApproachProcedureID           string `fixed:"14,19,left"`
+	RunwayOrHelipadID             string `fixed:"20,24,left"`
+	OperationType                 string `fixed:"25,26,left"`
+	ContinuationRecordNumber      string `fixed:"27,27,left"`
+	RouteIndicator                string `fixed:"28,28,left"`
+	SBASServiceProviderID         string `fixed:"29,30,left"`
+	ReferencePathDataSelector     string `fixed:"31,32,left"`
+	ReferencePathID               string `fixed:"33,36,left"`
+	ApproachPerformanceDesignator string `fixed:"37,37,left"`
+	LTPLatitude                   string `fixed:"38,48,left"`
+	LTPLongitude                  string `fixed:"49,60,left"`
+	LTPEllipsoidHeight            string `fixed:"61,66,left"`
+	GlidePathAngle                string `fixed:"67,70,left"`
+	FPAPLatitude                  string `fixed:"71,81,left"`
+	FPAPLongitude                 string `fixed:"82,93,left"`
+	CourseWidthAtThreshold        string `fixed:"94,98,left"`
+	LengthOffset                  string `fixed:"99,102,left"`
+	PathPointTCH                  string `fixed:"103,108,left"`
+	TCHUnitsIndicator             string `fixed:"109,109,left"`
+	HAL                           string `fixed:"110,112,left"`
+	VAL                           string `fixed:"113,115,left"`
+	FASDataCRCRemainder           string `fixed:"116,123,left"`
+}
+
+// AirportPathPointContinuationRecord is a continuation record for an
+// AirportPathPointPrimaryRecord, which carries the orthometric heights of the
+// LTP and FPAP and the name and channel of the approach.
+// See 4.1.28.2 Airport and Heliport Path Point Continuation Records
+type AirportPathPointContinuationRecord struct {
+	AirportEnrouteRecord     `fixed:"1,13,left"`
+	ApproachProcedureID      string `fixed:"14,19,left"`
+	RunwayOrHelipadID        string `fixed:"20,24,left"`
+	OperationType            string `fixed:"25,26,left"`
+	ContinuationRecordNumber string `fixed:"27,27,left"`
+	ApplicationType          string `fixed:"28,28,left"`
+	FPAPEllipsoidHeight      string `fixed:"29,34,left"`
+	FPAPOrthometricHeight    string `fixed:"35,40,left"`
+	LTPOrthometricHeight     string `fixed:"41,46,left"`
+	ApproachTypeIdentifier   string `fixed:"47,56,left"`
+	GNSSChannelNumber        string `fixed:"57,61,left"`
+}
+
+// AirportGLSRecord is a record for a GBAS landing system (GLS) approach to a
+// runway, and the ground station that provides it.
+// See 4.1.30.1 Airport and Heliport GLS Primary Records
+type AirportGLSRecord struct {
+	AirportEnrouteRecord     `fixed:"1,13,left"`
+	GLSReferencePathID       string     `fixed:"14,17,left"`
+	GLSCategory              string     `fixed:"18,18,left"`
+	ContinuationRecordNumber string     `fixed:"22,22,left"`
+	GLSChannel               string     `fixed:"23,27,left"`
+	RunwayIdentifier         string     `fixed:"28,32,left"`
+	GLSApproachBearing       Bearing    `fixed:"52,55,left"`
+	Station                  Coordinate `fixed:"56,74,left"`
+	GLSStationID             string     `fixed:"75,78,left"`
+	ServiceVolumeRadius      string     `fixed:"82,83,left"`
+	TDMASlots                string     `fixed:"84,85,left"`
+	GlidePathAngle           string     `fixed:"86,88,left"`
+	MagneticVar              MagVar     `fixed:"89,93,left"`
+	StationElevation         string     `fixed:"98,102,left"`
+	DatumCode                string     `fixed:"103,105,left"`
+	StationType              string     `fixed:"106,107,left"`
+	StationElevationWGS84    string     `fixed:"110,114,left"`
+}
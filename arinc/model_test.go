@@ -3,6 +3,9 @@ package arinc
 import (
 	"math"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	fixedwidth "github.com/ianlopshire/go-fixedwidth"
 )
 
 func TestLatLon(t *testing.T) {
@@ -118,6 +121,7 @@ func TestEncodeBearing(t *testing.T) {
 		name    string
 		bearing float64
 		want    string
+		wantErr bool
 	}{
 		{
 			name:    "Simple",
@@ -134,9 +138,29 @@ func TestEncodeBearing(t *testing.T) {
 			bearing: 23.45678,
 			want:    "02346",
 		},
+		{
+			name:    "Negative",
+			bearing: -1,
+			wantErr: true,
+		},
+		{
+			name:    "Over360Degrees",
+			bearing: 360.5,
+			wantErr: true,
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := EncodeBearing(tt.bearing); got != tt.want {
+			got, err := EncodeBearing(tt.bearing)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("EncodeBearing(%f) = _, <nil> want _, <non-nil>", tt.bearing)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EncodeBearing(%f) = _, %v want _, <nil>", tt.bearing, err)
+			}
+			if got != tt.want {
 				t.Errorf("EncodeBearing(%f) = %q want %q", tt.bearing, got, tt.want)
 			}
 		})
@@ -326,3 +350,459 @@ func TestIsFinalApproachFix(t *testing.T) {
 		})
 	}
 }
+
+func TestOppositeRunwayID(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		runwayID string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "Left",
+			runwayID: "RW28L",
+			want:     "RW10R",
+		},
+		{
+			name:     "Right",
+			runwayID: "RW10R",
+			want:     "RW28L",
+		},
+		{
+			name:     "Center",
+			runwayID: "RW34C",
+			want:     "RW16C",
+		},
+		{
+			name:     "NoDesignator",
+			runwayID: "RW02",
+			want:     "RW20",
+		},
+		{
+			name:     "Runway18",
+			runwayID: "RW18",
+			want:     "RW36",
+		},
+		{
+			name:     "Runway36",
+			runwayID: "RW36",
+			want:     "RW18",
+		},
+		{
+			name:     "InvalidPrefix",
+			runwayID: "XX28L",
+			wantErr:  true,
+		},
+		{
+			name:     "InvalidNumber",
+			runwayID: "RW37",
+			wantErr:  true,
+		},
+		{
+			name:     "InvalidDesignator",
+			runwayID: "RW28X",
+			wantErr:  true,
+		},
+		{
+			name:     "InvalidLength",
+			runwayID: "RW",
+			wantErr:  true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OppositeRunwayID(tt.runwayID)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("OppositeRunwayID(%q) = _, <nil> want _, <non-nil>", tt.runwayID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OppositeRunwayID(%q) = _, %v want _, <nil>", tt.runwayID, err)
+			}
+			if got != tt.want {
+				t.Errorf("OppositeRunwayID(%q) = %q, _ want %q, _", tt.runwayID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsMissedApproachPoint(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		record *AirportProcedurePrimaryRecord
+		want   bool
+	}{
+		{
+			name:   "Good",
+			record: &AirportProcedurePrimaryRecord{WaypointDescriptionCode: "GY M"},
+			want:   true,
+		},
+		{
+			name:   "NotMissedApproachPoint",
+			record: &AirportProcedurePrimaryRecord{WaypointDescriptionCode: "   F"},
+			want:   false,
+		},
+		{
+			name:   "InvalidData",
+			record: &AirportProcedurePrimaryRecord{WaypointDescriptionCode: ""},
+			want:   false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.record.IsMissedApproachPoint()
+			if got != tt.want {
+				t.Errorf("IsMissedApproachPoint() = %t want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRunwayID(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		runwayID       string
+		wantNumber     int
+		wantDesignator string
+		wantErr        bool
+	}{
+		{
+			name:           "Left",
+			runwayID:       "RW28L",
+			wantNumber:     28,
+			wantDesignator: "L",
+		},
+		{
+			name:           "Center",
+			runwayID:       "RW16C",
+			wantNumber:     16,
+			wantDesignator: "C",
+		},
+		{
+			name:       "NoDesignator",
+			runwayID:   "RW02",
+			wantNumber: 2,
+		},
+		{
+			name:     "InvalidPrefix",
+			runwayID: "XX28L",
+			wantErr:  true,
+		},
+		{
+			name:     "InvalidNumber",
+			runwayID: "RW00",
+			wantErr:  true,
+		},
+		{
+			name:     "InvalidDesignator",
+			runwayID: "RW28X",
+			wantErr:  true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			number, designator, err := ParseRunwayID(tt.runwayID)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseRunwayID(%q) = _, _, <nil> want _, _, <non-nil>", tt.runwayID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRunwayID(%q) = _, _, %v want _, _, <nil>", tt.runwayID, err)
+			}
+			if number != tt.wantNumber || designator != tt.wantDesignator {
+				t.Errorf("ParseRunwayID(%q) = %d, %q, _ want %d, %q, _", tt.runwayID, number, designator, tt.wantNumber, tt.wantDesignator)
+			}
+			if got := EncodeRunwayID(number, designator); got != tt.runwayID {
+				t.Errorf("EncodeRunwayID(%d, %q) = %q want %q", number, designator, got, tt.runwayID)
+			}
+		})
+	}
+}
+
+func TestParseRunwayLength(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		length  string
+		want    int
+		wantErr bool
+	}{
+		{
+			name:   "Simple",
+			length: "03107",
+			want:   3107,
+		},
+		{
+			name:   "Long",
+			length: "11870",
+			want:   11870,
+		},
+		{
+			name:    "Negative",
+			length:  "-0100",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidData",
+			length:  "ABCDE",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidLength",
+			length:  "3107",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRunwayLength(tt.length)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseRunwayLength(%q) = _, <nil> want _, <non-nil>", tt.length)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRunwayLength(%q) = _, %v want _, <nil>", tt.length, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRunwayLength(%q) = %d, _ want %d, _", tt.length, got, tt.want)
+			}
+			if enc := EncodeRunwayLength(got); enc != tt.length {
+				t.Errorf("EncodeRunwayLength(%d) = %q want %q", got, enc, tt.length)
+			}
+		})
+	}
+}
+
+func TestEncodeRunwayBearing(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		bearing float64
+		isTrue  bool
+		want    string
+	}{
+		{
+			name:    "Magnetic",
+			bearing: 284.0,
+			want:    "2840",
+		},
+		{
+			name:    "LessThan100Degrees",
+			bearing: 14.7,
+			want:    "0147",
+		},
+		{
+			name:    "True",
+			bearing: 347,
+			isTrue:  true,
+			want:    "347T",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EncodeRunwayBearing(tt.bearing, tt.isTrue)
+			if got != tt.want {
+				t.Fatalf("EncodeRunwayBearing(%f, %t) = %q want %q", tt.bearing, tt.isTrue, got, tt.want)
+			}
+			bearing, isTrue, err := ParseBearing(got)
+			if err != nil {
+				t.Fatalf("ParseBearing(%q) = _, _, %v want _, _, <nil>", got, err)
+			}
+			if bearing != tt.bearing || isTrue != tt.isTrue {
+				t.Errorf("ParseBearing(%q) = %f, %t, _ want %f, %t, _", got, bearing, isTrue, tt.bearing, tt.isTrue)
+			}
+		})
+	}
+}
+
+func TestParseElevation(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		elevation string
+		want      int
+		wantErr   bool
+	}{
+		{
+			name:      "Simple",
+			elevation: "00028",
+			want:      28,
+		},
+		{
+			name:      "BelowSeaLevel",
+			elevation: "-0024",
+			want:      -24,
+		},
+		{
+			name:      "InvalidData",
+			elevation: "0002A",
+			wantErr:   true,
+		},
+		{
+			name:      "InvalidLength",
+			elevation: "28",
+			wantErr:   true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseElevation(tt.elevation)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseElevation(%q) = _, <nil> want _, <non-nil>", tt.elevation)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseElevation(%q) = _, %v want _, <nil>", tt.elevation, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseElevation(%q) = %d, _ want %d, _", tt.elevation, got, tt.want)
+			}
+			if enc := EncodeElevation(got); enc != tt.elevation {
+				t.Errorf("EncodeElevation(%d) = %q want %q", got, enc, tt.elevation)
+			}
+		})
+	}
+}
+
+func TestAirportRunwayPrimaryRecord(t *testing.T) {
+	const record = "SUSAP KHWDK2GRW28L   0056942840 N37391866W122065313         -0017200050067635150RIHWD0                                     108881707"
+	got := AirportRunwayPrimaryRecord{}
+	if err := fixedwidth.Unmarshal([]byte(record), &got); err != nil {
+		t.Fatalf("Unmarshal() = %v want <nil>", err)
+	}
+	want := AirportRunwayPrimaryRecord{
+		AirportEnrouteRecord: AirportEnrouteRecord{
+			Record:         Record{RecordType: "S", CustomerAreaCode: "USA", SectionCode: "P"},
+			AirportID:      "KHWD",
+			ICAOCode:       "K2",
+			SubsectionCode: "G",
+		},
+		RunwayID:                   "RW28L",
+		ContinuationRecordNumber:   "0",
+		RunwayLength:               "05694",
+		RunwayMagneticBearing:      Bearing{Value: 284},
+		Runway:                     Coordinate{Lat: 37 + 39.0/60 + 18.66/3600, Lon: -(122 + 6.0/60 + 53.13/3600)},
+		EllipsoidHeight:            "-00172",
+		LandingThresholdElevation:  "00050",
+		DisplacedThresholdDistance: "0676",
+		ThresholdCrossingHeight:    "35",
+		RunwayWidth:                "150",
+		TCHValueIndicator:          "R",
+		LocalizerID:                "IHWD",
+		LocalizerCategory:          "0",
+	}
+	approx := cmp.Comparer(func(x, y float64) bool { return math.Abs(x-y) < 0.000001 })
+	if diff := cmp.Diff(want, got, approx); diff != "" {
+		t.Fatalf("Unmarshal() had diffs (-want +got): %s", diff)
+	}
+
+	number, designator, err := ParseRunwayID(got.RunwayID)
+	if err != nil {
+		t.Fatalf("ParseRunwayID(%q) = _, _, %v want _, _, <nil>", got.RunwayID, err)
+	}
+	length, err := ParseRunwayLength(got.RunwayLength)
+	if err != nil {
+		t.Fatalf("ParseRunwayLength(%q) = _, %v want _, <nil>", got.RunwayLength, err)
+	}
+	elevation, err := ParseElevation(got.LandingThresholdElevation)
+	if err != nil {
+		t.Fatalf("ParseElevation(%q) = _, %v want _, <nil>", got.LandingThresholdElevation, err)
+	}
+
+	got.RunwayID = EncodeRunwayID(number, designator)
+	got.RunwayLength = EncodeRunwayLength(length)
+	got.LandingThresholdElevation = EncodeElevation(elevation)
+	gotBytes, err := fixedwidth.Marshal(got)
+	if err != nil {
+		t.Fatalf("Marshal() = %v want <nil>", err)
+	}
+	// Marshal does not write columns that are not in the struct, such as the
+	// file record number.
+	if diff := cmp.Diff(record[:123], string(gotBytes)[:123]); diff != "" {
+		t.Errorf("Marshal() had diffs (-want +got): %s", diff)
+	}
+}
+
+func TestEnrouteAirwayRecord(t *testing.T) {
+	const record = "SUSAER       V25         0100PYE  K2D 0V    OL                        115002081147 04000     17999                         459892002"
+	got := EnrouteAirwayRecord{}
+	if err := fixedwidth.Unmarshal([]byte(record), &got); err != nil {
+		t.Fatalf("Unmarshal() = %v want <nil>", err)
+	}
+	want := EnrouteAirwayRecord{
+		Record:                   Record{RecordType: "S", CustomerAreaCode: "USA", SectionCode: "E", SubsectionCode: "R"},
+		RouteID:                  "V25",
+		SequenceNumber:           "0100",
+		FixID:                    "PYE",
+		FixICAOCode:              "K2",
+		FixSectionCode:           "D",
+		ContinuationRecordNumber: "0",
+		WaypointDescriptionCode:  "V",
+		RouteType:                "O",
+		Level:                    "L",
+		OutboundMagneticCourse:   NullBearing{Bearing: Bearing{Value: 115}, Valid: true},
+		RouteDistanceFrom:        "0208",
+		InboundMagneticCourse:    NullBearing{Bearing: Bearing{Value: 114.7}, Valid: true},
+		MinimumAltitude:          "04000",
+		MaximumAltitude:          "17999",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unmarshal() had diffs (-want +got): %s", diff)
+	}
+}
+
+func TestIsEndOfAirway(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		desc string
+		want bool
+	}{
+		{
+			name: "End",
+			desc: "VE",
+			want: true,
+		},
+		{
+			name: "NotEnd",
+			desc: "V",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &EnrouteAirwayRecord{WaypointDescriptionCode: tt.desc}
+			if got := r.IsEndOfAirway(); got != tt.want {
+				t.Errorf("IsEndOfAirway() = %t want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAirportLocalizerMarkerRecord(t *testing.T) {
+	const record = "SUSAP KOAKK2MIOAKOM  000362RW30 N37450000W1221300003020N37450083W122130172H  W      OA    E0140  00010                     108062002"
+	got := AirportLocalizerMarkerRecord{}
+	if err := fixedwidth.Unmarshal([]byte(record), &got); err != nil {
+		t.Fatalf("Unmarshal() = %v want <nil>", err)
+	}
+	want := AirportLocalizerMarkerRecord{
+		AirportEnrouteRecord: AirportEnrouteRecord{
+			Record:         Record{RecordType: "S", CustomerAreaCode: "USA", SectionCode: "P"},
+			AirportID:      "KOAK",
+			ICAOCode:       "K2",
+			SubsectionCode: "M",
+		},
+		LocalizerID:              "IOAK",
+		MarkerType:               "OM",
+		ContinuationRecordNumber: "0",
+		LocatorFrequency:         "00362",
+		RunwayIdentifier:         "RW30",
+		Marker:                   NullCoordinate{Coordinate: Coordinate{Lat: 37.75, Lon: -(122 + 13.0/60)}, Valid: true},
+		MinorAxisBearing:         NullBearing{Bearing: Bearing{Value: 302}, Valid: true},
+		Locator:                  NullCoordinate{Coordinate: Coordinate{Lat: 37 + 45.0/60 + 0.83/3600, Lon: -(122 + 13.0/60 + 1.72/3600)}, Valid: true},
+		LocatorClass:             "H  W",
+		LocatorID:                "OA",
+		MagneticVar:              NullMagVar{MagVar: MagVar{Value: -14}, Valid: true},
+		FacilityElevation:        "00010",
+	}
+	approx := cmp.Comparer(func(x, y float64) bool { return math.Abs(x-y) < 0.000001 })
+	if diff := cmp.Diff(want, got, approx); diff != "" {
+		t.Errorf("Unmarshal() had diffs (-want +got): %s", diff)
+	}
+}
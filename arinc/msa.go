@@ -0,0 +1,143 @@
+package arinc
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// MSASector is one sector of a minimum sector altitude. The sector extends
+// clockwise from FromBearing to ToBearing. Bearings are those of courses to
+// the MSA center, as printed on charts, so an aircraft is in the sector if its
+// bearing to the center is in it.
+type MSASector struct {
+	FromBearing float64
+	ToBearing   float64
+	Altitude    Altitude
+	// Radius is the radius of the sector in nautical miles.
+	Radius int
+}
+
+// Contains returns true if the bearing to the MSA center is in the sector. A
+// sector whose bearings are equal, such as 000 to 360, covers every bearing.
+func (s *MSASector) Contains(bearing float64) bool {
+	return betweenBearings(s.FromBearing, s.ToBearing, bearing)
+}
+
+// betweenBearings returns true if the bearing is clockwise from the from
+// bearing and before the to bearing. If the two are equal, every bearing is
+// between them.
+func betweenBearings(from, to, bearing float64) bool {
+	span := math.Mod(to-from+360, 360)
+	if span == 0 {
+		return true
+	}
+	return math.Mod(bearing-from+360, 360) < span
+}
+
+// MSA is a decoded minimum sector altitude.
+type MSA struct {
+	AirportID    string
+	CenterID     string
+	MultipleCode string
+	// BearingsAreTrue is true if the sector bearings are referenced to true
+	// north instead of magnetic north.
+	BearingsAreTrue bool
+	Sectors         []*MSASector
+}
+
+// Sector returns the sector that contains the bearing to the MSA center. If no
+// sector contains it, nil is returned.
+func (m *MSA) Sector(bearing float64) *MSASector {
+	for _, s := range m.Sectors {
+		if s.Contains(bearing) {
+			return s
+		}
+	}
+	return nil
+}
+
+// MSA decodes the fields of the MSA record. Sectors with blank fields are
+// skipped. If any field is malformed, an error naming the field is returned.
+func (r *AirportMSARecord) MSA() (*MSA, error) {
+	m := &MSA{
+		AirportID:    r.AirportID,
+		CenterID:     r.MSACenter,
+		MultipleCode: r.MultipleCode,
+	}
+	switch r.MagneticTrueIndicator {
+	case "M", "":
+	case "T":
+		m.BearingsAreTrue = true
+	default:
+		return nil, fmt.Errorf("MagneticTrueIndicator: invalid indicator %q", r.MagneticTrueIndicator)
+	}
+	for i, f := range [][3]string{
+		{r.SectorBearing1, r.SectorAltitude1, r.SectorRadius1},
+		{r.SectorBearing2, r.SectorAltitude2, r.SectorRadius2},
+		{r.SectorBearing3, r.SectorAltitude3, r.SectorRadius3},
+		{r.SectorBearing4, r.SectorAltitude4, r.SectorRadius4},
+		{r.SectorBearing5, r.SectorAltitude5, r.SectorRadius5},
+		{r.SectorBearing6, r.SectorAltitude6, r.SectorRadius6},
+		{r.SectorBearing7, r.SectorAltitude7, r.SectorRadius7},
+	} {
+		if f[0] == "" && f[1] == "" && f[2] == "" {
+			continue
+		}
+		s, err := parseMSASector(f[0], f[1], f[2])
+		if err != nil {
+			return nil, fmt.Errorf("Sector%d: %v", i+1, err)
+		}
+		m.Sectors = append(m.Sectors, s)
+	}
+	if len(m.Sectors) == 0 {
+		return nil, fmt.Errorf("MSA at %q has no sectors", r.MSACenter)
+	}
+	return m, nil
+}
+
+// parseMSASector parses a six character pair of whole degree bearings, a three
+// character altitude in hundreds of feet, and a two character radius in
+// nautical miles.
+func parseMSASector(bearings, altitude, radius string) (*MSASector, error) {
+	from, to, err := parseSectorBearings(bearings)
+	if err != nil {
+		return nil, err
+	}
+	alt, err := parseSectorAltitude(altitude)
+	if err != nil {
+		return nil, err
+	}
+	nm, err := strconv.Atoi(radius)
+	if err != nil || nm <= 0 || len(radius) != 2 {
+		return nil, fmt.Errorf("invalid sector radius %q", radius)
+	}
+	return &MSASector{
+		FromBearing: from,
+		ToBearing:   to,
+		Altitude:    alt,
+		Radius:      nm,
+	}, nil
+}
+
+// parseSectorBearings parses a six character pair of whole degree bearings.
+func parseSectorBearings(bearings string) (from, to float64, err error) {
+	if len(bearings) != 6 {
+		return 0, 0, fmt.Errorf("invalid sector bearings %q, want 6 characters", bearings)
+	}
+	f, err1 := strconv.Atoi(bearings[:3])
+	t, err2 := strconv.Atoi(bearings[3:])
+	if err1 != nil || err2 != nil || f < 0 || f > 360 || t < 0 || t > 360 {
+		return 0, 0, fmt.Errorf("invalid sector bearings %q", bearings)
+	}
+	return float64(f), float64(t), nil
+}
+
+// parseSectorAltitude parses a three character altitude in hundreds of feet.
+func parseSectorAltitude(altitude string) (Altitude, error) {
+	hundreds, err := strconv.Atoi(altitude)
+	if err != nil || hundreds < 0 || len(altitude) != 3 {
+		return Altitude{}, fmt.Errorf("invalid sector altitude %q", altitude)
+	}
+	return Altitude{Feet: hundreds * 100}, nil
+}
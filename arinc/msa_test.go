@@ -0,0 +1,110 @@
+package arinc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMSA(t *testing.T) {
+	rec, err := Parse([]byte("SUSAP KHWDK2SBOGREK2PC                0   0001800402518036006025                                                       M   107992002"))
+	if err != nil {
+		t.Fatalf("Parse() = %v want <nil>", err)
+	}
+	got, err := rec.(*AirportMSARecord).MSA()
+	if err != nil {
+		t.Fatalf("MSA() = _, %v want _, <nil>", err)
+	}
+	want := &MSA{
+		AirportID: "KHWD",
+		CenterID:  "BOGRE",
+		Sectors: []*MSASector{
+			{FromBearing: 0, ToBearing: 180, Altitude: Altitude{Feet: 4000}, Radius: 25},
+			{FromBearing: 180, ToBearing: 360, Altitude: Altitude{Feet: 6000}, Radius: 25},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MSA() had diffs (-want +got): %s", diff)
+	}
+}
+
+func TestMSAErrors(t *testing.T) {
+	good := func() *AirportMSARecord {
+		return &AirportMSARecord{
+			MSACenter:             "BOGRE",
+			SectorBearing1:        "000360",
+			SectorAltitude1:       "040",
+			SectorRadius1:         "25",
+			MagneticTrueIndicator: "M",
+		}
+	}
+	for _, tt := range []struct {
+		name   string
+		modify func(r *AirportMSARecord)
+	}{
+		{
+			name:   "MagneticTrueIndicator",
+			modify: func(r *AirportMSARecord) { r.MagneticTrueIndicator = "X" },
+		},
+		{
+			name:   "SectorBearing",
+			modify: func(r *AirportMSARecord) { r.SectorBearing1 = "000361" },
+		},
+		{
+			name:   "SectorBearingLength",
+			modify: func(r *AirportMSARecord) { r.SectorBearing1 = "00036" },
+		},
+		{
+			name:   "SectorAltitude",
+			modify: func(r *AirportMSARecord) { r.SectorAltitude1 = "04" },
+		},
+		{
+			name:   "SectorRadius",
+			modify: func(r *AirportMSARecord) { r.SectorRadius1 = "00" },
+		},
+		{
+			name:   "PartialSector",
+			modify: func(r *AirportMSARecord) { r.SectorAltitude2 = "060" },
+		},
+		{
+			name: "NoSectors",
+			modify: func(r *AirportMSARecord) {
+				r.SectorBearing1, r.SectorAltitude1, r.SectorRadius1 = "", "", ""
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := good()
+			if _, err := r.MSA(); err != nil {
+				t.Fatalf("MSA() before modification = _, %v want _, <nil>", err)
+			}
+			tt.modify(r)
+			if _, err := r.MSA(); err == nil {
+				t.Errorf("MSA() = _, <nil> want _, <non-nil>")
+			}
+		})
+	}
+}
+
+func TestMSASectorContains(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		sector  MSASector
+		bearing float64
+		want    bool
+	}{
+		{"Inside", MSASector{FromBearing: 90, ToBearing: 180}, 135, true},
+		{"FromBearing", MSASector{FromBearing: 90, ToBearing: 180}, 90, true},
+		{"ToBearing", MSASector{FromBearing: 90, ToBearing: 180}, 180, false},
+		{"Outside", MSASector{FromBearing: 90, ToBearing: 180}, 200, false},
+		{"AcrossNorth", MSASector{FromBearing: 270, ToBearing: 90}, 10, true},
+		{"AcrossNorthOutside", MSASector{FromBearing: 270, ToBearing: 90}, 180, false},
+		{"FullCircle", MSASector{FromBearing: 0, ToBearing: 360}, 359.9, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sector.Contains(tt.bearing); got != tt.want {
+				t.Errorf("Contains(%f) = %t want %t", tt.bearing, got, tt.want)
+			}
+		})
+	}
+}
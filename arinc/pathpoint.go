@@ -0,0 +1,197 @@
+package arinc
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/wallaceicy06/enhance-faa-cifp/geodesy"
+)
+
+// ParseHighPrecisionLatLon returns the numerical latitude and longitude of
+// the provided high precision latitude and longitude strings, whose seconds
+// have four decimal places, such as "N3739450000" and "W12207300000". If the
+// data is invalid, an error is returned.
+// See 5.267 High Precision Latitude, 5.268 High Precision Longitude
+func ParseHighPrecisionLatLon(latitude, longitude string) (float64, float64, error) {
+	lat, err := parseHighPrecisionPoint(latitude, "NS", 2)
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not calculate latitude: %v", err)
+	}
+	lon, err := parseHighPrecisionPoint(longitude, "EW", 3)
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not calculate longitude: %v", err)
+	}
+	return lat, lon, nil
+}
+
+// parseHighPrecisionPoint parses a hemisphere, degrees with the given number
+// of digits, minutes, and seconds with four decimal places. The result is
+// negative in the second of the two hemispheres.
+func parseHighPrecisionPoint(point, hemispheres string, degreeDigits int) (float64, error) {
+	if len(point) != degreeDigits+9 || (point[0] != hemispheres[0] && point[0] != hemispheres[1]) {
+		return 0, fmt.Errorf("invalid high precision point %q", point)
+	}
+	deg, err1 := strconv.Atoi(point[1 : 1+degreeDigits])
+	min, err2 := strconv.Atoi(point[1+degreeDigits : 3+degreeDigits])
+	tenThousandths, err3 := strconv.Atoi(point[3+degreeDigits:])
+	if err1 != nil || err2 != nil || err3 != nil || deg < 0 || min < 0 || min >= 60 || tenThousandths < 0 || tenThousandths >= 600000 {
+		return 0, fmt.Errorf("invalid high precision point %q", point)
+	}
+	v := float64(deg) + float64(min)/60 + float64(tenThousandths)/1e4/3600
+	if point[0] == hemispheres[1] {
+		v = -v
+	}
+	return v, nil
+}
+
+// PathPoint is a decoded final approach segment of an SBAS or GBAS approach.
+// Numerical fields are zero if they are blank in the record.
+type PathPoint struct {
+	AirportID       string
+	ApproachID      string
+	RunwayID        string
+	ReferencePathID string
+	// OperationType is zero for straight-in approaches.
+	OperationType int
+	// SBASProvider identifies the SBAS service provider, such as 0 for WAAS.
+	SBASProvider              int
+	ReferencePathDataSelector int
+	// LTPLat and LTPLon are the position of the landing threshold point, and
+	// FPAPLat and FPAPLon are the position of the flight path alignment
+	// point, in decimal degrees.
+	LTPLat, LTPLon   float64
+	FPAPLat, FPAPLon float64
+	// LTPEllipsoidHeight is the height of the landing threshold point above
+	// the WGS-84 ellipsoid in meters.
+	LTPEllipsoidHeight float64
+	// GlidePathAngle is in degrees.
+	GlidePathAngle float64
+	// CourseWidth is the width of the course at the threshold in meters.
+	CourseWidth float64
+	// LengthOffset is the distance from the stop end of the runway to the
+	// FPAP in meters.
+	LengthOffset int
+	// TCH is the threshold crossing height, in meters if TCHInMeters is true
+	// and in feet otherwise.
+	TCH         float64
+	TCHInMeters bool
+	// HAL and VAL are the horizontal and vertical alert limits in meters.
+	HAL, VAL float64
+}
+
+// PathPoint decodes the fields of the path point record. If any field is
+// malformed, an error naming the field is returned.
+func (r *AirportPathPointPrimaryRecord) PathPoint() (*PathPoint, error) {
+	p := &PathPoint{
+		AirportID:       r.AirportID,
+		ApproachID:      r.ApproachProcedureID,
+		RunwayID:        r.RunwayOrHelipadID,
+		ReferencePathID: r.ReferencePathID,
+	}
+	var err error
+	if p.OperationType, err = parseDigits(r.OperationType, 2); err != nil {
+		return nil, fmt.Errorf("OperationType: %v", err)
+	}
+	if p.SBASProvider, err = parseDigits(r.SBASServiceProviderID, 2); err != nil {
+		return nil, fmt.Errorf("SBASServiceProviderID: %v", err)
+	}
+	if p.ReferencePathDataSelector, err = parseDigits(r.ReferencePathDataSelector, 2); err != nil {
+		return nil, fmt.Errorf("ReferencePathDataSelector: %v", err)
+	}
+	if p.LTPLat, p.LTPLon, err = ParseHighPrecisionLatLon(r.LTPLatitude, r.LTPLongitude); err != nil {
+		return nil, fmt.Errorf("LTPLatitude/LTPLongitude: %v", err)
+	}
+	if p.FPAPLat, p.FPAPLon, err = ParseHighPrecisionLatLon(r.FPAPLatitude, r.FPAPLongitude); err != nil {
+		return nil, fmt.Errorf("FPAPLatitude/FPAPLongitude: %v", err)
+	}
+	if p.LTPEllipsoidHeight, err = parseEllipsoidHeight(r.LTPEllipsoidHeight); err != nil {
+		return nil, fmt.Errorf("LTPEllipsoidHeight: %v", err)
+	}
+	angle, err := parseDigits(r.GlidePathAngle, 4)
+	if err != nil || angle == 0 {
+		return nil, fmt.Errorf("GlidePathAngle: invalid glide path angle %q", r.GlidePathAngle)
+	}
+	p.GlidePathAngle = float64(angle) / 100
+	width, err := parseDigits(r.CourseWidthAtThreshold, 5)
+	if err != nil {
+		return nil, fmt.Errorf("CourseWidthAtThreshold: %v", err)
+	}
+	p.CourseWidth = float64(width) / 100
+	if r.LengthOffset != "" {
+		if p.LengthOffset, err = parseDigits(r.LengthOffset, 4); err != nil {
+			return nil, fmt.Errorf("LengthOffset: %v", err)
+		}
+	}
+	tch, err := parseDigits(r.PathPointTCH, 6)
+	if err != nil {
+		return nil, fmt.Errorf("PathPointTCH: %v", err)
+	}
+	p.TCH = float64(tch) / 10
+	switch r.TCHUnitsIndicator {
+	case "F":
+	case "M":
+		p.TCHInMeters = true
+	default:
+		return nil, fmt.Errorf("TCHUnitsIndicator: invalid units %q", r.TCHUnitsIndicator)
+	}
+	hal, err := parseDigits(r.HAL, 3)
+	if err != nil {
+		return nil, fmt.Errorf("HAL: %v", err)
+	}
+	p.HAL = float64(hal) / 10
+	if r.VAL != "" {
+		val, err := parseDigits(r.VAL, 3)
+		if err != nil {
+			return nil, fmt.Errorf("VAL: %v", err)
+		}
+		p.VAL = float64(val) / 10
+	}
+	return p, nil
+}
+
+// TrueFinalApproachCourse returns the true course of the final approach
+// segment in degrees in the range [0, 360), which is the initial bearing of
+// the geodesic from the landing threshold point to the flight path alignment
+// point.
+func (p *PathPoint) TrueFinalApproachCourse() (float64, error) {
+	return geodesy.InitialBearing(p.LTPLat, p.LTPLon, p.FPAPLat, p.FPAPLon)
+}
+
+// CRC returns the published CRC remainder of the FAS data block. If the field
+// is not eight hexadecimal digits, an error is returned.
+func (r *AirportPathPointPrimaryRecord) CRC() (uint32, error) {
+	if len(r.FASDataCRCRemainder) != 8 {
+		return 0, fmt.Errorf("FASDataCRCRemainder: invalid CRC %q, want 8 characters", r.FASDataCRCRemainder)
+	}
+	crc, err := strconv.ParseUint(r.FASDataCRCRemainder, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("FASDataCRCRemainder: invalid CRC %q", r.FASDataCRCRemainder)
+	}
+	return uint32(crc), nil
+}
+
+// parseDigits returns the value of a string of exactly n digits.
+func parseDigits(s string, n int) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 0 || len(s) != n || s[0] == '+' {
+		return 0, fmt.Errorf("invalid value %q, want %d digits", s, n)
+	}
+	return v, nil
+}
+
+// parseEllipsoidHeight returns the height in meters of the provided six
+// character signed height in tenths of a meter, such as "+00118".
+func parseEllipsoidHeight(s string) (float64, error) {
+	if len(s) != 6 || (s[0] != '+' && s[0] != '-') {
+		return 0, fmt.Errorf("invalid ellipsoid height %q", s)
+	}
+	tenths, err := parseDigits(s[1:], 5)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ellipsoid height %q", s)
+	}
+	h := float64(tenths) / 10
+	if s[0] == '-' {
+		h = -h
+	}
+	return h, nil
+}
@@ -0,0 +1,250 @@
+package arinc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	testPathPointRecord     = "SUSAP KHWDK2PR28L  RW28L001A0001W28A0N3739450000W12207300000+001180300N3730000000W12207300000106050008000550F400500B0B82C38123452002"
+	testPathPointContRecord = "SUSAP KHWDK2PR28L  RW28L002E      +00118+00118LPV       21473                                                              123462002"
+)
+
+func TestParseHighPrecisionLatLon(t *testing.T) {
+	const tolerance = 0.000000001
+	for _, tt := range []struct {
+		name      string
+		latitude  string
+		longitude string
+		wantLat   float64
+		wantLon   float64
+		wantErr   bool
+	}{
+		{
+			name:      "NorthWest",
+			latitude:  "N3739452500",
+			longitude: "W12207300001",
+			wantLat:   37 + 39.0/60 + 45.25/3600,
+			wantLon:   -(122 + 7.0/60 + 30.0001/3600),
+		},
+		{
+			name:      "SouthEast",
+			latitude:  "S3352000000",
+			longitude: "E15110000000",
+			wantLat:   -(33 + 52.0/60),
+			wantLon:   151 + 10.0/60,
+		},
+		{
+			name:      "ShortLatitude",
+			latitude:  "N37394525",
+			longitude: "W12207300001",
+			wantErr:   true,
+		},
+		{
+			name:      "LatitudeHemisphere",
+			latitude:  "E3739452500",
+			longitude: "W12207300001",
+			wantErr:   true,
+		},
+		{
+			name:      "LongitudeHemisphere",
+			latitude:  "N3739452500",
+			longitude: "N12207300001",
+			wantErr:   true,
+		},
+		{
+			name:      "Minutes",
+			latitude:  "N3760452500",
+			longitude: "W12207300001",
+			wantErr:   true,
+		},
+		{
+			name:      "Seconds",
+			latitude:  "N3739602500",
+			longitude: "W12207300001",
+			wantErr:   true,
+		},
+		{
+			name:      "NotNumber",
+			latitude:  "N37394525X0",
+			longitude: "W12207300001",
+			wantErr:   true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			gotLat, gotLon, err := ParseHighPrecisionLatLon(tt.latitude, tt.longitude)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseHighPrecisionLatLon(%q, %q) = %f, %f, <nil> want _, _, <non-nil>", tt.latitude, tt.longitude, gotLat, gotLon)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseHighPrecisionLatLon(%q, %q) = _, _, %v want _, _, <nil>", tt.latitude, tt.longitude, err)
+			}
+			if math.Abs(gotLat-tt.wantLat) > tolerance || math.Abs(gotLon-tt.wantLon) > tolerance {
+				t.Errorf("ParseHighPrecisionLatLon(%q, %q) = %.9f, %.9f want %.9f, %.9f", tt.latitude, tt.longitude, gotLat, gotLon, tt.wantLat, tt.wantLon)
+			}
+		})
+	}
+}
+
+func TestPathPoint(t *testing.T) {
+	rec, err := Parse([]byte(testPathPointRecord))
+	if err != nil {
+		t.Fatalf("Parse() = %v want <nil>", err)
+	}
+	got, err := rec.(*AirportPathPointPrimaryRecord).PathPoint()
+	if err != nil {
+		t.Fatalf("PathPoint() = _, %v want _, <nil>", err)
+	}
+	want := &PathPoint{
+		AirportID:                 "KHWD",
+		ApproachID:                "R28L",
+		RunwayID:                  "RW28L",
+		ReferencePathID:           "W28A",
+		ReferencePathDataSelector: 1,
+		LTPLat:                    37 + 39.0/60 + 45.0/3600,
+		LTPLon:                    -(122 + 7.0/60 + 30.0/3600),
+		FPAPLat:                   37 + 30.0/60,
+		FPAPLon:                   -(122 + 7.0/60 + 30.0/3600),
+		LTPEllipsoidHeight:        11.8,
+		GlidePathAngle:            3,
+		CourseWidth:               106.05,
+		LengthOffset:              8,
+		TCH:                       55,
+		HAL:                       40,
+		VAL:                       50,
+	}
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(x, y float64) bool { return math.Abs(x-y) < 0.0000001 })); diff != "" {
+		t.Errorf("PathPoint() had diffs (-want +got): %s", diff)
+	}
+}
+
+func TestTrueFinalApproachCourse(t *testing.T) {
+	const tolerance = 0.0001
+	ltp := [2]float64{37.6625, -122.125}
+	for _, tt := range []struct {
+		name string
+		fpap [2]float64
+		want float64
+	}{
+		{"South", [2]float64{37.5, -122.125}, 180},
+		{"North", [2]float64{37.8, -122.125}, 0},
+		// The geodesic to the west starts slightly north of west because of
+		// the convergence of the meridians.
+		{"West", [2]float64{37.6625, -122.3}, 270.0535},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &PathPoint{LTPLat: ltp[0], LTPLon: ltp[1], FPAPLat: tt.fpap[0], FPAPLon: tt.fpap[1]}
+			got, err := p.TrueFinalApproachCourse()
+			if err != nil {
+				t.Fatalf("TrueFinalApproachCourse() = _, %v want _, <nil>", err)
+			}
+			if math.Abs(got-tt.want) > tolerance {
+				t.Errorf("TrueFinalApproachCourse() = %f want %f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathPointErrors(t *testing.T) {
+	good := func() *AirportPathPointPrimaryRecord {
+		rec, err := Parse([]byte(testPathPointRecord))
+		if err != nil {
+			t.Fatalf("Parse() = %v want <nil>", err)
+		}
+		return rec.(*AirportPathPointPrimaryRecord)
+	}
+	for _, tt := range []struct {
+		name   string
+		modify func(r *AirportPathPointPrimaryRecord)
+	}{
+		{
+			name:   "OperationType",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.OperationType = "0" },
+		},
+		{
+			name:   "SBASServiceProviderID",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.SBASServiceProviderID = "XX" },
+		},
+		{
+			name:   "ReferencePathDataSelector",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.ReferencePathDataSelector = "" },
+		},
+		{
+			name:   "LTPLatitude",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.LTPLatitude = "N37394500" },
+		},
+		{
+			name:   "FPAPLongitude",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.FPAPLongitude = "" },
+		},
+		{
+			name:   "LTPEllipsoidHeight",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.LTPEllipsoidHeight = "000118" },
+		},
+		{
+			name:   "GlidePathAngle",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.GlidePathAngle = "0000" },
+		},
+		{
+			name:   "CourseWidthAtThreshold",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.CourseWidthAtThreshold = "106.0" },
+		},
+		{
+			name:   "LengthOffset",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.LengthOffset = "8" },
+		},
+		{
+			name:   "PathPointTCH",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.PathPointTCH = "+00550" },
+		},
+		{
+			name:   "TCHUnitsIndicator",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.TCHUnitsIndicator = "" },
+		},
+		{
+			name:   "HAL",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.HAL = "" },
+		},
+		{
+			name:   "VAL",
+			modify: func(r *AirportPathPointPrimaryRecord) { r.VAL = "5" },
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := good()
+			if _, err := r.PathPoint(); err != nil {
+				t.Fatalf("PathPoint() before modification = _, %v want _, <nil>", err)
+			}
+			tt.modify(r)
+			if _, err := r.PathPoint(); err == nil {
+				t.Errorf("PathPoint() = _, <nil> want _, <non-nil>")
+			}
+		})
+	}
+}
+
+func TestPathPointCRC(t *testing.T) {
+	rec, err := Parse([]byte(testPathPointRecord))
+	if err != nil {
+		t.Fatalf("Parse() = %v want <nil>", err)
+	}
+	r := rec.(*AirportPathPointPrimaryRecord)
+	got, err := r.CRC()
+	if err != nil {
+		t.Fatalf("CRC() = _, %v want _, <nil>", err)
+	}
+	if want := uint32(0xB0B82C38); got != want {
+		t.Errorf("CRC() = %08X want %08X", got, want)
+	}
+	for _, crc := range []string{"", "078B2ED", "078B2EDG"} {
+		r.FASDataCRCRemainder = crc
+		if got, err := r.CRC(); err == nil {
+			t.Errorf("CRC() with %q = %08X, <nil> want _, <non-nil>", crc, got)
+		}
+	}
+}
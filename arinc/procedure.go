@@ -0,0 +1,315 @@
+package arinc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ProcedureKind is the kind of an airport procedure, which is determined by
+// the subsection of its records.
+type ProcedureKind int
+
+const (
+	ProcedureSID ProcedureKind = iota
+	ProcedureSTAR
+	ProcedureApproach
+)
+
+// String returns the name of the procedure kind.
+func (k ProcedureKind) String() string {
+	switch k {
+	case ProcedureSID:
+		return "SID"
+	case ProcedureSTAR:
+		return "STAR"
+	case ProcedureApproach:
+		return "approach"
+	}
+	return fmt.Sprintf("ProcedureKind(%d)", k)
+}
+
+// procedureKinds maps subsection codes to procedure kinds.
+var procedureKinds = map[string]ProcedureKind{
+	SubsectionCodeSID:               ProcedureSID,
+	SubsectionCodeSTAR:              ProcedureSTAR,
+	SubsectionCodeApproachProcedure: ProcedureApproach,
+}
+
+// RouteType is the route type of a procedure transition, whose meaning
+// depends on the kind of the procedure. It is a SIDRouteType, a
+// STARRouteType, or an ApproachRouteType.
+// See 5.7 Route Type
+type RouteType interface {
+	// ProcedureKind returns the kind of procedure that the route type
+	// belongs to.
+	ProcedureKind() ProcedureKind
+}
+
+// SIDRouteType is the RouteType of a SID record.
+// See 5.7 Route Type
+type SIDRouteType string
+
+const (
+	SIDEngineOut               SIDRouteType = "0"
+	SIDRunwayTransition        SIDRouteType = "1"
+	SIDCommonRoute             SIDRouteType = "2"
+	SIDEnrouteTransition       SIDRouteType = "3"
+	SIDRNAVRunwayTransition    SIDRouteType = "4"
+	SIDRNAVCommonRoute         SIDRouteType = "5"
+	SIDRNAVEnrouteTransition   SIDRouteType = "6"
+	SIDFMSRunwayTransition     SIDRouteType = "F"
+	SIDFMSCommonRoute          SIDRouteType = "M"
+	SIDFMSEnrouteTransition    SIDRouteType = "S"
+	SIDVectorRunwayTransition  SIDRouteType = "T"
+	SIDVectorEnrouteTransition SIDRouteType = "V"
+)
+
+// ProcedureKind returns ProcedureSID.
+func (SIDRouteType) ProcedureKind() ProcedureKind { return ProcedureSID }
+
+// STARRouteType is the RouteType of a STAR record.
+// See 5.7 Route Type
+type STARRouteType string
+
+const (
+	STAREnrouteTransition               STARRouteType = "1"
+	STARCommonRoute                     STARRouteType = "2"
+	STARRunwayTransition                STARRouteType = "3"
+	STARRNAVEnrouteTransition           STARRouteType = "4"
+	STARRNAVCommonRoute                 STARRouteType = "5"
+	STARRNAVRunwayTransition            STARRouteType = "6"
+	STARProfileDescentEnrouteTransition STARRouteType = "7"
+	STARProfileDescentCommonRoute       STARRouteType = "8"
+	STARProfileDescentRunwayTransition  STARRouteType = "9"
+	STARFMSEnrouteTransition            STARRouteType = "F"
+	STARFMSCommonRoute                  STARRouteType = "M"
+	STARFMSRunwayTransition             STARRouteType = "S"
+)
+
+// ProcedureKind returns ProcedureSTAR.
+func (STARRouteType) ProcedureKind() ProcedureKind { return ProcedureSTAR }
+
+// ApproachRouteType is the RouteType of an approach procedure record.
+// See 5.7 Route Type
+type ApproachRouteType string
+
+const (
+	ApproachTransition    ApproachRouteType = "A"
+	ApproachLocBackcourse ApproachRouteType = "B"
+	ApproachVORDME        ApproachRouteType = "D"
+	ApproachFMS           ApproachRouteType = "F"
+	ApproachIGS           ApproachRouteType = "G"
+	ApproachRNP           ApproachRouteType = "H"
+	ApproachILS           ApproachRouteType = "I"
+	ApproachGLS           ApproachRouteType = "J"
+	ApproachLOC           ApproachRouteType = "L"
+	ApproachMLS           ApproachRouteType = "M"
+	ApproachNDB           ApproachRouteType = "N"
+	ApproachGPS           ApproachRouteType = "P"
+	ApproachNDBDME        ApproachRouteType = "Q"
+	ApproachRNAV          ApproachRouteType = "R"
+	ApproachVORTAC        ApproachRouteType = "S"
+	ApproachTACAN         ApproachRouteType = "T"
+	ApproachSDF           ApproachRouteType = "U"
+	ApproachVOR           ApproachRouteType = "V"
+	ApproachMLSTypeA      ApproachRouteType = "W"
+	ApproachLDA           ApproachRouteType = "X"
+	ApproachMLSTypeBAndC  ApproachRouteType = "Y"
+)
+
+// ProcedureKind returns ProcedureApproach.
+func (ApproachRouteType) ProcedureKind() ProcedureKind { return ProcedureApproach }
+
+var routeTypes = map[RouteType]bool{
+	SIDEngineOut: true, SIDRunwayTransition: true, SIDCommonRoute: true,
+	SIDEnrouteTransition: true, SIDRNAVRunwayTransition: true,
+	SIDRNAVCommonRoute: true, SIDRNAVEnrouteTransition: true,
+	SIDFMSRunwayTransition: true, SIDFMSCommonRoute: true,
+	SIDFMSEnrouteTransition: true, SIDVectorRunwayTransition: true,
+	SIDVectorEnrouteTransition: true,
+
+	STAREnrouteTransition: true, STARCommonRoute: true,
+	STARRunwayTransition: true, STARRNAVEnrouteTransition: true,
+	STARRNAVCommonRoute: true, STARRNAVRunwayTransition: true,
+	STARProfileDescentEnrouteTransition: true,
+	STARProfileDescentCommonRoute:       true,
+	STARProfileDescentRunwayTransition:  true,
+	STARFMSEnrouteTransition:            true, STARFMSCommonRoute: true,
+	STARFMSRunwayTransition: true,
+
+	ApproachTransition: true, ApproachLocBackcourse: true, ApproachVORDME: true,
+	ApproachFMS: true, ApproachIGS: true, ApproachRNP: true, ApproachILS: true,
+	ApproachGLS: true, ApproachLOC: true, ApproachMLS: true, ApproachNDB: true,
+	ApproachGPS: true, ApproachNDBDME: true, ApproachRNAV: true,
+	ApproachVORTAC: true, ApproachTACAN: true, ApproachSDF: true,
+	ApproachVOR: true, ApproachMLSTypeA: true, ApproachLDA: true,
+	ApproachMLSTypeBAndC: true,
+}
+
+// ParseRouteType returns the route type of the provided string for a
+// procedure of the given kind. If it is not a valid route type for that kind
+// of procedure, an error is returned.
+func ParseRouteType(kind ProcedureKind, s string) (RouteType, error) {
+	var rt RouteType
+	switch kind {
+	case ProcedureSID:
+		rt = SIDRouteType(s)
+	case ProcedureSTAR:
+		rt = STARRouteType(s)
+	case ProcedureApproach:
+		rt = ApproachRouteType(s)
+	default:
+		return nil, fmt.Errorf("invalid procedure kind %v", kind)
+	}
+	if !routeTypes[rt] {
+		return nil, fmt.Errorf("invalid %v route type %q", kind, s)
+	}
+	return rt, nil
+}
+
+// Kind returns the kind of procedure that the record belongs to. If the
+// record is not in a procedure subsection, an error is returned.
+func (p *AirportProcedurePrimaryRecord) Kind() (ProcedureKind, error) {
+	k, ok := procedureKinds[p.SubsectionCode]
+	if !ok {
+		return 0, fmt.Errorf("invalid procedure subsection code %q", p.SubsectionCode)
+	}
+	return k, nil
+}
+
+// Procedure is a SID, STAR, or approach procedure at an airport.
+type Procedure struct {
+	AirportID string
+	Kind      ProcedureKind
+	ID        string
+	// Transitions are in the order that they first appear in the data.
+	Transitions []*Transition
+}
+
+// Transition is a route within a procedure, such as an enroute transition,
+// a runway transition, or a common route.
+type Transition struct {
+	// RouteType is the route type of the legs. Its concrete type matches the
+	// kind of the procedure.
+	RouteType RouteType
+	// ID is the transition identifier. It is "ALL" or empty for common
+	// routes, and empty for the final approach segment.
+	ID string
+	// Legs are sorted by SequenceNumber.
+	Legs []*AirportProcedurePrimaryRecord
+}
+
+// Transition returns the first transition in the procedure with the given
+// identifier, or nil if there is none.
+func (p *Procedure) Transition(id string) *Transition {
+	for _, t := range p.Transitions {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// RouteTransition returns the transition in the procedure with the given
+// route type and identifier, or nil if there is none.
+func (p *Procedure) RouteTransition(routeType RouteType, id string) *Transition {
+	for _, t := range p.Transitions {
+		if t.RouteType == routeType && t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+type procedureKey struct {
+	airportID string
+	kind      ProcedureKind
+	id        string
+}
+
+// ProcedureAssembler groups procedure records into procedures and
+// transitions. The records may be added in any order.
+type ProcedureAssembler struct {
+	procedures map[procedureKey]*Procedure
+}
+
+// NewProcedureAssembler returns a new, empty ProcedureAssembler.
+func NewProcedureAssembler() *ProcedureAssembler {
+	return &ProcedureAssembler{procedures: make(map[procedureKey]*Procedure)}
+}
+
+// Add adds the record to its procedure and transition. Continuation records
+// are ignored. If the record is not in a procedure subsection, or its route
+// type or sequence number is invalid, an error is returned.
+func (a *ProcedureAssembler) Add(rec *AirportProcedurePrimaryRecord) error {
+	if rec.ContinuationRecordNumber != "0" && rec.ContinuationRecordNumber != "1" {
+		return nil
+	}
+	kind, err := rec.Kind()
+	if err != nil {
+		return err
+	}
+	routeType, err := ParseRouteType(kind, rec.RouteType)
+	if err != nil {
+		return fmt.Errorf("%v for procedure %q at %q", err, rec.ProcedureID, rec.AirportID)
+	}
+	seq, err := sequenceNumber(rec)
+	if err != nil {
+		return err
+	}
+	k := procedureKey{rec.AirportID, kind, rec.ProcedureID}
+	proc, ok := a.procedures[k]
+	if !ok {
+		proc = &Procedure{AirportID: rec.AirportID, Kind: kind, ID: rec.ProcedureID}
+		a.procedures[k] = proc
+	}
+	t := proc.RouteTransition(routeType, rec.TransitionID)
+	if t == nil {
+		t = &Transition{RouteType: routeType, ID: rec.TransitionID}
+		proc.Transitions = append(proc.Transitions, t)
+	}
+	// The sequence numbers of the legs were checked when they were added.
+	i := sort.Search(len(t.Legs), func(i int) bool {
+		s, _ := sequenceNumber(t.Legs[i])
+		return s > seq
+	})
+	t.Legs = append(t.Legs, nil)
+	copy(t.Legs[i+1:], t.Legs[i:])
+	t.Legs[i] = rec
+	return nil
+}
+
+// sequenceNumber returns the numerical sequence number of the record.
+func sequenceNumber(rec *AirportProcedurePrimaryRecord) (int, error) {
+	seq, err := strconv.Atoi(rec.SequenceNumber)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sequence number %q for procedure %q at %q", rec.SequenceNumber, rec.ProcedureID, rec.AirportID)
+	}
+	return seq, nil
+}
+
+// Procedure returns the procedure with the given kind and identifier at the
+// airport, or nil if there is none.
+func (a *ProcedureAssembler) Procedure(airportID string, kind ProcedureKind, id string) *Procedure {
+	return a.procedures[procedureKey{airportID, kind, id}]
+}
+
+// Procedures returns all of the procedures, sorted by airport, kind, and
+// identifier.
+func (a *ProcedureAssembler) Procedures() []*Procedure {
+	procs := make([]*Procedure, 0, len(a.procedures))
+	for _, p := range a.procedures {
+		procs = append(procs, p)
+	}
+	sort.Slice(procs, func(i, j int) bool {
+		if procs[i].AirportID != procs[j].AirportID {
+			return procs[i].AirportID < procs[j].AirportID
+		}
+		if procs[i].Kind != procs[j].Kind {
+			return procs[i].Kind < procs[j].Kind
+		}
+		return procs[i].ID < procs[j].ID
+	})
+	return procs
+}
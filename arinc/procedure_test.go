@@ -0,0 +1,255 @@
+package arinc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// procedureTestData is out of order to check that legs are sorted.
+var procedureTestData = []string{
+	"SUSAP KHWDK2EPXN6  1GMN   030PXN  K2D 0VE      TF                                                                          108191909",
+	"SUSAP KHWDK2EPXN6  1AVE   010AVE  K2D 0V       IF                                             18000                        108151909",
+	"SUSAP KHWDK2EPXN6  2ALL   020KARNNK2EA0E       TF                                                                          108211909",
+	"SUSAP KHWDK2EPXN6  1GMN   010GMN  K2D 0V       IF                                             18000                        108171909",
+	"SUSAP KHWDK2EPXN6  1AVE   020PXN  K2D 0VE      TF                                                                          108161909",
+	"SUSAP KHWDK2EPXN6  1GMN   020SRENAK2EA0E       TF                                                                          108181909",
+	"SUSAP KHWDK2EPXN6  2ALL   010PXN  K2D 0V       IF                                             18000                        108201909",
+	"SUSAP KBURK2DELMOO91RW08  010         0        CA                     0789        + 01178     18000                        360941905",
+	"SUSAP KHWDK2FL28L  L      010JIBANK2PC0E  I    IF IHWDK2      10790127        PI  + 03700     18000                 0 DS   108511310",
+	"SUSAP KHWDK2ESHARR15ALL   010SHARRK2EA0E       IF                                             18000                        108441707",
+}
+
+func assembleProcedures(t *testing.T) *ProcedureAssembler {
+	t.Helper()
+	a := NewProcedureAssembler()
+	for _, line := range procedureTestData {
+		rec, err := Parse([]byte(line))
+		if err != nil {
+			t.Fatalf("Parse(%q) = %v want <nil>", line, err)
+		}
+		if err := a.Add(rec.(*AirportProcedurePrimaryRecord)); err != nil {
+			t.Fatalf("Add(%q) = %v want <nil>", line, err)
+		}
+	}
+	return a
+}
+
+func TestProcedureAssemblerTransition(t *testing.T) {
+	a := assembleProcedures(t)
+	for _, tt := range []struct {
+		name       string
+		airportID  string
+		kind       ProcedureKind
+		procedure  string
+		transition string
+		want       []string
+	}{
+		{
+			name:       "STAREnrouteTransition",
+			airportID:  "KHWD",
+			kind:       ProcedureSTAR,
+			procedure:  "PXN6",
+			transition: "GMN",
+			want:       []string{"GMN", "SRENA", "PXN"},
+		},
+		{
+			name:       "STARCommonRoute",
+			airportID:  "KHWD",
+			kind:       ProcedureSTAR,
+			procedure:  "PXN6",
+			transition: "ALL",
+			want:       []string{"PXN", "KARNN"},
+		},
+		{
+			name:       "SID",
+			airportID:  "KBUR",
+			kind:       ProcedureSID,
+			procedure:  "ELMOO9",
+			transition: "RW08",
+			want:       []string{""},
+		},
+		{
+			name:       "Approach",
+			airportID:  "KHWD",
+			kind:       ProcedureApproach,
+			procedure:  "L28L",
+			transition: "",
+			want:       []string{"JIBAN"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			proc := a.Procedure(tt.airportID, tt.kind, tt.procedure)
+			if proc == nil {
+				t.Fatalf("Procedure(%q, %v, %q) = <nil> want <non-nil>", tt.airportID, tt.kind, tt.procedure)
+			}
+			trans := proc.Transition(tt.transition)
+			if trans == nil {
+				t.Fatalf("Transition(%q) = <nil> want <non-nil>", tt.transition)
+			}
+			var got []string
+			for _, leg := range trans.Legs {
+				got = append(got, leg.FixID)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Transition(%q) legs had diffs (-want +got): %s", tt.transition, diff)
+			}
+		})
+	}
+}
+
+func TestProcedureAssemblerProcedures(t *testing.T) {
+	a := assembleProcedures(t)
+	type procedureSummary struct {
+		AirportID   string
+		Kind        ProcedureKind
+		ID          string
+		Transitions []string
+	}
+	want := []procedureSummary{
+		{AirportID: "KBUR", Kind: ProcedureSID, ID: "ELMOO9", Transitions: []string{"1RW08"}},
+		{AirportID: "KHWD", Kind: ProcedureSTAR, ID: "PXN6", Transitions: []string{"1GMN", "1AVE", "2ALL"}},
+		{AirportID: "KHWD", Kind: ProcedureSTAR, ID: "SHARR1", Transitions: []string{"5ALL"}},
+		{AirportID: "KHWD", Kind: ProcedureApproach, ID: "L28L", Transitions: []string{"L"}},
+	}
+	var got []procedureSummary
+	for _, p := range a.Procedures() {
+		s := procedureSummary{AirportID: p.AirportID, Kind: p.Kind, ID: p.ID}
+		for _, t := range p.Transitions {
+			s.Transitions = append(s.Transitions, fmt.Sprint(t.RouteType)+t.ID)
+		}
+		got = append(got, s)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Procedures() had diffs (-want +got): %s", diff)
+	}
+	if got := a.Procedure("KHWD", ProcedureSID, "PXN6"); got != nil {
+		t.Errorf("Procedure(%q, %v, %q) = %v want <nil>", "KHWD", ProcedureSID, "PXN6", got)
+	}
+}
+
+func TestProcedureAssemblerAdd(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		record  *AirportProcedurePrimaryRecord
+		wantErr bool
+	}{
+		{
+			name: "Good",
+			record: &AirportProcedurePrimaryRecord{
+				AirportEnrouteRecord:     AirportEnrouteRecord{AirportID: "KHWD", SubsectionCode: SubsectionCodeSTAR},
+				ProcedureID:              "PXN6",
+				RouteType:                "1",
+				SequenceNumber:           "010",
+				ContinuationRecordNumber: "0",
+			},
+		},
+		{
+			name: "InvalidRouteType",
+			record: &AirportProcedurePrimaryRecord{
+				AirportEnrouteRecord:     AirportEnrouteRecord{AirportID: "KHWD", SubsectionCode: SubsectionCodeSTAR},
+				ProcedureID:              "PXN6",
+				RouteType:                "A",
+				SequenceNumber:           "010",
+				ContinuationRecordNumber: "0",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Continuation",
+			record: &AirportProcedurePrimaryRecord{
+				AirportEnrouteRecord:     AirportEnrouteRecord{AirportID: "KHWD", SubsectionCode: SubsectionCodeSTAR},
+				ProcedureID:              "PXN6",
+				ContinuationRecordNumber: "2",
+			},
+		},
+		{
+			name: "InvalidSubsection",
+			record: &AirportProcedurePrimaryRecord{
+				AirportEnrouteRecord:     AirportEnrouteRecord{AirportID: "KHWD", SubsectionCode: SubsectionCodeRunway},
+				SequenceNumber:           "010",
+				ContinuationRecordNumber: "0",
+			},
+			wantErr: true,
+		},
+		{
+			name: "InvalidSequenceNumber",
+			record: &AirportProcedurePrimaryRecord{
+				AirportEnrouteRecord:     AirportEnrouteRecord{AirportID: "KHWD", SubsectionCode: SubsectionCodeSID},
+				RouteType:                "1",
+				SequenceNumber:           "0X0",
+				ContinuationRecordNumber: "0",
+			},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewProcedureAssembler().Add(tt.record)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Add() = <nil> want <non-nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Add() = %v want <nil>", err)
+			}
+		})
+	}
+}
+
+func TestProcedureKindString(t *testing.T) {
+	for _, tt := range []struct {
+		kind ProcedureKind
+		want string
+	}{
+		{ProcedureSID, "SID"},
+		{ProcedureSTAR, "STAR"},
+		{ProcedureApproach, "approach"},
+		{ProcedureKind(7), "ProcedureKind(7)"},
+	} {
+		if got := tt.kind.String(); got != tt.want {
+			t.Errorf("String() = %q want %q", got, tt.want)
+		}
+	}
+}
+
+func TestParseRouteType(t *testing.T) {
+	for _, tt := range []struct {
+		kind    ProcedureKind
+		s       string
+		want    RouteType
+		wantErr bool
+	}{
+		{kind: ProcedureSID, s: "5", want: SIDRNAVCommonRoute},
+		{kind: ProcedureSID, s: "V", want: SIDVectorEnrouteTransition},
+		{kind: ProcedureSTAR, s: "9", want: STARProfileDescentRunwayTransition},
+		{kind: ProcedureSTAR, s: "F", want: STARFMSEnrouteTransition},
+		{kind: ProcedureApproach, s: "I", want: ApproachILS},
+		{kind: ProcedureApproach, s: "A", want: ApproachTransition},
+		{kind: ProcedureSID, s: "7", wantErr: true},
+		{kind: ProcedureSTAR, s: "A", wantErr: true},
+		{kind: ProcedureApproach, s: "1", wantErr: true},
+		{kind: ProcedureApproach, s: " ", wantErr: true},
+		{kind: ProcedureKind(7), s: "1", wantErr: true},
+	} {
+		got, err := ParseRouteType(tt.kind, tt.s)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseRouteType(%v, %q) = %v, <nil> want _, <non-nil>", tt.kind, tt.s, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRouteType(%v, %q) = _, %v want _, <nil>", tt.kind, tt.s, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRouteType(%v, %q) = %#v want %#v", tt.kind, tt.s, got, tt.want)
+		}
+		if got.ProcedureKind() != tt.kind {
+			t.Errorf("ParseRouteType(%v, %q).ProcedureKind() = %v want %v", tt.kind, tt.s, got.ProcedureKind(), tt.kind)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package arinc
+
+import (
+	"fmt"
+
+	fixedwidth "github.com/ianlopshire/go-fixedwidth"
+)
+
+// TypedRecord is a record that has been parsed into the struct type for its
+// section and subsection. All record types in this package implement it.
+type TypedRecord interface {
+	// Header returns the fields common to all records. Only the fields in
+	// columns 1 through 6 are set for record types that embed Record.
+	Header() *Record
+}
+
+// Header implements TypedRecord.
+func (r *Record) Header() *Record {
+	return r
+}
+
+// AirportRecord is a record that belongs to an airport or heliport.
+type AirportRecord interface {
+	TypedRecord
+	// Airport returns the fields common to all airport records.
+	Airport() *AirportEnrouteRecord
+}
+
+// Airport implements AirportRecord.
+func (r *AirportEnrouteRecord) Airport() *AirportEnrouteRecord {
+	return r
+}
+
+// airportLikeSections are the sections whose subsection code is in column 13
+// instead of column 6.
+var airportLikeSections = map[string]bool{
+	SectionCodeAirport:  true,
+	SectionCodeHeliport: true,
+}
+
+// SectionAndSubsection returns the section and subsection codes of the record.
+// Airport and heliport records have their subsection code in column 13, and
+// all other records have it in column 6.
+func SectionAndSubsection(line []byte) (section, subsection string, err error) {
+	if len(line) < 6 {
+		return "", "", fmt.Errorf("record is too short: %d characters", len(line))
+	}
+	section = string(line[4])
+	if !airportLikeSections[section] {
+		return section, trimBlank(line[5]), nil
+	}
+	if len(line) < 13 {
+		return "", "", fmt.Errorf("airport record is too short: %d characters", len(line))
+	}
+	return section, trimBlank(line[12]), nil
+}
+
+// trimBlank returns the character as a string, or an empty string if it is a
+// space. This matches the left aligned fixedwidth decoding of the field.
+func trimBlank(c byte) string {
+	if c == ' ' {
+		return ""
+	}
+	return string(c)
+}
+
+// registration is a record type in the registry.
+type registration struct {
+	newRecord func() TypedRecord
+	match     func(line []byte) bool
+}
+
+type sectionSubsection struct {
+	section, subsection string
+}
+
+// registry holds the record types known to Parse.
+var registry = make(map[sectionSubsection][]registration)
+
+// Register adds a record type to the registry used by Parse for records with
+// the given section and subsection codes. newRecord returns a new, empty
+// record of the type. If match is not nil, the type is only used for records
+// that it returns true for, which allows continuation records to have a
+// different type from primary records. Types with a match function are tried
+// in the order that they were registered, before the type without one.
+func Register(section, subsection string, newRecord func() TypedRecord, match func(line []byte) bool) {
+	k := sectionSubsection{section, subsection}
+	registry[k] = append(registry[k], registration{newRecord: newRecord, match: match})
+}
+
+// Parse parses the record into the struct type registered for its section and
+// subsection. Records of an unknown type are parsed into an
+// AirportEnrouteRecord if they are in the airport or heliport section, and a
+// Record otherwise. Records that are too short to have a subsection are also
+// parsed into a Record.
+func Parse(line []byte) (TypedRecord, error) {
+	var rec TypedRecord = &Record{}
+	if section, subsection, err := SectionAndSubsection(line); err == nil {
+		rec = newRecord(line, section, subsection)
+	}
+	if err := fixedwidth.Unmarshal(line, rec); err != nil {
+		return nil, fmt.Errorf("problem unmarshalling %T: %w", rec, err)
+	}
+	return rec, nil
+}
+
+// newRecord returns a new, empty record of the type for the line.
+func newRecord(line []byte, section, subsection string) TypedRecord {
+	var fallback func() TypedRecord
+	for _, r := range registry[sectionSubsection{section, subsection}] {
+		if r.match == nil {
+			if fallback == nil {
+				fallback = r.newRecord
+			}
+			continue
+		}
+		if r.match(line) {
+			return r.newRecord()
+		}
+	}
+	if fallback != nil {
+		return fallback()
+	}
+	if airportLikeSections[section] {
+		return &AirportEnrouteRecord{}
+	}
+	return &Record{}
+}
+
+// isSimContinuation returns true if the localizer record is a simulation
+// continuation record.
+func isSimContinuation(line []byte) bool {
+	return len(line) > 22 && line[21] != '0' && line[21] != '1' && string(line[22]) == ContinuationRecordSimulation
+}
+
+// isCommunicationContinuation returns true if the airport communication
+// record is a continuation record.
+func isCommunicationContinuation(line []byte) bool {
+	return len(line) > 24 && line[24] != '0' && line[24] != '1'
+}
+
+// isPrimary returns a match function that is true for primary records, whose
+// continuation record number in the given column is 0 or 1. Continuation
+// records of types that use it have a different layout that is not modelled,
+// so they are parsed into the fallback type for their section.
+func isPrimary(column int) func(line []byte) bool {
+	return func(line []byte) bool {
+		return len(line) >= column && (line[column-1] == '0' || line[column-1] == '1')
+	}
+}
+
+// isPathPointContinuation returns true if the path point record is a
+// continuation record.
+func isPathPointContinuation(line []byte) bool {
+	return len(line) > 26 && line[26] != '0' && line[26] != '1'
+}
+
+func init() {
+	Register(SectionCodeNavaid, SubsectionCodeNavaidVHF, func() TypedRecord { return &VHFNavaidRecord{} }, isPrimary(22))
+	Register(SectionCodeNavaid, SubsectionCodeNavaidNDB, func() TypedRecord { return &NDBNavaidRecord{} }, isPrimary(22))
+	Register(SectionCodeEnroute, SubsectionCodeEnrouteWaypoint, func() TypedRecord { return &WaypointPrimaryRecord{} }, isPrimary(22))
+	Register(SectionCodeEnroute, SubsectionCodeEnrouteHolding, func() TypedRecord { return &HoldingPatternRecord{} }, isPrimary(39))
+	Register(SectionCodeEnroute, SubsectionCodeEnrouteAirway, func() TypedRecord { return &EnrouteAirwayRecord{} }, isPrimary(39))
+	Register(SectionCodeAirspace, SubsectionCodeControlled, func() TypedRecord { return &ControlledAirspaceRecord{} }, isPrimary(25))
+	Register(SectionCodeAirspace, SubsectionCodeRestrictive, func() TypedRecord { return &RestrictiveAirspaceRecord{} }, isPrimary(25))
+	Register(SectionCodeAirport, SubsectionCodeAirportRefPoint, func() TypedRecord { return &AirportPrimaryRecord{} }, isPrimary(22))
+	Register(SectionCodeAirport, SubsectionCodeTerminalWaypoint, func() TypedRecord { return &WaypointPrimaryRecord{} }, isPrimary(22))
+	Register(SectionCodeAirport, SubsectionCodeTerminalNDB, func() TypedRecord { return &TerminalNDBRecord{} }, isPrimary(22))
+	Register(SectionCodeAirport, SubsectionCodeLocalizerMarker, func() TypedRecord { return &AirportLocalizerMarkerRecord{} }, isPrimary(22))
+	Register(SectionCodeAirport, SubsectionCodeSID, func() TypedRecord { return &AirportProcedurePrimaryRecord{} }, isPrimary(39))
+	Register(SectionCodeAirport, SubsectionCodeSTAR, func() TypedRecord { return &AirportProcedurePrimaryRecord{} }, isPrimary(39))
+	Register(SectionCodeAirport, SubsectionCodeApproachProcedure, func() TypedRecord { return &AirportProcedurePrimaryRecord{} }, isPrimary(39))
+	Register(SectionCodeAirport, SubsectionCodeRunway, func() TypedRecord { return &AirportRunwayPrimaryRecord{} }, isPrimary(22))
+	Register(SectionCodeAirport, SubsectionCodeMSA, func() TypedRecord { return &AirportMSARecord{} }, nil)
+	Register(SectionCodeAirport, SubsectionCodeTAA, func() TypedRecord { return &AirportTAARecord{} }, nil)
+	Register(SectionCodeAirport, SubsectionCodeCommunication, func() TypedRecord { return &AirportCommunicationContinuationRecord{} }, isCommunicationContinuation)
+	Register(SectionCodeAirport, SubsectionCodeCommunication, func() TypedRecord { return &AirportCommunicationPrimaryRecord{} }, nil)
+	Register(SectionCodeAirport, SubsectionCodeLocGS, func() TypedRecord { return &AirportLocGSSimContinuationRecord{} }, isSimContinuation)
+	Register(SectionCodeAirport, SubsectionCodeLocGS, func() TypedRecord { return &AirportLocGSPrimaryRecord{} }, isPrimary(22))
+	Register(SectionCodeAirport, SubsectionCodePathPoint, func() TypedRecord { return &AirportPathPointContinuationRecord{} }, isPathPointContinuation)
+	Register(SectionCodeAirport, SubsectionCodePathPoint, func() TypedRecord { return &AirportPathPointPrimaryRecord{} }, nil)
+	Register(SectionCodeAirport, SubsectionCodeGLS, func() TypedRecord { return &AirportGLSRecord{} }, isPrimary(22))
+	Register(SectionCodeHeliport, SubsectionCodeAirportRefPoint, func() TypedRecord { return &HeliportPrimaryRecord{} }, isPrimary(22))
+	Register(SectionCodeHeliport, SubsectionCodeTerminalWaypoint, func() TypedRecord { return &WaypointPrimaryRecord{} }, isPrimary(22))
+	Register(SectionCodeHeliport, SubsectionCodeSID, func() TypedRecord { return &AirportProcedurePrimaryRecord{} }, isPrimary(39))
+	Register(SectionCodeHeliport, SubsectionCodeSTAR, func() TypedRecord { return &AirportProcedurePrimaryRecord{} }, isPrimary(39))
+	Register(SectionCodeHeliport, SubsectionCodeApproachProcedure, func() TypedRecord { return &AirportProcedurePrimaryRecord{} }, isPrimary(39))
+	Register(SectionCodeHeliport, SubsectionCodeHelipad, func() TypedRecord { return &HelipadRecord{} }, isPrimary(22))
+	Register(SectionCodeHeliport, SubsectionCodePathPoint, func() TypedRecord { return &AirportPathPointContinuationRecord{} }, isPathPointContinuation)
+	Register(SectionCodeHeliport, SubsectionCodePathPoint, func() TypedRecord { return &AirportPathPointPrimaryRecord{} }, nil)
+	Register(SectionCodeHeliport, SubsectionCodeGLS, func() TypedRecord { return &AirportGLSRecord{} }, isPrimary(22))
+	Register(SectionCodeHeliport, SubsectionCodeMSA, func() TypedRecord { return &AirportMSARecord{} }, nil)
+	Register(SectionCodeHeliport, SubsectionCodeTAA, func() TypedRecord { return &AirportTAARecord{} }, nil)
+	Register(SectionCodeHeliport, SubsectionCodeCommunication, func() TypedRecord { return &AirportCommunicationContinuationRecord{} }, isCommunicationContinuation)
+	Register(SectionCodeHeliport, SubsectionCodeCommunication, func() TypedRecord { return &AirportCommunicationPrimaryRecord{} }, nil)
+}
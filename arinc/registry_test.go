@@ -0,0 +1,384 @@
+package arinc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	fixedwidth "github.com/ianlopshire/go-fixedwidth"
+)
+
+func TestSectionAndSubsection(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		record         string
+		wantSection    string
+		wantSubsection string
+		wantErr        bool
+	}{
+		{
+			name:           "Airport",
+			record:         "SUSAP KHWDK2CBOGRE K20    W     N37372195W122023769                       E0133     NAR           BOGRE                    107992002",
+			wantSection:    "P",
+			wantSubsection: "C",
+		},
+		{
+			name:           "Enroute",
+			record:         "SUSAEAENRT   SUNOL K20    C  RL N37000000W121000000                       E0132     NAR           SUNOL                    459212002",
+			wantSection:    "E",
+			wantSubsection: "A",
+		},
+		{
+			name:        "BlankSubsection",
+			record:      "SUSAD        PYE   K2011370VDHW N38000000W122000000    N38044712W122520418E0170013402     NARPOINT REYES                   236192002",
+			wantSection: "D",
+		},
+		{
+			name:    "TooShort",
+			record:  "SUSA",
+			wantErr: true,
+		},
+		{
+			name:           "Heliport",
+			record:         "SUSAH CA52K2CHW    K20    W     N37354475W121595747                       E0132     NAR           HW                       108112002",
+			wantSection:    "H",
+			wantSubsection: "C",
+		},
+		{
+			name:    "AirportTooShort",
+			record:  "SUSAP KHWD",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			section, subsection, err := SectionAndSubsection([]byte(tt.record))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("SectionAndSubsection() = <nil> want <non-nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SectionAndSubsection() = %v want <nil>", err)
+			}
+			if section != tt.wantSection || subsection != tt.wantSubsection {
+				t.Errorf("SectionAndSubsection() = %q, %q want %q, %q", section, subsection, tt.wantSection, tt.wantSubsection)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		record   string
+		wantType string
+	}{
+		{
+			name:     "VHFNavaid",
+			record:   "SUSAD        PYE   K2011370VDHW N38000000W122000000    N38044712W122520418E0170013402     NARPOINT REYES                   236192002",
+			wantType: "*arinc.VHFNavaidRecord",
+		},
+		{
+			name:     "NDBNavaid",
+			record:   "SUSADB       OA    K2003620H  W N37450083W122130172                       E0140           NAROAKLAND                       236052002",
+			wantType: "*arinc.NDBNavaidRecord",
+		},
+		{
+			name:     "EnrouteWaypoint",
+			record:   "SUSAEAENRT   SUNOL K20    C  RL N37000000W121000000                       E0132     NAR           SUNOL                    459212002",
+			wantType: "*arinc.WaypointPrimaryRecord",
+		},
+		{
+			name:     "EnrouteAirway",
+			record:   "SUSAER       V25         0100PYE  K2D 0V    OL                        115002081147 04000     17999                         459892002",
+			wantType: "*arinc.EnrouteAirwayRecord",
+		},
+		{
+			name:     "HoldingPattern",
+			record:   "SUSAEPENRTK2                 SUNOLK2EA01150R   100500017000230                                    SUNOL                    459892002",
+			wantType: "*arinc.HoldingPatternRecord",
+		},
+		{
+			name:     "AirportPrimary",
+			record:   "SUSAP KHWDK2AHWD     0     056YHN37393214W122071825E015000052         1800018000C    MNAR    HAYWARD EXECUTIVE             107981608",
+			wantType: "*arinc.AirportPrimaryRecord",
+		},
+		{
+			name:     "TerminalWaypoint",
+			record:   "SUSAP KHWDK2CBOGRE K20    W     N37372195W122023769                       E0133     NAR           BOGRE                    107992002",
+			wantType: "*arinc.WaypointPrimaryRecord",
+		},
+		{
+			name:     "TerminalNDB",
+			record:   "SUSAP KHWDK2NHW    K2000362H  W N37354475W121595747                       E0132           NARFERNE                         108052002",
+			wantType: "*arinc.TerminalNDBRecord",
+		},
+		{
+			name:     "LocalizerMarker",
+			record:   "SUSAP KHWDK2MIHWDOM  000362RW28LN37354475W1215957472880                   H  W      HW    E0132  00010                     108062002",
+			wantType: "*arinc.AirportLocalizerMarkerRecord",
+		},
+		{
+			name:     "ApproachProcedure",
+			record:   "SUSAP KHWDK2FL28L  L      010JIBANK2PC0E  I    IF IHWDK2      10790127        PI  + 03700     18000                 0 DS   108511310",
+			wantType: "*arinc.AirportProcedurePrimaryRecord",
+		},
+		{
+			name:     "ApproachProcedureContinuation",
+			record:   "SUSAP KBURK2FH08-Y H      020WESKIK2PC2W                                                A031A021                      FS   363961310",
+			wantType: "*arinc.AirportEnrouteRecord",
+		},
+		{
+			name:     "Runway",
+			record:   "SUSAP KHWDK2GRW28L   0056942840 N37391866W122065313         -0017200050067635150RIHWD0                                     108881707",
+			wantType: "*arinc.AirportRunwayPrimaryRecord",
+		},
+		{
+			name:     "MSA",
+			record:   "SUSAP KHWDK2SBOGREK2PC                0   0001800402518036006025                                                       M   107992002",
+			wantType: "*arinc.AirportMSARecord",
+		},
+		{
+			name:     "TAA",
+			record:   "SUSAP KHWDK2KR28L  C      JOBUSK2PC   0 195015M00150571530060                                                              108801310",
+			wantType: "*arinc.AirportTAARecord",
+		},
+		{
+			name:     "ControlledAirspace",
+			record:   "SUSAUCK2AKOAK PAC  A00101BC   R N37500000W122120000N37400000W12212000001000000   GND  M04000MOAKLAND CLASS C               123452002",
+			wantType: "*arinc.ControlledAirspaceRecord",
+		},
+		{
+			name:     "RestrictiveAirspace",
+			record:   "SUSAURK2R2531      A00101BC   CE                   N37000000W1210000000050       GND  MFL180MR-2531 TEST                   123452002",
+			wantType: "*arinc.RestrictiveAirspaceRecord",
+		},
+		{
+			name:     "RestrictiveAirspaceContinuation",
+			record:   "SUSAURK2R2531      A00102CCONTROLLED BY OAKLAND CENTER                                                                     123452002",
+			wantType: "*arinc.Record",
+		},
+		{
+			name:     "CommunicationPrimary",
+			record:   "SUSAP KHWDK2VAPP1348500V1   RA3N37393214W122071825E015000052Y000180B0800001500OAK K2D             NORCAL APPROACH          109022002",
+			wantType: "*arinc.AirportCommunicationPrimaryRecord",
+		},
+		{
+			name:     "CommunicationContinuation",
+			record:   "SUSAP KHWDK2VAPP1348500V2NNORTH OF THE AIRPORT BELOW 8000                                                                  109032002",
+			wantType: "*arinc.AirportCommunicationContinuationRecord",
+		},
+		{
+			name:     "LocGSPrimary",
+			record:   "SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212",
+			wantType: "*arinc.AirportLocGSPrimaryRecord",
+		},
+		{
+			name:     "LocGSSimContinuation",
+			record:   "SUSAP KHWDK2IIHWD0   2S                            30294N                                                                  108901212",
+			wantType: "*arinc.AirportLocGSSimContinuationRecord",
+		},
+		{
+			name:     "SID",
+			record:   "SUSAP KBURK2DELMOO91RW08  010         0        CA                     0789        + 01178     18000                        360941905",
+			wantType: "*arinc.AirportProcedurePrimaryRecord",
+		},
+		{
+			name:     "STAR",
+			record:   "SUSAP KHWDK2EPXN6  1AVE   010AVE  K2D 0V       IF                                             18000                        108151909",
+			wantType: "*arinc.AirportProcedurePrimaryRecord",
+		},
+		{
+			name:     "PathPointPrimary",
+			record:   "SUSAP KHWDK2PR28L  RW28L001A0001W28A0N3739450000W12207300000+001180300N3730000000W12207300000106050008000550F400500B0B82C38123452002",
+			wantType: "*arinc.AirportPathPointPrimaryRecord",
+		},
+		{
+			name:     "PathPointContinuation",
+			record:   "SUSAP KHWDK2PR28L  RW28L002E      +00118+00118LPV       21473                                                              123462002",
+			wantType: "*arinc.AirportPathPointContinuationRecord",
+		},
+		{
+			name:     "GLS",
+			record:   "SUSAP KHWDK2TG28L1   021473RW28L                   2950N37393214W122071825G28L   20AB300E0150    00052NARLG                123472002",
+			wantType: "*arinc.AirportGLSRecord",
+		},
+		{
+			name:     "HeliportPrimary",
+			record:   "SUSAH CA52K2A        0          N37393214W122071825E015000052         1800018000C    MNAR    HAYWARD HOSPITAL              107981608",
+			wantType: "*arinc.HeliportPrimaryRecord",
+		},
+		{
+			name:     "HeliportWaypoint",
+			record:   "SUSAH CA52K2CHW    K20    W     N37354475W121595747                       E0132     NAR           HW                       108112002",
+			wantType: "*arinc.WaypointPrimaryRecord",
+		},
+		{
+			name:     "HeliportApproach",
+			record:   "SUSAH CA52K2FL28L  L      020HW   K2HC0E  F    CF IHWDK2      1079007428800053PI  + 02500                 OAK   K2D 0 DS   108521310",
+			wantType: "*arinc.AirportProcedurePrimaryRecord",
+		},
+		{
+			name:     "HeliportPathPoint",
+			record:   "SUSAH CA52K2PH28L  H1   001A0001W28H0N3738000000W12203000000+001180300N3730000000W12203000000106050008000550F400500        123452002",
+			wantType: "*arinc.AirportPathPointPrimaryRecord",
+		},
+		{
+			name:     "Helipad",
+			record:   "SUSAH CA52K2HH1      0          N37380000W122030000                                                                        123452002",
+			wantType: "*arinc.HelipadRecord",
+		},
+		{
+			name:     "UnknownHeliport",
+			record:   "SUSAH CA52K2XPXN6  1AVE   010AVE  K2D 0V       IF                                             18000                        108151909",
+			wantType: "*arinc.AirportEnrouteRecord",
+		},
+		{
+			name:     "UnknownAirport",
+			record:   "SUSAP KHWDK2XPXN6  1AVE   010AVE  K2D 0V       IF                                             18000                        108151909",
+			wantType: "*arinc.AirportEnrouteRecord",
+		},
+		{
+			name:     "Unknown",
+			record:   "HDR01FAACIFP18      001P013203974306  01-OCT-2020  19:43:35  U.S.A. DOT FAA AIS                            ",
+			wantType: "*arinc.Record",
+		},
+		{
+			name:     "TooShort",
+			record:   "SUSA",
+			wantType: "*arinc.Record",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.record))
+			if err != nil {
+				t.Fatalf("Parse() = %v want <nil>", err)
+			}
+			if gotType := fmt.Sprintf("%T", got); gotType != tt.wantType {
+				t.Errorf("Parse() type = %s want %s", gotType, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	got, err := Parse([]byte("SUSAP KHWDK2IIHWD0   2S                            30294N                                                                  108901212"))
+	if err != nil {
+		t.Fatalf("Parse() = %v want <nil>", err)
+	}
+	rec, ok := got.(AirportRecord)
+	if !ok {
+		t.Fatalf("Parse() = %T want an AirportRecord", got)
+	}
+	want := &AirportEnrouteRecord{
+		Record: Record{
+			RecordType:       "S",
+			CustomerAreaCode: "USA",
+			SectionCode:      "P",
+		},
+		AirportID:      "KHWD",
+		ICAOCode:       "K2",
+		SubsectionCode: "I",
+	}
+	if diff := cmp.Diff(want, rec.Airport()); diff != "" {
+		t.Errorf("Parse() airport fields had diffs (-want +got): %s", diff)
+	}
+	if diff := cmp.Diff(&want.Record, rec.Header()); diff != "" {
+		t.Errorf("Parse() header fields had diffs (-want +got): %s", diff)
+	}
+}
+
+func TestParseFieldErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		record    string
+		wantField string
+	}{
+		{
+			name:      "Coordinate",
+			record:    "SUSAP KHWDK2CBOGRE K20    W     N37372195W12202376X                       E0133     NAR           BOGRE                    107992002",
+			wantField: "Waypoint",
+		},
+		{
+			name:      "NonCanonicalCoordinate",
+			record:    "SUSAP KHWDK2CBOGRE K20    W     N37376095W122023769                       E0133     NAR           BOGRE                    107992002",
+			wantField: "Waypoint",
+		},
+		{
+			name:      "BlankCoordinate",
+			record:    "SUSAP KHWDK2TG28L1   021473RW28L                   2950                   G28L   20AB300E0150    00052NARLG                123472002",
+			wantField: "Station",
+		},
+		{
+			name:      "Bearing",
+			record:    "SUSAP KHWDK2TG28L1   021473RW28L                   295 N37393214W122071825G28L   20AB300E0150    00052NARLG                123472002",
+			wantField: "GLSApproachBearing",
+		},
+		{
+			name:      "MagVar",
+			record:    "SUSAP KHWDK2TG28L1   021473RW28L                   2950N37393214W122071825G28L   20AB300X0150    00052NARLG                123472002",
+			wantField: "MagneticVar",
+		},
+		{
+			name:      "Altitude",
+			record:    "SUSAP KHWDK2FL28L  L      010JIBANK2PC0E  I    IF IHWDK2      10790127        PI  + +3700     18000                 0 DS   108511310",
+			wantField: "Altitude1",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.record))
+			var te *fixedwidth.UnmarshalTypeError
+			if !errors.As(err, &te) {
+				t.Fatalf("Parse() = %v want *fixedwidth.UnmarshalTypeError", err)
+			}
+			if te.Field != tt.wantField {
+				t.Errorf("Parse() field = %q want %q", te.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+type testRecord struct {
+	Record `fixed:"1,6,left"`
+	Ident  string `fixed:"7,10,left"`
+}
+
+type testContinuationRecord struct {
+	Record `fixed:"1,6,left"`
+	Ident  string `fixed:"7,10,left"`
+}
+
+func TestRegister(t *testing.T) {
+	Register("Z", "Z", func() TypedRecord { return &testContinuationRecord{} }, func(line []byte) bool {
+		return len(line) > 10 && line[10] == '2'
+	})
+	Register("Z", "Z", func() TypedRecord { return &testRecord{} }, nil)
+	for _, tt := range []struct {
+		name   string
+		record string
+		want   TypedRecord
+	}{
+		{
+			name:   "Primary",
+			record: "SUSAZZTEST1",
+			want:   &testRecord{Record: Record{RecordType: "S", CustomerAreaCode: "USA", SectionCode: "Z", SubsectionCode: "Z"}, Ident: "TEST"},
+		},
+		{
+			name:   "Continuation",
+			record: "SUSAZZTEST2",
+			want:   &testContinuationRecord{Record: Record{RecordType: "S", CustomerAreaCode: "USA", SectionCode: "Z", SubsectionCode: "Z"}, Ident: "TEST"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.record))
+			if err != nil {
+				t.Fatalf("Parse() = %v want <nil>", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Parse() had diffs (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package arinc
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// TAABand is the part of a TAA sector between two arcs around the IAF. An
+// aircraft is in the band if its distance to the IAF is at least InnerRadius
+// and at most OuterRadius.
+type TAABand struct {
+	// InnerRadius and OuterRadius are in nautical miles.
+	InnerRadius int
+	OuterRadius int
+	Altitude    Altitude
+}
+
+// TAA is one decoded sector of a terminal arrival area. The sector extends
+// clockwise from FromBearing to ToBearing. Like MSA sectors, the bearings are
+// those of courses to the IAF.
+type TAA struct {
+	AirportID  string
+	ApproachID string
+	// SectorID identifies the sector within the TAA of the approach, such as
+	// the straight-in area or a base area.
+	SectorID string
+	IAFID    string
+	// BearingsAreTrue is true if the sector bearings are referenced to true
+	// north instead of magnetic north.
+	BearingsAreTrue bool
+	FromBearing     float64
+	ToBearing       float64
+	// Bands are in the order of the record, which is from the IAF outwards.
+	Bands []*TAABand
+}
+
+// Contains returns true if the bearing to the IAF is in the sector.
+func (t *TAA) Contains(bearing float64) bool {
+	return betweenBearings(t.FromBearing, t.ToBearing, bearing)
+}
+
+// Band returns the band of the sector that contains the point with the given
+// bearing and distance in nautical miles to the IAF. If the sector does not
+// contain it, nil is returned. A distance on the arc between two bands is in
+// the inner one.
+func (t *TAA) Band(bearing, distance float64) *TAABand {
+	if !t.Contains(bearing) {
+		return nil
+	}
+	for _, b := range t.Bands {
+		if distance >= float64(b.InnerRadius) && distance <= float64(b.OuterRadius) {
+			return b
+		}
+	}
+	return nil
+}
+
+// TAA decodes the fields of the TAA record. Bands with blank fields are
+// skipped. If any field is malformed, an error naming the field is returned.
+func (r *AirportTAARecord) TAA() (*TAA, error) {
+	t := &TAA{
+		AirportID:  r.AirportID,
+		ApproachID: r.ApproachID,
+		SectorID:   r.SectorID,
+		IAFID:      r.IAFWaypoint,
+	}
+	switch r.MagneticTrueIndicator {
+	case "M", "":
+	case "T":
+		t.BearingsAreTrue = true
+	default:
+		return nil, fmt.Errorf("MagneticTrueIndicator: invalid indicator %q", r.MagneticTrueIndicator)
+	}
+	var err error
+	if t.FromBearing, t.ToBearing, err = parseSectorBearings(r.SectorBearing); err != nil {
+		return nil, fmt.Errorf("SectorBearing: %v", err)
+	}
+	for i, f := range [][2]string{
+		{r.SectorRadius1, r.SectorAltitude1},
+		{r.SectorRadius2, r.SectorAltitude2},
+		{r.SectorRadius3, r.SectorAltitude3},
+		{r.SectorRadius4, r.SectorAltitude4},
+		{r.SectorRadius5, r.SectorAltitude5},
+		{r.SectorRadius6, r.SectorAltitude6},
+		{r.SectorRadius7, r.SectorAltitude7},
+	} {
+		if f[0] == "" && f[1] == "" {
+			continue
+		}
+		b, err := parseTAABand(f[0], f[1])
+		if err != nil {
+			return nil, fmt.Errorf("Band%d: %v", i+1, err)
+		}
+		t.Bands = append(t.Bands, b)
+	}
+	if len(t.Bands) == 0 {
+		return nil, fmt.Errorf("TAA sector %q of %q has no bands", r.SectorID, r.ApproachID)
+	}
+	return t, nil
+}
+
+// parseTAABand parses a four character pair of inner and outer radii in
+// nautical miles, and a three character altitude in hundreds of feet.
+func parseTAABand(radii, altitude string) (*TAABand, error) {
+	if len(radii) != 4 {
+		return nil, fmt.Errorf("invalid sector radii %q, want 4 characters", radii)
+	}
+	inner, err1 := strconv.Atoi(radii[:2])
+	outer, err2 := strconv.Atoi(radii[2:])
+	if err1 != nil || err2 != nil || inner < 0 || outer <= inner {
+		return nil, fmt.Errorf("invalid sector radii %q", radii)
+	}
+	alt, err := parseSectorAltitude(altitude)
+	if err != nil {
+		return nil, err
+	}
+	return &TAABand{InnerRadius: inner, OuterRadius: outer, Altitude: alt}, nil
+}
@@ -0,0 +1,120 @@
+package arinc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTAA(t *testing.T) {
+	rec, err := Parse([]byte("SUSAP KHWDK2KR28L  C      JOBUSK2PC   0 195015M00150571530060                                                              108801310"))
+	if err != nil {
+		t.Fatalf("Parse() = %v want <nil>", err)
+	}
+	got, err := rec.(*AirportTAARecord).TAA()
+	if err != nil {
+		t.Fatalf("TAA() = _, %v want _, <nil>", err)
+	}
+	want := &TAA{
+		AirportID:   "KHWD",
+		ApproachID:  "R28L",
+		SectorID:    "C",
+		IAFID:       "JOBUS",
+		FromBearing: 195,
+		ToBearing:   15,
+		Bands: []*TAABand{
+			{InnerRadius: 0, OuterRadius: 15, Altitude: Altitude{Feet: 5700}},
+			{InnerRadius: 15, OuterRadius: 30, Altitude: Altitude{Feet: 6000}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("TAA() had diffs (-want +got): %s", diff)
+	}
+}
+
+func TestTAAErrors(t *testing.T) {
+	good := func() *AirportTAARecord {
+		return &AirportTAARecord{
+			ApproachID:            "R28L",
+			SectorID:              "L",
+			SectorBearing:         "015105",
+			MagneticTrueIndicator: "M",
+			SectorRadius1:         "0030",
+			SectorAltitude1:       "050",
+		}
+	}
+	for _, tt := range []struct {
+		name   string
+		modify func(r *AirportTAARecord)
+	}{
+		{
+			name:   "MagneticTrueIndicator",
+			modify: func(r *AirportTAARecord) { r.MagneticTrueIndicator = "X" },
+		},
+		{
+			name:   "SectorBearing",
+			modify: func(r *AirportTAARecord) { r.SectorBearing = "01510" },
+		},
+		{
+			name:   "SectorRadius",
+			modify: func(r *AirportTAARecord) { r.SectorRadius1 = "3000" },
+		},
+		{
+			name:   "SectorRadiusLength",
+			modify: func(r *AirportTAARecord) { r.SectorRadius1 = "030" },
+		},
+		{
+			name:   "SectorAltitude",
+			modify: func(r *AirportTAARecord) { r.SectorAltitude1 = "05" },
+		},
+		{
+			name:   "PartialBand",
+			modify: func(r *AirportTAARecord) { r.SectorRadius2 = "3040" },
+		},
+		{
+			name:   "NoBands",
+			modify: func(r *AirportTAARecord) { r.SectorRadius1, r.SectorAltitude1 = "", "" },
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := good()
+			if _, err := r.TAA(); err != nil {
+				t.Fatalf("TAA() before modification = _, %v want _, <nil>", err)
+			}
+			tt.modify(r)
+			if _, err := r.TAA(); err == nil {
+				t.Errorf("TAA() = _, <nil> want _, <non-nil>")
+			}
+		})
+	}
+}
+
+func TestTAABand(t *testing.T) {
+	taa := &TAA{
+		FromBearing: 195,
+		ToBearing:   15,
+		Bands: []*TAABand{
+			{InnerRadius: 0, OuterRadius: 15, Altitude: Altitude{Feet: 5700}},
+			{InnerRadius: 15, OuterRadius: 30, Altitude: Altitude{Feet: 6000}},
+		},
+	}
+	for _, tt := range []struct {
+		name     string
+		bearing  float64
+		distance float64
+		want     *TAABand
+	}{
+		{"InnerBand", 285, 10, taa.Bands[0]},
+		{"OuterBand", 300, 20, taa.Bands[1]},
+		{"OnArc", 285, 15, taa.Bands[0]},
+		{"AcrossNorth", 5, 25, taa.Bands[1]},
+		{"OutsideBearings", 100, 10, nil},
+		{"BeyondOuterArc", 285, 31, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := taa.Band(tt.bearing, tt.distance); got != tt.want {
+				t.Errorf("Band(%f, %f) = %v want %v", tt.bearing, tt.distance, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,603 @@
+package arinc
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The value types in this file decode and encode the coordinate, bearing,
+// magnetic variation, and altitude fields of ARINC 424 records. Each type
+// implements encoding.TextMarshaler and encoding.TextUnmarshaler with the
+// encoding of its field, so that the record structs use them directly.
+// UnmarshalText only accepts the encoding that MarshalText returns, so that
+// encoding a decoded value returns the original field. Blank fields are not
+// valid values, so fields that may be blank use the Null types, whose Valid
+// field is false if the field is blank.
+
+// Reference is the north reference of a bearing or a magnetic variation.
+type Reference int
+
+const (
+	// MagneticNorth means that bearings are referenced to magnetic north.
+	MagneticNorth Reference = iota
+	// TrueNorth means that bearings are referenced to true north.
+	TrueNorth
+)
+
+// String returns the name of the reference.
+func (r Reference) String() string {
+	switch r {
+	case MagneticNorth:
+		return "magnetic"
+	case TrueNorth:
+		return "true"
+	}
+	return fmt.Sprintf("Reference(%d)", r)
+}
+
+// Coordinate is a position in decimal degrees, with positive latitudes north
+// of the equator and positive longitudes east of the prime meridian.
+type Coordinate struct {
+	Lat, Lon float64
+}
+
+// ParseCoordinate returns the coordinate of the provided nine character
+// latitude (e.g. "N37394620") and ten character longitude (e.g.
+// "W122074675"). If the data is invalid, an error is returned.
+// See 5.36 Latitude, 5.37 Longitude
+func ParseCoordinate(latitude, longitude string) (Coordinate, error) {
+	lat, err := ParseLatitude(latitude)
+	if err != nil {
+		return Coordinate{}, err
+	}
+	lon, err := ParseLongitude(longitude)
+	if err != nil {
+		return Coordinate{}, err
+	}
+	return Coordinate{Lat: lat, Lon: lon}, nil
+}
+
+// Latitude returns the nine character encoding of the latitude, rounded to a
+// hundredth of an arc second.
+// Example: Coordinate{Lat: 37.66128}.Latitude() = "N37394061"
+func (c Coordinate) Latitude() string {
+	return encodePoint(c.Lat, "N", "S", 2)
+}
+
+// Longitude returns the ten character encoding of the longitude, rounded to a
+// hundredth of an arc second.
+// Example: Coordinate{Lon: -122.12187}.Longitude() = "W122071873"
+func (c Coordinate) Longitude() string {
+	return encodePoint(c.Lon, "E", "W", 3)
+}
+
+// encodePoint encodes the latitude or longitude in degrees, minutes, and
+// hundredths of arc seconds, with the given number of degree digits.
+func encodePoint(deg float64, positive, negative string, degreeDigits int) string {
+	dir := positive
+	if deg < 0 {
+		dir = negative
+	}
+	hundredths := round(math.Abs(deg) * 360000)
+	return fmt.Sprintf("%s%0*d%02d%04d", dir, degreeDigits, hundredths/360000, hundredths/6000%60, hundredths%6000)
+}
+
+// String returns the coordinate in decimal degrees.
+func (c Coordinate) String() string {
+	return fmt.Sprintf("%.6f,%.6f", c.Lat, c.Lon)
+}
+
+// MarshalText implements encoding.TextMarshaler. The coordinate is encoded as
+// the latitude followed by the longitude, which are adjacent in every record
+// that has both. If the coordinate is out of range, an error is returned.
+func (c Coordinate) MarshalText() ([]byte, error) {
+	if !(math.Abs(c.Lat) <= 90) || !(math.Abs(c.Lon) <= 180) {
+		return nil, fmt.Errorf("coordinate %v is out of range", c)
+	}
+	return []byte(c.Latitude() + c.Longitude()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for the nineteen
+// character latitude and longitude written by MarshalText.
+func (c *Coordinate) UnmarshalText(text []byte) error {
+	if len(text) != 19 {
+		return fmt.Errorf("invalid coordinate %q, want 19 characters", text)
+	}
+	v, err := ParseCoordinate(string(text[:9]), string(text[9:]))
+	if err != nil {
+		return err
+	}
+	if err := checkEncoding(v, text, "coordinate"); err != nil {
+		return err
+	}
+	*c = v
+	return nil
+}
+
+// Bearing is a bearing or course in degrees.
+type Bearing struct {
+	Value     float64
+	Reference Reference
+}
+
+// ParseBearingValue returns the bearing of the provided four character
+// string. Magnetic bearings are in tenths of a degree (e.g. "2570"), and true
+// bearings are in whole degrees followed by a "T" (e.g. "347T"). If any error
+// occurs, an error is returned.
+// See 5.26 Inbound Holding Course, 5.58 Runway Magnetic Bearing
+func ParseBearingValue(bearing string) (Bearing, error) {
+	if len(bearing) != 4 {
+		return Bearing{}, fmt.Errorf("Could not parse bearing, invalid length %d want 4.", len(bearing))
+	}
+	if bearing[3] == 'T' {
+		num, err := strconv.ParseFloat(bearing[0:3], 64)
+		if err != nil {
+			return Bearing{}, fmt.Errorf("Could not parse bearing: %v", err)
+		}
+		return Bearing{Value: num, Reference: TrueNorth}, nil
+	}
+
+	num, err := strconv.ParseFloat(bearing[0:4], 64)
+	if err != nil {
+		return Bearing{}, fmt.Errorf("Could not parse bearing: %v", err)
+	}
+	return Bearing{Value: num / 10}, nil
+}
+
+// ParseTrueBearing returns the true bearing of the provided five character
+// string, where the decimal point is implied after the third character.
+// See 5.94 True Bearing
+// Example: ParseTrueBearing("19012") = Bearing{Value: 190.12, Reference: TrueNorth}
+func ParseTrueBearing(bearing string) (Bearing, error) {
+	if len(bearing) != 5 {
+		return Bearing{}, fmt.Errorf("invalid true bearing %q, want 5 characters", bearing)
+	}
+	hundredths, err := parseDigits(bearing, 5)
+	if err != nil || hundredths >= 36000 {
+		return Bearing{}, fmt.Errorf("invalid true bearing %q", bearing)
+	}
+	return Bearing{Value: float64(hundredths) / 100, Reference: TrueNorth}, nil
+}
+
+// EncodeTrueBearing encodes the true bearing into the five character string
+// that ParseTrueBearing accepts, rounded to a hundredth of a degree. If the
+// bearing is magnetic or out of range, an error is returned.
+// Example: Bearing{Value: 190.123, Reference: TrueNorth}.EncodeTrueBearing() = "19012"
+func (b Bearing) EncodeTrueBearing() (string, error) {
+	if b.Reference != TrueNorth {
+		return "", fmt.Errorf("bearing %v is not a true bearing", b)
+	}
+	hundredths, err := b.units(100)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%05d", hundredths), nil
+}
+
+// units returns the bearing rounded to the given fraction of a degree, with
+// 360 degrees wrapped to zero. If the bearing is out of range, an error is
+// returned.
+func (b Bearing) units(perDegree float64) (int64, error) {
+	if !(b.Value >= 0 && b.Value < 360) {
+		return 0, fmt.Errorf("bearing %v is out of range", b)
+	}
+	return round(b.Value*perDegree) % round(360*perDegree), nil
+}
+
+// String returns the bearing in degrees and its reference, such as "284.0
+// magnetic".
+func (b Bearing) String() string {
+	return fmt.Sprintf("%.1f %v", b.Value, b.Reference)
+}
+
+// MarshalText implements encoding.TextMarshaler with the four character
+// encoding that ParseBearingValue accepts. Magnetic bearings are rounded to a
+// tenth of a degree, and true bearings to a whole degree. A bearing of
+// exactly 360 degrees is encoded as such, since some records give north that
+// way. If the bearing is out of range, an error is returned.
+func (b Bearing) MarshalText() ([]byte, error) {
+	if b.Value == 360 {
+		if b.Reference == TrueNorth {
+			return []byte("360T"), nil
+		}
+		return []byte("3600"), nil
+	}
+	if b.Reference == TrueNorth {
+		deg, err := b.units(1)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(fmt.Sprintf("%03dT", deg)), nil
+	}
+	tenths, err := b.units(10)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("%04d", tenths)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler with ParseBearingValue.
+func (b *Bearing) UnmarshalText(text []byte) error {
+	v, err := ParseBearingValue(string(text))
+	if err != nil {
+		return err
+	}
+	if err := checkEncoding(v, text, "bearing"); err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+// TrueBearing is a true bearing with the five character encoding that
+// ParseTrueBearing accepts, which is more precise than that of Bearing.
+// See 5.94 True Bearing
+type TrueBearing struct {
+	Bearing
+}
+
+// MarshalText implements encoding.TextMarshaler with EncodeTrueBearing.
+func (b TrueBearing) MarshalText() ([]byte, error) {
+	s, err := b.EncodeTrueBearing()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler with ParseTrueBearing.
+func (b *TrueBearing) UnmarshalText(text []byte) error {
+	v, err := ParseTrueBearing(string(text))
+	if err != nil {
+		return err
+	}
+	if err := checkEncoding(TrueBearing{v}, text, "true bearing"); err != nil {
+		return err
+	}
+	b.Bearing = v
+	return nil
+}
+
+// True returns the bearing referenced to true north, converting a magnetic
+// bearing with the magnetic variation. If the bearing is magnetic and the
+// variation is referenced to true north, the bearing cannot be converted and
+// an error is returned.
+func (b Bearing) True(v MagVar) (Bearing, error) {
+	if b.Reference == TrueNorth {
+		return b, nil
+	}
+	if v.Reference == TrueNorth {
+		return Bearing{}, fmt.Errorf("magnetic bearing %v cannot be converted where bearings are true", b)
+	}
+	return Bearing{Value: normalizeBearing(b.Value - v.Value), Reference: TrueNorth}, nil
+}
+
+// Magnetic returns the bearing referenced to magnetic north, converting a true
+// bearing with the magnetic variation. If the bearing is true and the
+// variation is referenced to true north, the bearing cannot be converted and
+// an error is returned.
+func (b Bearing) Magnetic(v MagVar) (Bearing, error) {
+	if b.Reference == MagneticNorth {
+		return b, nil
+	}
+	if v.Reference == TrueNorth {
+		return Bearing{}, fmt.Errorf("true bearing %v cannot be converted where bearings are true", b)
+	}
+	return Bearing{Value: normalizeBearing(b.Value + v.Value)}, nil
+}
+
+// normalizeBearing returns the bearing in degrees in the range [0, 360).
+func normalizeBearing(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
+
+// MagVar is a magnetic variation or station declination in degrees, positive
+// for west variation and negative for east variation. Where bearings are
+// referenced to true north, Reference is TrueNorth and Value is zero.
+type MagVar struct {
+	Value     float64
+	Reference Reference
+}
+
+// ParseMagVar returns the magnetic variation of the provided five character
+// string, such as "E0150" or "W0140". A variation of "T0000" indicates that
+// bearings are referenced to true north. If any error occurs, an error is
+// returned.
+// See 5.39 Magnetic Variation, 5.66 Station Declination
+func ParseMagVar(magVar string) (MagVar, error) {
+	if len(magVar) != 5 {
+		return MagVar{}, fmt.Errorf("Could not parse magnetic variation, invalid length %d want 5.", len(magVar))
+	}
+	var eastWestFactor float64
+	switch magVar[0] {
+	case 'E':
+		eastWestFactor = -1.0
+	case 'W':
+		eastWestFactor = 1.0
+	case 'T':
+		return MagVar{Reference: TrueNorth}, nil
+	default:
+		return MagVar{}, fmt.Errorf("Could not parse magnetic variation, invalid direction indicator %q", magVar[0])
+	}
+	num, err := strconv.ParseFloat(magVar[1:5], 64)
+	if err != nil {
+		return MagVar{}, fmt.Errorf("Could not parse numerical portion of magnetic variation: %v", err)
+	}
+	if num == 0 {
+		// A variation of zero is positive zero if it is east and negative
+		// zero if it is west, so that MarshalText can tell them apart.
+		return MagVar{Value: math.Copysign(0, -eastWestFactor)}, nil
+	}
+	return MagVar{Value: num * eastWestFactor / 10}, nil
+}
+
+// String returns the magnetic variation as it is written on charts, such as
+// "15.0E", or "T" if bearings are referenced to true north.
+func (v MagVar) String() string {
+	switch {
+	case v.Reference == TrueNorth:
+		return "T"
+	case v.Value > 0:
+		return fmt.Sprintf("%.1fW", v.Value)
+	}
+	return fmt.Sprintf("%.1fE", -v.Value)
+}
+
+// MarshalText implements encoding.TextMarshaler with the five character
+// encoding that ParseMagVar accepts, rounded to a tenth of a degree. A
+// variation of zero is encoded as east, unless it is the negative zero that
+// ParseMagVar returns for "W0000". If the variation is out of range, an error
+// is returned.
+func (v MagVar) MarshalText() ([]byte, error) {
+	if v.Reference == TrueNorth {
+		return []byte("T0000"), nil
+	}
+	if !(math.Abs(v.Value) <= 180) {
+		return nil, fmt.Errorf("magnetic variation %v is out of range", v.Value)
+	}
+	dir := "E"
+	if v.Value > 0 || math.Signbit(v.Value) && v.Value == 0 {
+		dir = "W"
+	}
+	return []byte(fmt.Sprintf("%s%04d", dir, round(math.Abs(v.Value)*10))), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler with ParseMagVar.
+func (v *MagVar) UnmarshalText(text []byte) error {
+	m, err := ParseMagVar(string(text))
+	if err != nil {
+		return err
+	}
+	if err := checkEncoding(m, text, "magnetic variation"); err != nil {
+		return err
+	}
+	*v = m
+	return nil
+}
+
+// AltitudeReference is the reference of an altitude.
+type AltitudeReference int
+
+const (
+	// MeanSeaLevel means that the altitude is above mean sea level.
+	MeanSeaLevel AltitudeReference = iota
+	// AboveGroundLevel means that the altitude is above ground level.
+	AboveGroundLevel
+	// FlightLevel means that the altitude is a flight level, which is a
+	// pressure altitude referenced to the standard atmosphere.
+	FlightLevel
+)
+
+// String returns the abbreviation of the reference.
+func (r AltitudeReference) String() string {
+	switch r {
+	case MeanSeaLevel:
+		return "MSL"
+	case AboveGroundLevel:
+		return "AGL"
+	case FlightLevel:
+		return "FL"
+	}
+	return fmt.Sprintf("AltitudeReference(%d)", r)
+}
+
+// Altitude is an altitude in feet.
+type Altitude struct {
+	// Feet is the altitude in feet above the reference. For flight levels,
+	// it is the flight level times 100.
+	Feet      int
+	Reference AltitudeReference
+}
+
+// String returns the altitude as it is written on charts, such as "3700",
+// "1500 AGL", or "FL200".
+func (a Altitude) String() string {
+	switch a.Reference {
+	case FlightLevel:
+		return fmt.Sprintf("FL%03d", a.Feet/100)
+	case AboveGroundLevel:
+		return strconv.Itoa(a.Feet) + " AGL"
+	}
+	return strconv.Itoa(a.Feet)
+}
+
+// ParseAltitude returns the altitude of the provided five character string,
+// which is either a number of feet above mean sea level (e.g. "03700") or a
+// flight level (e.g. "FL200"). Records that have altitudes above ground level
+// indicate them in a separate field. If any error occurs, an error is
+// returned.
+// See 5.30 Altitude, 5.121 Lower/Upper Limit
+func ParseAltitude(s string) (Altitude, error) {
+	if len(s) != 5 {
+		return Altitude{}, fmt.Errorf("invalid altitude %q, want 5 characters", s)
+	}
+	if strings.HasPrefix(s, "FL") {
+		fl, err := strconv.Atoi(s[2:])
+		if err != nil || fl < 0 {
+			return Altitude{}, fmt.Errorf("invalid flight level %q", s)
+		}
+		return Altitude{Feet: fl * 100, Reference: FlightLevel}, nil
+	}
+	feet, err := strconv.Atoi(s)
+	if err != nil {
+		return Altitude{}, fmt.Errorf("invalid altitude %q", s)
+	}
+	return Altitude{Feet: feet}, nil
+}
+
+// MarshalText implements encoding.TextMarshaler with the five character
+// encoding that ParseAltitude accepts. Altitudes above ground level are
+// encoded like altitudes above mean sea level, since the reference is in a
+// separate field. If the altitude does not fit in five characters, or a flight
+// level is not a whole number of hundreds of feet, an error is returned.
+func (a Altitude) MarshalText() ([]byte, error) {
+	if a.Reference == FlightLevel {
+		if a.Feet < 0 || a.Feet > 99900 || a.Feet%100 != 0 {
+			return nil, fmt.Errorf("invalid flight level %v", a)
+		}
+		return []byte(a.String()), nil
+	}
+	if a.Feet < -9999 || a.Feet > 99999 {
+		return nil, fmt.Errorf("altitude %v does not fit in 5 characters", a)
+	}
+	return []byte(fmt.Sprintf("%05d", a.Feet)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler with ParseAltitude.
+func (a *Altitude) UnmarshalText(text []byte) error {
+	v, err := ParseAltitude(string(text))
+	if err != nil {
+		return err
+	}
+	if err := checkEncoding(v, text, "altitude"); err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// checkEncoding returns an error if the value decoded from text does not
+// encode to text, such as for a bearing of "+123" or a latitude of 60
+// seconds.
+func checkEncoding(v encoding.TextMarshaler, text []byte, name string) error {
+	if enc, err := v.MarshalText(); err != nil || !bytes.Equal(enc, text) {
+		return fmt.Errorf("invalid %s %q", name, text)
+	}
+	return nil
+}
+
+// NullCoordinate is a coordinate field that may be blank.
+type NullCoordinate struct {
+	Coordinate Coordinate
+	// Valid is false if the field is blank.
+	Valid bool
+}
+
+// MarshalText implements encoding.TextMarshaler. A blank field is encoded as
+// an empty string.
+func (c NullCoordinate) MarshalText() ([]byte, error) {
+	if !c.Valid {
+		return nil, nil
+	}
+	return c.Coordinate.MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text is a blank
+// field.
+func (c *NullCoordinate) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*c = NullCoordinate{}
+		return nil
+	}
+	c.Valid = true
+	return c.Coordinate.UnmarshalText(text)
+}
+
+// NullBearing is a bearing field that may be blank.
+type NullBearing struct {
+	Bearing Bearing
+	// Valid is false if the field is blank.
+	Valid bool
+}
+
+// MarshalText implements encoding.TextMarshaler. A blank field is encoded as
+// an empty string.
+func (b NullBearing) MarshalText() ([]byte, error) {
+	if !b.Valid {
+		return nil, nil
+	}
+	return b.Bearing.MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text is a blank
+// field.
+func (b *NullBearing) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*b = NullBearing{}
+		return nil
+	}
+	b.Valid = true
+	return b.Bearing.UnmarshalText(text)
+}
+
+// NullMagVar is a magnetic variation field that may be blank.
+type NullMagVar struct {
+	MagVar MagVar
+	// Valid is false if the field is blank.
+	Valid bool
+}
+
+// MarshalText implements encoding.TextMarshaler. A blank field is encoded as
+// an empty string.
+func (v NullMagVar) MarshalText() ([]byte, error) {
+	if !v.Valid {
+		return nil, nil
+	}
+	return v.MagVar.MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text is a blank
+// field.
+func (v *NullMagVar) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*v = NullMagVar{}
+		return nil
+	}
+	v.Valid = true
+	return v.MagVar.UnmarshalText(text)
+}
+
+// NullAltitude is an altitude field that may be blank.
+type NullAltitude struct {
+	Altitude Altitude
+	// Valid is false if the field is blank.
+	Valid bool
+}
+
+// MarshalText implements encoding.TextMarshaler. A blank field is encoded as
+// an empty string.
+func (a NullAltitude) MarshalText() ([]byte, error) {
+	if !a.Valid {
+		return nil, nil
+	}
+	return a.Altitude.MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text is a blank
+// field.
+func (a *NullAltitude) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*a = NullAltitude{}
+		return nil
+	}
+	a.Valid = true
+	return a.Altitude.UnmarshalText(text)
+}
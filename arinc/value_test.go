@@ -0,0 +1,765 @@
+package arinc
+
+import (
+	"encoding"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	fixedwidth "github.com/ianlopshire/go-fixedwidth"
+)
+
+func TestParseCoordinate(t *testing.T) {
+	const tolerance = 0.0001
+	for _, tt := range []struct {
+		name    string
+		latStr  string
+		lonStr  string
+		wantLat float64
+		wantLon float64
+		wantErr bool
+	}{
+		{
+			name:    "GoodNE",
+			latStr:  "N39513881",
+			lonStr:  "E104450794",
+			wantLat: 39.860781,
+			wantLon: 104.752206,
+		},
+		{
+			name:    "GoodNW",
+			latStr:  "N39513881",
+			lonStr:  "W104450794",
+			wantLat: 39.860781,
+			wantLon: -104.752206,
+		},
+		{
+			name:    "GoodSE",
+			latStr:  "S39513881",
+			lonStr:  "E104450794",
+			wantLat: -39.860781,
+			wantLon: 104.752206,
+		},
+		{
+			name:    "GoodSW",
+			latStr:  "S39513881",
+			lonStr:  "W104450794",
+			wantLat: -39.860781,
+			wantLon: -104.752206,
+		},
+		{
+			name:    "InvalidLatLen",
+			latStr:  "881",
+			lonStr:  "W104450794",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidLatDeg",
+			latStr:  "N3F513881",
+			lonStr:  "W104450794",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidLatMin",
+			latStr:  "N39F13881",
+			lonStr:  "W104450794",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidLatSec",
+			latStr:  "N395138F1",
+			lonStr:  "W104450794",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidLonLen",
+			latStr:  "S39513881",
+			lonStr:  "W1044507945",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidLonDeg",
+			latStr:  "S39513881",
+			lonStr:  "W10F450794",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidLonLen",
+			latStr:  "S39513881",
+			lonStr:  "W104F50794",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidLonSec",
+			latStr:  "S39513881",
+			lonStr:  "W104450F945",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCoordinate(tt.latStr, tt.lonStr)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseCoordinate(%q, %q) = _, <nil> want _, <non-nil>", tt.latStr, tt.lonStr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCoordinate(%q, %q) = _, %v want _, <nil>", tt.latStr, tt.lonStr, err)
+			}
+			if diff := math.Abs(got.Lat - tt.wantLat); diff > tolerance {
+				t.Errorf("latitude = %f want %f", got.Lat, tt.wantLat)
+			}
+			if diff := math.Abs(got.Lon - tt.wantLon); diff > tolerance {
+				t.Errorf("longitude = %f want %f", got.Lon, tt.wantLon)
+			}
+			if lat, lon := got.Latitude(), got.Longitude(); lat != tt.latStr || lon != tt.lonStr {
+				t.Errorf("Latitude(), Longitude() = %q, %q want %q, %q", lat, lon, tt.latStr, tt.lonStr)
+			}
+		})
+	}
+}
+
+func TestEncodeTrueBearing(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		bearing float64
+		want    string
+	}{
+		{
+			name:    "Simple",
+			bearing: 123.45,
+			want:    "12345",
+		},
+		{
+			name:    "LongDecimal",
+			bearing: 123.45678,
+			want:    "12346",
+		},
+		{
+			name:    "LessThan100Degrees",
+			bearing: 23.45678,
+			want:    "02346",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Bearing{Value: tt.bearing, Reference: TrueNorth}
+			got, err := b.EncodeTrueBearing()
+			if err != nil || got != tt.want {
+				t.Fatalf("EncodeTrueBearing(%v) = %q, %v want %q, <nil>", b, got, err, tt.want)
+			}
+			if parsed, err := ParseTrueBearing(got); err != nil || math.Abs(parsed.Value-tt.bearing) > 0.005 || parsed.Reference != TrueNorth {
+				t.Errorf("ParseTrueBearing(%q) = %v, %v want %v, <nil>", got, parsed, err, b)
+			}
+		})
+	}
+}
+
+func TestParseMagVar(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		magVar   string
+		want     float64
+		wantTrue bool
+		wantErr  bool
+	}{
+		{
+			name:   "West",
+			magVar: "W0140",
+			want:   14.0,
+		},
+		{
+			name:   "East",
+			magVar: "E0135",
+			want:   -13.5,
+		},
+		{
+			name:     "True",
+			magVar:   "T0000",
+			want:     0,
+			wantTrue: true,
+		},
+		{
+			name:    "InvalidDirection",
+			magVar:  "Y0140",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidLength",
+			magVar:  "Y",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMagVar(tt.magVar)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseMagVar(%q) = _, <nil> want _, <non-nil>", tt.magVar)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMagVar(%q) = _, %v want _, <nil>", tt.magVar, err)
+			}
+			if isTrue := got.Reference == TrueNorth; isTrue != tt.wantTrue {
+				t.Errorf("ParseMagVar(%q) = %v want true north %t", tt.magVar, got, tt.wantTrue)
+			}
+			if got.Value != tt.want {
+				t.Errorf("ParseMagVar(%q) = %f want %f", tt.magVar, got.Value, tt.want)
+			}
+			if text, err := got.MarshalText(); err != nil || string(text) != tt.magVar {
+				t.Errorf("MarshalText() = %q, %v want %q, <nil>", text, err, tt.magVar)
+			}
+		})
+	}
+}
+
+func TestParseBearingValue(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		bearing  string
+		want     float64
+		wantTrue bool
+		wantErr  bool
+	}{
+		{
+			name:    "Simple",
+			bearing: "2570",
+			want:    257.0,
+		},
+		{
+			name:    "Decimal",
+			bearing: "0147",
+			want:    14.7,
+		},
+		{
+			name:     "True",
+			bearing:  "347T",
+			want:     347.0,
+			wantTrue: true,
+		},
+		{
+			name:    "InvalidData",
+			bearing: "ABCD",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidLength",
+			bearing: "347",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBearingValue(tt.bearing)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseBearingValue(%q) = _, <nil> want _, <non-nil>", tt.bearing)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBearingValue(%q) = _, %v want _, <nil>", tt.bearing, err)
+			}
+			if isTrue := got.Reference == TrueNorth; isTrue != tt.wantTrue {
+				t.Errorf("ParseBearingValue(%q) = %v want true north %t", tt.bearing, got, tt.wantTrue)
+			}
+			if got.Value != tt.want {
+				t.Errorf("ParseBearingValue(%q) = %f want %f", tt.bearing, got.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestBearingMarshalText(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		bearing float64
+		isTrue  bool
+		want    string
+	}{
+		{
+			name:    "Magnetic",
+			bearing: 284.0,
+			want:    "2840",
+		},
+		{
+			name:    "LessThan100Degrees",
+			bearing: 14.7,
+			want:    "0147",
+		},
+		{
+			name:    "True",
+			bearing: 347,
+			isTrue:  true,
+			want:    "347T",
+		},
+		{
+			name:    "North",
+			bearing: 360,
+			want:    "3600",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Bearing{Value: tt.bearing}
+			if tt.isTrue {
+				b.Reference = TrueNorth
+			}
+			got, err := b.MarshalText()
+			if err != nil || string(got) != tt.want {
+				t.Fatalf("MarshalText(%v) = %q, %v want %q, <nil>", b, got, err, tt.want)
+			}
+			var parsed Bearing
+			if err := parsed.UnmarshalText(got); err != nil {
+				t.Fatalf("UnmarshalText(%q) = %v want <nil>", got, err)
+			}
+			if parsed != b {
+				t.Errorf("UnmarshalText(%q) = %v want %v", got, parsed, b)
+			}
+		})
+	}
+}
+
+func TestBearingMarshalTextErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		bearing Bearing
+	}{
+		{
+			name:    "Negative",
+			bearing: Bearing{Value: -1},
+		},
+		{
+			name:    "TooLarge",
+			bearing: Bearing{Value: 360.5, Reference: TrueNorth},
+		},
+		{
+			name:    "NaN",
+			bearing: Bearing{Value: math.NaN()},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := tt.bearing.MarshalText(); err == nil {
+				t.Errorf("MarshalText(%v) = %q, <nil> want _, <non-nil>", tt.bearing, got)
+			}
+		})
+	}
+}
+
+func TestParseTrueBearingErrors(t *testing.T) {
+	for _, bearing := range []string{"1901", "190123", "1901T", "36000", "-1000"} {
+		if got, err := ParseTrueBearing(bearing); err == nil {
+			t.Errorf("ParseTrueBearing(%q) = %v, <nil> want _, <non-nil>", bearing, got)
+		}
+	}
+	// Only true bearings can be encoded as true bearings.
+	if got, err := (Bearing{Value: 190}).EncodeTrueBearing(); err == nil {
+		t.Errorf("EncodeTrueBearing() of magnetic bearing = %q, <nil> want _, <non-nil>", got)
+	}
+}
+
+func TestBearingConversion(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		bearing      Bearing
+		magVar       MagVar
+		wantTrue     Bearing
+		wantMagnetic Bearing
+		wantErr      bool
+	}{
+		{
+			name:         "MagneticEast",
+			bearing:      Bearing{Value: 284},
+			magVar:       MagVar{Value: -15},
+			wantTrue:     Bearing{Value: 299, Reference: TrueNorth},
+			wantMagnetic: Bearing{Value: 284},
+		},
+		{
+			name:         "MagneticWestWraps",
+			bearing:      Bearing{Value: 5},
+			magVar:       MagVar{Value: 15},
+			wantTrue:     Bearing{Value: 350, Reference: TrueNorth},
+			wantMagnetic: Bearing{Value: 5},
+		},
+		{
+			name:         "TrueEastWraps",
+			bearing:      Bearing{Value: 350, Reference: TrueNorth},
+			magVar:       MagVar{Value: -15},
+			wantTrue:     Bearing{Value: 350, Reference: TrueNorth},
+			wantMagnetic: Bearing{Value: 335},
+		},
+		{
+			name:         "TrueWest",
+			bearing:      Bearing{Value: 350, Reference: TrueNorth},
+			magVar:       MagVar{Value: 15},
+			wantTrue:     Bearing{Value: 350, Reference: TrueNorth},
+			wantMagnetic: Bearing{Value: 5},
+		},
+		{
+			name:    "MagneticWhereBearingsAreTrue",
+			bearing: Bearing{Value: 284},
+			magVar:  MagVar{Reference: TrueNorth},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTrue, err := tt.bearing.True(tt.magVar)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("True(%v) = _, <nil> want _, <non-nil>", tt.magVar)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("True(%v) = _, %v want _, <nil>", tt.magVar, err)
+			}
+			if gotTrue != tt.wantTrue {
+				t.Errorf("True(%v) = %v want %v", tt.magVar, gotTrue, tt.wantTrue)
+			}
+			gotMagnetic, err := tt.bearing.Magnetic(tt.magVar)
+			if err != nil {
+				t.Fatalf("Magnetic(%v) = _, %v want _, <nil>", tt.magVar, err)
+			}
+			if gotMagnetic != tt.wantMagnetic {
+				t.Errorf("Magnetic(%v) = %v want %v", tt.magVar, gotMagnetic, tt.wantMagnetic)
+			}
+		})
+	}
+}
+
+func TestMagVarMarshalText(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		magVar  MagVar
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "West",
+			magVar: MagVar{Value: 13.2},
+			want:   "W0132",
+		},
+		{
+			name:   "East",
+			magVar: MagVar{Value: -15},
+			want:   "E0150",
+		},
+		{
+			name:   "Zero",
+			magVar: MagVar{},
+			want:   "E0000",
+		},
+		{
+			name:   "WestZero",
+			magVar: MagVar{Value: math.Copysign(0, -1)},
+			want:   "W0000",
+		},
+		{
+			name:   "True",
+			magVar: MagVar{Reference: TrueNorth},
+			want:   "T0000",
+		},
+		{
+			name:    "OutOfRange",
+			magVar:  MagVar{Value: 181},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.magVar.MarshalText()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("MarshalText(%v) = %q, <nil> want _, <non-nil>", tt.magVar, got)
+				}
+				return
+			}
+			if err != nil || string(got) != tt.want {
+				t.Fatalf("MarshalText(%v) = %q, %v want %q, <nil>", tt.magVar, got, err, tt.want)
+			}
+			var parsed MagVar
+			if err := parsed.UnmarshalText(got); err != nil || parsed != tt.magVar {
+				t.Errorf("UnmarshalText(%q) = %v, %v want %v, <nil>", got, parsed, err, tt.magVar)
+			}
+		})
+	}
+}
+
+func TestAltitudeString(t *testing.T) {
+	for _, tt := range []struct {
+		altitude Altitude
+		want     string
+	}{
+		{Altitude{Feet: 3700}, "3700"},
+		{Altitude{Feet: 1500, Reference: AboveGroundLevel}, "1500 AGL"},
+		{Altitude{Feet: 5000, Reference: FlightLevel}, "FL050"},
+	} {
+		if got := tt.altitude.String(); got != tt.want {
+			t.Errorf("String() = %q want %q", got, tt.want)
+		}
+	}
+}
+
+func TestAltitudeReferenceString(t *testing.T) {
+	for _, tt := range []struct {
+		reference AltitudeReference
+		want      string
+	}{
+		{MeanSeaLevel, "MSL"},
+		{AboveGroundLevel, "AGL"},
+		{FlightLevel, "FL"},
+		{AltitudeReference(42), "AltitudeReference(42)"},
+	} {
+		if got := tt.reference.String(); got != tt.want {
+			t.Errorf("String() = %q want %q", got, tt.want)
+		}
+	}
+}
+
+func TestAltitudeMarshalText(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		altitude Altitude
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "Feet",
+			altitude: Altitude{Feet: 3700},
+			want:     "03700",
+		},
+		{
+			name:     "BelowSeaLevel",
+			altitude: Altitude{Feet: -100},
+			want:     "-0100",
+		},
+		{
+			name:     "FlightLevel",
+			altitude: Altitude{Feet: 18000, Reference: FlightLevel},
+			want:     "FL180",
+		},
+		{
+			name:     "TooHigh",
+			altitude: Altitude{Feet: 100000},
+			wantErr:  true,
+		},
+		{
+			name:     "PartialFlightLevel",
+			altitude: Altitude{Feet: 18050, Reference: FlightLevel},
+			wantErr:  true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.altitude.MarshalText()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("MarshalText(%v) = %q, <nil> want _, <non-nil>", tt.altitude, got)
+				}
+				return
+			}
+			if err != nil || string(got) != tt.want {
+				t.Fatalf("MarshalText(%v) = %q, %v want %q, <nil>", tt.altitude, got, err, tt.want)
+			}
+			var parsed Altitude
+			if err := parsed.UnmarshalText(got); err != nil || parsed != tt.altitude {
+				t.Errorf("UnmarshalText(%q) = %v, %v want %v, <nil>", got, parsed, err, tt.altitude)
+			}
+		})
+	}
+}
+
+func TestCoordinateMarshalText(t *testing.T) {
+	for _, text := range []string{"N37394620W122074675", "S00000001E000000001", "N90000000W180000000"} {
+		var c Coordinate
+		if err := c.UnmarshalText([]byte(text)); err != nil {
+			t.Fatalf("UnmarshalText(%q) = %v want <nil>", text, err)
+		}
+		if got, err := c.MarshalText(); err != nil || string(got) != text {
+			t.Errorf("MarshalText(%v) = %q, %v want %q, <nil>", c, got, err, text)
+		}
+	}
+	var c Coordinate
+	if err := c.UnmarshalText([]byte("N37394620")); err == nil {
+		t.Errorf("UnmarshalText() of latitude alone = <nil> want <non-nil>")
+	}
+	if got, err := (Coordinate{Lat: 91}).MarshalText(); err == nil {
+		t.Errorf("MarshalText() of latitude 91 = %q, <nil> want _, <non-nil>", got)
+	}
+}
+
+// typedLocalizer is a localizer record whose fields use the value types.
+type typedLocalizer struct {
+	Localizer           Coordinate `fixed:"33,51,left"`
+	Bearing             Bearing    `fixed:"52,55,left"`
+	GlideSlope          Coordinate `fixed:"56,74,left"`
+	Declination         MagVar     `fixed:"91,95,left"`
+	GlideSlopeElevation Altitude   `fixed:"98,102,left"`
+}
+
+func TestUnmarshalTextNonCanonical(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		text string
+		v    encoding.TextUnmarshaler
+	}{
+		{
+			name: "Coordinate",
+			text: "N3739321 W122071825",
+			v:    &Coordinate{},
+		},
+		{
+			name: "Bearing",
+			text: "295",
+			v:    &Bearing{},
+		},
+		{
+			name: "TrueBearing",
+			text: "2950",
+			v:    &TrueBearing{},
+		},
+		{
+			name: "MagVar",
+			text: "e0150",
+			v:    &MagVar{},
+		},
+		{
+			name: "Altitude",
+			text: "+3700",
+			v:    &Altitude{},
+		},
+		{
+			name: "BlankAltitude",
+			text: "",
+			v:    &Altitude{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.v.UnmarshalText([]byte(tt.text)); err == nil {
+				t.Errorf("UnmarshalText(%q) = <nil> want <non-nil>", tt.text)
+			}
+		})
+	}
+}
+
+func TestNullValues(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		text string
+		v    interface {
+			encoding.TextMarshaler
+			encoding.TextUnmarshaler
+		}
+		wantValid bool
+	}{
+		{
+			name:      "Coordinate",
+			text:      "N37393214W122071825",
+			v:         &NullCoordinate{},
+			wantValid: true,
+		},
+		{
+			name: "BlankCoordinate",
+			v:    &NullCoordinate{},
+		},
+		{
+			name:      "Bearing",
+			text:      "2950",
+			v:         &NullBearing{},
+			wantValid: true,
+		},
+		{
+			name: "BlankBearing",
+			v:    &NullBearing{},
+		},
+		{
+			name:      "MagVar",
+			text:      "E0150",
+			v:         &NullMagVar{},
+			wantValid: true,
+		},
+		{
+			name: "BlankMagVar",
+			v:    &NullMagVar{},
+		},
+		{
+			name:      "Altitude",
+			text:      "FL180",
+			v:         &NullAltitude{},
+			wantValid: true,
+		},
+		{
+			name: "BlankAltitude",
+			v:    &NullAltitude{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.v.UnmarshalText([]byte(tt.text)); err != nil {
+				t.Fatalf("UnmarshalText(%q) = %v want <nil>", tt.text, err)
+			}
+			if valid := reflect.ValueOf(tt.v).Elem().FieldByName("Valid").Bool(); valid != tt.wantValid {
+				t.Errorf("UnmarshalText(%q) Valid = %t want %t", tt.text, valid, tt.wantValid)
+			}
+			got, err := tt.v.MarshalText()
+			if err != nil || string(got) != tt.text {
+				t.Errorf("MarshalText() = %q, %v want %q, <nil>", got, err, tt.text)
+			}
+		})
+	}
+}
+
+func TestValueTypesInFixedWidthRecord(t *testing.T) {
+	const record = "SUSAP KBURK2IIBUR1   010950RW08 N34115264W1182220910789N34115527W1182154266809-12260500300E01206000725                     365471903"
+	for _, tt := range []struct {
+		name   string
+		record string
+		want   typedLocalizer
+	}{
+		{
+			name:   "Magnetic",
+			record: record,
+			want: typedLocalizer{
+				Localizer:           Coordinate{Lat: 34.197956, Lon: -118.372475},
+				Bearing:             Bearing{Value: 78.9},
+				GlideSlope:          Coordinate{Lat: 34.198686, Lon: -118.365072},
+				Declination:         MagVar{Value: -12},
+				GlideSlopeElevation: Altitude{Feet: 725},
+			},
+		},
+		{
+			name:   "True",
+			record: record[:51] + "079T" + record[55:],
+			want: typedLocalizer{
+				Localizer:           Coordinate{Lat: 34.197956, Lon: -118.372475},
+				Bearing:             Bearing{Value: 79, Reference: TrueNorth},
+				GlideSlope:          Coordinate{Lat: 34.198686, Lon: -118.365072},
+				Declination:         MagVar{Value: -12},
+				GlideSlopeElevation: Altitude{Feet: 725},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got typedLocalizer
+			if err := fixedwidth.Unmarshal([]byte(tt.record), &got); err != nil {
+				t.Fatalf("Unmarshal() = %v want <nil>", err)
+			}
+			approx := cmp.Comparer(func(x, y float64) bool { return math.Abs(x-y) < 0.000001 })
+			if diff := cmp.Diff(tt.want, got, approx); diff != "" {
+				t.Errorf("Unmarshal() had diffs (-want +got): %s", diff)
+			}
+			enc, err := fixedwidth.Marshal(got)
+			if err != nil {
+				t.Fatalf("Marshal() = _, %v want _, <nil>", err)
+			}
+			if diff := cmp.Diff(string(blankUnmodelled([]byte(tt.record), &got))[:102], string(enc)); diff != "" {
+				t.Errorf("Marshal() had diffs (-want +got): %s", diff)
+			}
+		})
+	}
+
+	// A localizer without a glide slope has a blank glide slope position.
+	blank := record[:55] + strings.Repeat(" ", 19) + record[74:]
+	var got typedLocalizer
+	if err := fixedwidth.Unmarshal([]byte(blank), &got); err == nil {
+		t.Errorf("Unmarshal(%q) = <nil> want <non-nil>", blank)
+	}
+}
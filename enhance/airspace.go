@@ -0,0 +1,14 @@
+package enhance
+
+import (
+	"github.com/wallaceicy06/enhance-faa-cifp/airspace"
+)
+
+// CollectAirspace is an option that adds every controlled and restrictive
+// airspace record in the data to assembler, so that the airspaces can be
+// drawn or queried once all of the data is processed.
+func CollectAirspace(assembler *airspace.Assembler) Option {
+	return func(p *processor) {
+		p.Airspace = assembler
+	}
+}
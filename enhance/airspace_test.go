@@ -0,0 +1,67 @@
+package enhance
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/wallaceicy06/enhance-faa-cifp/airspace"
+)
+
+func TestCollectAirspace(t *testing.T) {
+	in := strings.Join([]string{
+		"SUSAUCK2AKOAK PAC  A00101BC   R N37500000W122120000N37400000W12212000001000000   GND  M04000MOAKLAND CLASS C               123452002",
+		"SUSAUCK2AKOAK PAC  A00201BC   G N37400000W121592150                                                                        123452002",
+		"SUSAUCK2AKOAK PAC  A00301BC   G N37300000W122120000                                                                        123452002",
+		"SUSAUCK2AKOAK PAC  A00401BC   GEN37400000W122270000                                                                        123452002",
+		"SUSAURK2R2531      A00101BC   CE                   N37000000W1210000000050       GND  MFL180MR-2531 TEST                   123452002",
+		"SUSAURK2R2531      A00102CCONTROLLED BY OAKLAND CENTER                                                                     123452002",
+	}, "\n")
+	for _, process := range []struct {
+		name string
+		fn   func(out *bytes.Buffer, opts ...Option) error
+	}{
+		{"Process", func(out *bytes.Buffer, opts ...Option) error {
+			return Process(strings.NewReader(in), out, opts...)
+		}},
+		{"ProcessStream", func(out *bytes.Buffer, opts ...Option) error {
+			return ProcessStream(strings.NewReader(in), out, opts...)
+		}},
+	} {
+		t.Run(process.name, func(t *testing.T) {
+			assembler := airspace.NewAssembler(5)
+			var out bytes.Buffer
+			if err := process.fn(&out, CollectAirspace(assembler)); err != nil {
+				t.Fatalf("%s() = %v want <nil>", process.name, err)
+			}
+			if diff := cmp.Diff(in+"\n", out.String()); diff != "" {
+				t.Errorf("%s() out content not as expected (-want +got): %s", process.name, diff)
+			}
+			got, err := assembler.Airspaces()
+			if err != nil {
+				t.Fatalf("Airspaces() = _, %v want _, <nil>", err)
+			}
+			var names []string
+			for _, a := range got {
+				names = append(names, a.Name)
+			}
+			if diff := cmp.Diff([]string{"OAKLAND CLASS C", "R-2531 TEST"}, names); diff != "" {
+				t.Errorf("%s() collected airspaces had diffs (-want +got): %s", process.name, diff)
+			}
+		})
+	}
+}
+
+func TestCollectAirspaceBadRecord(t *testing.T) {
+	in := strings.NewReader("SUSAURK2R2531      A00101BC   XE                   N37000000W1210000000050       GND  MFL180MR-2531 TEST                   123452002")
+	var out bytes.Buffer
+	if err := Process(in, &out, CollectAirspace(airspace.NewAssembler(5))); err == nil {
+		t.Errorf("Process() = <nil> want <non-nil>")
+	}
+	// Airspace records are only decoded when they are collected.
+	in.Seek(0, 0)
+	if err := Process(in, &out); err != nil {
+		t.Errorf("Process() without CollectAirspace = %v want <nil>", err)
+	}
+}
@@ -0,0 +1,78 @@
+package enhance
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	zipMagic  = []byte("PK\x03\x04")
+	gzipMagic = []byte{0x1f, 0x8b}
+)
+
+// cifpFilePrefix is the prefix of the name of the ARINC data file in the CIFP
+// distribution. (e.g. FAACIFP18)
+const cifpFilePrefix = "FAACIFP"
+
+// ProcessArchive is like Process, but also accepts the data compressed as a
+// zip or gzip file, such as the CIFP distribution from the FAA. The format is
+// detected from the contents of in, which has the given size. A zip file must
+// contain exactly one file whose name starts with FAACIFP, which is processed
+// without extracting it. Data that is not compressed is processed as is.
+func ProcessArchive(in io.ReaderAt, size int64, out io.Writer, opts ...Option) error {
+	magic := make([]byte, len(zipMagic))
+	n, err := in.ReadAt(magic, 0)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("could not read data: %v", err)
+	}
+	magic = magic[:n]
+
+	switch {
+	case bytes.HasPrefix(magic, zipMagic):
+		zr, err := zip.NewReader(in, size)
+		if err != nil {
+			return fmt.Errorf("could not open zip file: %v", err)
+		}
+		f, err := findCIFPFile(zr)
+		if err != nil {
+			return err
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("could not open %q in zip file: %v", f.Name, err)
+		}
+		defer rc.Close()
+		return ProcessStream(rc, out, opts...)
+	case bytes.HasPrefix(magic, gzipMagic):
+		gr, err := gzip.NewReader(io.NewSectionReader(in, 0, size))
+		if err != nil {
+			return fmt.Errorf("could not open gzip file: %v", err)
+		}
+		defer gr.Close()
+		return ProcessStream(gr, out, opts...)
+	}
+	return Process(io.NewSectionReader(in, 0, size), out, opts...)
+}
+
+// findCIFPFile returns the ARINC data file in the zip file.
+func findCIFPFile(zr *zip.Reader) (*zip.File, error) {
+	var found *zip.File
+	for _, f := range zr.File {
+		if !strings.HasPrefix(path.Base(f.Name), cifpFilePrefix) || f.FileInfo().IsDir() {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("found more than one CIFP file in zip file: %q and %q", found.Name, f.Name)
+		}
+		found = f
+	}
+	if found == nil {
+		return nil, fmt.Errorf("could not find a file starting with %q in zip file", cifpFilePrefix)
+	}
+	return found, nil
+}
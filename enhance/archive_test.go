@@ -0,0 +1,119 @@
+package enhance
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// zipFiles returns a zip file that contains the given files, keyed by name.
+func zipFiles(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Could not create zip entry: %v", err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatalf("Could not write zip entry: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Could not close zip file: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// gzipData returns the data compressed with gzip.
+func gzipData(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(data); err != nil {
+		t.Fatalf("Could not write gzip data: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("Could not close gzip data: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcessArchive(t *testing.T) {
+	testData, err := ioutil.ReadFile("test_data_locduplicates.txt")
+	if err != nil {
+		t.Fatalf("Could not read test data file: %v", err)
+	}
+	want, err := ioutil.ReadFile("test_data_locduplicates_remove_out.txt")
+	if err != nil {
+		t.Fatalf("Could not read test data file: %v", err)
+	}
+	readme := []byte("This is not ARINC data.\n")
+	for _, tt := range []struct {
+		name    string
+		in      []byte
+		wantErr bool
+	}{
+		{
+			name: "Plain",
+			in:   testData,
+		},
+		{
+			name: "Zip",
+			in: zipFiles(t, map[string][]byte{
+				"CIFP_200521/FAACIFP18":            testData,
+				"CIFP_200521/Read_Me.txt":          readme,
+				"CIFP_200521/IAP_Change_Notes.txt": readme,
+			}),
+		},
+		{
+			name: "Gzip",
+			in:   gzipData(t, testData),
+		},
+		{
+			name:    "ZipWithoutCIFP",
+			in:      zipFiles(t, map[string][]byte{"Read_Me.txt": readme}),
+			wantErr: true,
+		},
+		{
+			name: "ZipWithTwoCIFPs",
+			in: zipFiles(t, map[string][]byte{
+				"FAACIFP18":     testData,
+				"old/FAACIFP18": testData,
+			}),
+			wantErr: true,
+		},
+		{
+			name:    "BadZip",
+			in:      []byte("PK\x03\x04 not really a zip file"),
+			wantErr: true,
+		},
+		{
+			name:    "BadGzip",
+			in:      []byte{0x1f, 0x8b, 0x00},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bytes.Buffer
+			err := ProcessArchive(bytes.NewReader(tt.in), int64(len(tt.in)), &got, RemoveDuplicateLocalizers(true))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ProcessArchive() = <nil> want <non-nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessArchive() = %v want <nil>", err)
+			}
+			if diff := cmp.Diff(want, got.Bytes()); diff != "" {
+				t.Errorf("ProcessArchive() out content not as expected: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package enhance
+
+import (
+	"fmt"
+	"log"
+	"math"
+)
+
+// defaultMaxDeviation is the deviation threshold in degrees used if none is
+// specified.
+const defaultMaxDeviation = 5.0
+
+// DeviationPolicy determines what happens to a localizer whose computed
+// bearing deviates from its published bearing by more than the deviation
+// threshold.
+type DeviationPolicy int
+
+const (
+	// DeviationKeep logs a warning but still writes the computed bearing.
+	// This is the default.
+	DeviationKeep DeviationPolicy = iota
+	// DeviationPublished writes the published bearing, converted to a true
+	// bearing, instead of the computed bearing.
+	DeviationPublished
+	// DeviationDrop leaves the localizer unenhanced, so that no simulation
+	// continuation record is written for it.
+	DeviationDrop
+)
+
+// String returns the name of the policy as used in reports.
+func (d DeviationPolicy) String() string {
+	switch d {
+	case DeviationKeep:
+		return "keep"
+	case DeviationPublished:
+		return "published"
+	case DeviationDrop:
+		return "drop"
+	}
+	return fmt.Sprintf("DeviationPolicy(%d)", int(d))
+}
+
+// DeviationCounts counts the localizers whose computed bearing was checked
+// against the deviation threshold, and the decisions made for those that
+// exceeded it.
+type DeviationCounts struct {
+	// Checked is the number of localizers whose computed bearing was
+	// compared with their published bearing.
+	Checked int `json:"checked"`
+	// Kept is the number of localizers that were written with the computed
+	// bearing anyway.
+	Kept int `json:"kept"`
+	// Published is the number of localizers that were written with the
+	// published bearing instead.
+	Published int `json:"published"`
+	// Dropped is the number of localizers that were left unenhanced.
+	Dropped int `json:"dropped"`
+}
+
+// Total returns the number of localizers that exceeded the threshold.
+func (c DeviationCounts) Total() int {
+	return c.Kept + c.Published + c.Dropped
+}
+
+// MaxDeviation is an option that sets the largest difference in degrees that
+// is tolerated between the computed and published true bearings of a
+// localizer. Localizers that exceed it are handled according to the policy
+// set by OnDeviation. A threshold of zero tolerates no difference at all. If
+// the option is not given, the threshold is 5 degrees.
+func MaxDeviation(degrees float64) Option {
+	return func(p *processor) {
+		p.MaxDeviation = &degrees
+	}
+}
+
+// OnDeviation is an option that sets the policy for localizers whose computed
+// bearing exceeds the deviation threshold. By default, DeviationKeep is used.
+func OnDeviation(policy DeviationPolicy) Option {
+	return func(p *processor) {
+		p.DeviationPolicy = policy
+	}
+}
+
+// checkDeviation applies the deviation policy to a localizer and returns the
+// bearing to write to its simulation continuation record. The signed delta
+// from the old to the computed bearing is recorded in the report, and the
+// threshold applies to its absolute value. The delta accounts for courses
+// near 360 degrees so that false positives are not encountered. (i.e. 0.0 and
+// 360.0) If the localizer should be left unenhanced, an error is returned.
+func (p *processor) checkDeviation(report *LocalizerReport, bearing, oldTrueBearing float64) (float64, error) {
+	threshold := defaultMaxDeviation
+	if p.MaxDeviation != nil {
+		threshold = *p.MaxDeviation
+	}
+	p.Deviations.Checked++
+	report.Delta = bearingDelta(oldTrueBearing, bearing)
+	if math.Abs(report.Delta) <= threshold {
+		return bearing, nil
+	}
+	report.Deviation = p.DeviationPolicy.String()
+	switch p.DeviationPolicy {
+	case DeviationPublished:
+		log.Printf("New localizer bearing at %q (%f) is more than %g degrees off the old one %f, using the old one.", report.LocalizerID, bearing, threshold, oldTrueBearing)
+		p.Deviations.Published++
+		report.ComputedTrueBearing = oldTrueBearing
+		return oldTrueBearing, nil
+	case DeviationDrop:
+		p.Deviations.Dropped++
+		return 0, fmt.Errorf("new localizer bearing at %q (%f) is more than %g degrees off the old one %f", report.LocalizerID, bearing, threshold, oldTrueBearing)
+	}
+	log.Printf("New localizer bearing at %q (%f) is more than %g degrees off the old one %f.", report.LocalizerID, bearing, threshold, oldTrueBearing)
+	p.Deviations.Kept++
+	return bearing, nil
+}
+
+// reportDeviations logs the number of localizers that exceeded the deviation
+// threshold and records the counts in the report.
+func (p *processor) reportDeviations() {
+	if d := p.Deviations; d.Total() > 0 {
+		log.Printf("%d of %d checked localizers exceeded the deviation threshold: %d kept, %d published, %d dropped.", d.Total(), d.Checked, d.Kept, d.Published, d.Dropped)
+	}
+	if p.Report != nil {
+		p.Report.Deviations = p.Deviations
+	}
+}
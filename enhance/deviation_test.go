@@ -0,0 +1,188 @@
+package enhance
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheckDeviation(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		opts          []Option
+		bearing       float64
+		want          float64
+		wantDelta     float64
+		wantErr       bool
+		wantDeviation string
+		wantCounts    DeviationCounts
+	}{
+		{
+			name:       "WithinThreshold",
+			opts:       []Option{OnDeviation(DeviationDrop)},
+			bearing:    298.2,
+			want:       298.2,
+			wantDelta:  -4.9,
+			wantCounts: DeviationCounts{Checked: 1},
+		},
+		{
+			name:          "Keep",
+			bearing:       298,
+			want:          298,
+			wantDelta:     -5.1,
+			wantDeviation: "keep",
+			wantCounts:    DeviationCounts{Checked: 1, Kept: 1},
+		},
+		{
+			name:          "Published",
+			opts:          []Option{OnDeviation(DeviationPublished)},
+			bearing:       298,
+			want:          303.1,
+			wantDelta:     -5.1,
+			wantDeviation: "published",
+			wantCounts:    DeviationCounts{Checked: 1, Published: 1},
+		},
+		{
+			name:          "Drop",
+			opts:          []Option{OnDeviation(DeviationDrop)},
+			bearing:       298,
+			wantDelta:     -5.1,
+			wantErr:       true,
+			wantDeviation: "drop",
+			wantCounts:    DeviationCounts{Checked: 1, Dropped: 1},
+		},
+		{
+			name:       "HigherThreshold",
+			opts:       []Option{MaxDeviation(10), OnDeviation(DeviationDrop)},
+			bearing:    298,
+			want:       298,
+			wantDelta:  -5.1,
+			wantCounts: DeviationCounts{Checked: 1},
+		},
+		{
+			name:          "LowerThreshold",
+			opts:          []Option{MaxDeviation(1), OnDeviation(DeviationDrop)},
+			bearing:       304.6,
+			wantDelta:     1.5,
+			wantErr:       true,
+			wantDeviation: "drop",
+			wantCounts:    DeviationCounts{Checked: 1, Dropped: 1},
+		},
+		{
+			name:       "ZeroThresholdSame",
+			opts:       []Option{MaxDeviation(0), OnDeviation(DeviationDrop)},
+			bearing:    303.1,
+			want:       303.1,
+			wantCounts: DeviationCounts{Checked: 1},
+		},
+		{
+			name:          "ZeroThresholdDifferent",
+			opts:          []Option{MaxDeviation(0), OnDeviation(DeviationDrop)},
+			bearing:       303.2,
+			wantDelta:     0.1,
+			wantErr:       true,
+			wantDeviation: "drop",
+			wantCounts:    DeviationCounts{Checked: 1, Dropped: 1},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newProcessor(tt.opts...)
+			l := &LocalizerReport{
+				LocalizerID:         "IHWD",
+				OldTrueBearing:      floatPtr(303.1),
+				ComputedTrueBearing: tt.bearing,
+				Estimator:           "FAF",
+			}
+			got, err := p.checkDeviation(l, tt.bearing, 303.1)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("checkDeviation() = _, <nil> want _, <non-nil>")
+				}
+			} else if err != nil {
+				t.Errorf("checkDeviation() = _, %v want _, <nil>", err)
+			} else if math.Abs(got-tt.want) > 0.00001 {
+				t.Errorf("checkDeviation() = %f, _ want %f, _", got, tt.want)
+			}
+			if math.Abs(l.Delta-tt.wantDelta) > 0.00001 {
+				t.Errorf("Delta = %f want %f", l.Delta, tt.wantDelta)
+			}
+			if l.Deviation != tt.wantDeviation {
+				t.Errorf("Deviation = %q want %q", l.Deviation, tt.wantDeviation)
+			}
+			if l.Estimator != "FAF" {
+				t.Errorf("Estimator = %q want %q", l.Estimator, "FAF")
+			}
+			if diff := cmp.Diff(tt.wantCounts, p.Deviations); diff != "" {
+				t.Errorf("Deviations had diffs (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckDeviationNoReport(t *testing.T) {
+	p := newProcessor(OnDeviation(DeviationPublished))
+	got, err := p.checkDeviation(&LocalizerReport{}, 292, 303.1)
+	if err != nil {
+		t.Fatalf("checkDeviation() = _, %v want _, <nil>", err)
+	}
+	if got != 303.1 {
+		t.Errorf("checkDeviation() = %f, _ want %f, _", got, 303.1)
+	}
+	if diff := cmp.Diff(DeviationCounts{Checked: 1, Published: 1}, p.Deviations); diff != "" {
+		t.Errorf("Deviations had diffs (-want +got): %s", diff)
+	}
+}
+
+func TestReportDeviations(t *testing.T) {
+	report := &Report{}
+	p := newProcessor(ReportTo(report))
+	p.Deviations = DeviationCounts{Checked: 5, Kept: 1, Dropped: 2}
+	p.reportDeviations()
+	if diff := cmp.Diff(DeviationCounts{Checked: 5, Kept: 1, Dropped: 2}, report.Deviations); diff != "" {
+		t.Errorf("Report.Deviations had diffs (-want +got): %s", diff)
+	}
+}
+
+func TestDeviationPolicyString(t *testing.T) {
+	for _, tt := range []struct {
+		policy DeviationPolicy
+		want   string
+	}{
+		{DeviationKeep, "keep"},
+		{DeviationPublished, "published"},
+		{DeviationDrop, "drop"},
+		{DeviationPolicy(42), "DeviationPolicy(42)"},
+	} {
+		if got := tt.policy.String(); got != tt.want {
+			t.Errorf("String() = %q want %q", got, tt.want)
+		}
+	}
+}
+
+func TestProcessDeviationPublishedBearingSource(t *testing.T) {
+	testData, err := ioutil.ReadFile("test_data.txt")
+	if err != nil {
+		t.Fatalf("Could not read test data file: %v", err)
+	}
+	var out bytes.Buffer
+	if err := Process(bytes.NewReader(testData), &out, MaxDeviation(0.001), OnDeviation(DeviationPublished)); err != nil {
+		t.Fatalf("Process() = %v want <nil>", err)
+	}
+	found := false
+	for _, line := range strings.Split(out.String(), "\n") {
+		if len(line) < 57 || line[12] != 'I' || line[21:23] != "2S" {
+			continue
+		}
+		found = true
+		if got := line[56:57]; got != "P" {
+			t.Errorf("Process() wrote bearing source %q want %q in %q", got, "P", line)
+		}
+	}
+	if !found {
+		t.Errorf("Process() wrote no simulation continuation records")
+	}
+}
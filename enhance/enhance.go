@@ -6,38 +6,89 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"math"
+	"sort"
 
-	fixedwidth "github.com/ianlopshire/go-fixedwidth"
 	geo "github.com/kellydunn/golang-geo"
+	"github.com/wallaceicy06/enhance-faa-cifp/airspace"
 	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+	"github.com/wallaceicy06/enhance-faa-cifp/geodesy"
 )
 
+// airportData holds the data of an airport or heliport. Heliports have
+// helipads instead of runways, and Helipads is nil for airports. The ICAO code
+// and reference point Position come from the primary record, and Position is
+// nil if it has not been read.
 type airportData struct {
-	MagVar     float64
+	MagVar     arinc.MagVar
+	Heliport   bool
+	ICAOCode   string
+	Position   *geo.Point
 	Waypoints  map[string]*geo.Point
+	Runways    map[string]*geo.Point
+	Helipads   map[string]*geo.Point
 	Approaches map[string]*locApchData
 }
 
-// ApproachForLoc returns the first approach that specifies the given localizer
-// ID as the recommended navaid. If there is no corresponding approach, nil is
-// returned.
-func (a *airportData) ApproachForLoc(LocalizerID string) *locApchData {
-	for _, apch := range a.Approaches {
+// ApproachForLoc returns the identifier and data of the first approach that
+// specifies the given localizer ID as the recommended navaid. If there is no
+// corresponding approach, nil is returned.
+func (a *airportData) ApproachForLoc(LocalizerID string) (string, *locApchData) {
+	for id, apch := range a.Approaches {
 		if apch.LocalizerID == LocalizerID {
-			return apch
+			return id, apch
 		}
 	}
-	return nil
+	return "", nil
 }
 
 type locApchData struct {
-	FinalApproachFix string
-	LocalizerID      string
+	FinalApproachFix    string
+	MissedApproachPoint string
+	LocalizerID         string
+}
+
+// EarthModel is a model of the shape of the earth that is used to compute
+// bearings between two points.
+type EarthModel int
+
+const (
+	// EarthModelWGS84 computes bearings along geodesics on the WGS-84
+	// ellipsoid. This is the default.
+	EarthModelWGS84 EarthModel = iota
+	// EarthModelSphere computes bearings along great circles on a spherical
+	// earth. This is less accurate, and is provided for comparison with
+	// earlier versions of this program.
+	EarthModelSphere
+)
+
+// Bearing returns the initial true bearing in degrees from one point to
+// another using the earth model. The bearing is in the range [0, 360).
+func (m EarthModel) Bearing(from, to *geo.Point) (float64, error) {
+	switch m {
+	case EarthModelWGS84:
+		return geodesy.InitialBearing(from.Lat(), from.Lng(), to.Lat(), to.Lng())
+	case EarthModelSphere:
+		bearing := from.BearingTo(to)
+		// This corects a bug in the golang-geo library that causes negative bearings.
+		if bearing < 0 {
+			bearing = 360 + bearing
+		}
+		return bearing, nil
+	}
+	return 0, fmt.Errorf("unknown earth model %d", m)
 }
 
 type Option func(p *processor)
 
+// BearingEarthModel is an option that selects the earth model used to compute
+// localizer bearings. By default, bearings are computed on the WGS-84
+// ellipsoid.
+func BearingEarthModel(m EarthModel) Option {
+	return func(p *processor) {
+		p.EarthModel = m
+	}
+}
+
 // RemoveDuplicateLocalizers is an option that enables or disables removal of
 // duplicate localizers in the data. If enabled, duplicate localizers that
 // are specified as an LDA with or without glideslope will be removed from
@@ -52,34 +103,37 @@ func RemoveDuplicateLocalizers(enabled bool) Option {
 // Process reads ARINC data from in and writes the modified data to out. All
 // localizers in the input data will be augmented with an extension field that
 // includes a more accurate bearing for the localizer. This bearing is computed
-// as the course of the leg from the final approach fix to the missed approach
-// point.
+// by the first estimator in the chain set by BearingEstimators that succeeds,
+// which by default is the course of the leg from the final approach fix to
+// the localizer. The data is read twice, first to collect terminal navaids
+// and, if duplicate localizer removal is enabled, localizers. Use
+// ProcessStream for data that cannot be rewound.
 func Process(in io.ReadSeeker, out io.Writer, opts ...Option) error {
 	p := newProcessor(opts...)
 
-	// If duplicate localizer removal is enabled, the data must be pre-processed
-	// to collect all localizers.
-	if p.RemoveDuplicateLocalizers {
-		if _, err := in.Seek(0, io.SeekStart); err != nil {
-			return fmt.Errorf("could not seek to start of file: %v", err)
-		}
-		s := bufio.NewScanner(in)
-		for s.Scan() {
-			p.preProcess(s.Bytes())
-		}
-		if err := s.Err(); err != nil {
-			return fmt.Errorf("problem parsing data: %v", err)
-		}
+	// The data is pre-processed to collect terminal navaids and, if duplicate
+	// localizer removal is enabled, all localizers.
+	if _, err := in.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("could not seek to start of file: %v", err)
+	}
+	pre := bufio.NewScanner(in)
+	for pre.Scan() {
+		p.preProcess(pre.Bytes())
+	}
+	if err := pre.Err(); err != nil {
+		return fmt.Errorf("problem parsing data: %v", err)
 	}
 
 	if _, err := in.Seek(0, io.SeekStart); err != nil {
 		return fmt.Errorf("could not seek to start of file: %v", err)
 	}
 	s := bufio.NewScanner(in)
-	for s.Scan() {
+	for line := 1; s.Scan(); line++ {
 		processed, err := p.processRecord(s.Bytes())
 		if err != nil {
-			return fmt.Errorf("could not process record: %v", err)
+			if processed, err = p.recordFailed(line, s.Bytes(), err); err != nil {
+				return err
+			}
 		}
 		if _, err := out.Write(processed); err != nil {
 			return fmt.Errorf("could not write processed data: %v", err)
@@ -89,7 +143,12 @@ func Process(in io.ReadSeeker, out io.Writer, opts ...Option) error {
 	if err := s.Err(); err != nil {
 		return fmt.Errorf("problem parsing data: %v", err)
 	}
-	return nil
+	p.reportUnusedOverrides()
+	p.reportDeviations()
+	p.resolveHoldings()
+	p.resolveMSAs()
+	p.resolveTAAs()
+	return p.recordErrors()
 }
 
 type processor struct {
@@ -97,6 +156,27 @@ type processor struct {
 	OtherWaypoints            map[string]*geo.Point
 	DuplicateLocalizers       map[string]bool
 	RemoveDuplicateLocalizers bool
+	EarthModel                EarthModel
+	Estimators                []BearingEstimator
+	MaxDeviation              *float64
+	DeviationPolicy           DeviationPolicy
+	Deviations                DeviationCounts
+	Overrides                 map[localizerKey]*Override
+	UsedOverrides             map[localizerKey]bool
+	StreamBufferLimit         int
+	ContinueOnError           bool
+	MaxErrors                 int
+	Errors                    []*RecordError
+	Report                    *Report
+	Holdings                  *[]*Holding
+	PendingHoldings           []*Holding
+	OtherMagVars              map[string]arinc.MagVar
+	MSAs                      MSAs
+	PendingMSAs               []*MSA
+	TAAs                      TAAs
+	PendingTAAs               []*TAA
+	Airspace                  *airspace.Assembler
+	ValidatePathPoints        bool
 }
 
 func newProcessor(options ...Option) *processor {
@@ -111,228 +191,418 @@ func newProcessor(options ...Option) *processor {
 	return p
 }
 
+// preProcess collects the localizer, if duplicate localizer removal is
+// enabled, or the terminal navaid in the record before the data is processed.
+// Terminal navaid records come after the localizers of their airport in the
+// data, so they would otherwise not be known when the localizers are
+// enhanced. Other records are not parsed. Errors are left for processRecord to
+// report, but are returned for testing.
 func (p *processor) preProcess(recordBytes []byte) error {
-	r := arinc.Record{}
-	dec := fixedwidth.NewDecoder(bytes.NewReader(recordBytes))
-	if err := dec.Decode(&r); err != nil {
-		return fmt.Errorf("problem unmarshalling data: %v", err)
-	}
-	if r.SectionCode == arinc.SectionCodeAirport {
-		a := arinc.AirportEnrouteRecord{}
-		if err := fixedwidth.Unmarshal(recordBytes, &a); err != nil {
-			return fmt.Errorf("problem unmarshalling airport: %v", err)
-		}
-		if r.SectionCode == arinc.SectionCodeAirport && a.SubsectionCode == arinc.SubsectionCodeLocGS {
-			loc := arinc.AirportLocGSPrimaryRecord{}
-			if err := fixedwidth.Unmarshal(recordBytes, &loc); err != nil {
-				return fmt.Errorf("problem unmarshalling data: %v", err)
-			}
-			if _, ok := p.DuplicateLocalizers[loc.LocalizerID]; ok {
-				p.DuplicateLocalizers[loc.LocalizerID] = true
-			} else {
-				p.DuplicateLocalizers[loc.LocalizerID] = false
-			}
+	section, subsection, err := arinc.SectionAndSubsection(recordBytes)
+	if err != nil {
+		return err
+	}
+	if section != arinc.SectionCodeAirport {
+		return nil
+	}
+	switch subsection {
+	case arinc.SubsectionCodeLocGS:
+		if !p.RemoveDuplicateLocalizers {
+			return nil
 		}
+	case arinc.SubsectionCodeTerminalNDB, arinc.SubsectionCodeLocalizerMarker:
+	default:
+		return nil
+	}
+	rec, err := arinc.Parse(recordBytes)
+	if err != nil {
+		return err
+	}
+	if loc, ok := rec.(*arinc.AirportLocGSPrimaryRecord); ok {
+		p.collectLocalizer(loc)
 	}
+	p.addTerminalNavaid(rec)
 	return nil
 }
 
+// collectLocalizer records the localizer's ID, and marks the ID as duplicated
+// if it has been seen before.
+func (p *processor) collectLocalizer(loc *arinc.AirportLocGSPrimaryRecord) {
+	if _, ok := p.DuplicateLocalizers[loc.LocalizerID]; ok {
+		p.DuplicateLocalizers[loc.LocalizerID] = true
+	} else {
+		p.DuplicateLocalizers[loc.LocalizerID] = false
+	}
+}
+
+// processRecord processes the record and returns the records to write in its
+// place, each followed by a newline. Records that are not changed are written
+// from their original bytes.
 func (p *processor) processRecord(recordBytes []byte) ([]byte, error) {
-	writeRecord := func(buf *bytes.Buffer, records ...interface{}) ([]byte, error) {
-		for _, record := range records {
-			toWrite, err := fixedwidth.Marshal(record)
-			if err != nil {
-				return nil, fmt.Errorf("could not marshal record: %v", err)
+	recordBytes = bytes.TrimSuffix(recordBytes, []byte("\n"))
+	rec, err := arinc.Parse(recordBytes)
+	if err != nil {
+		return nil, err
+	}
+	r := rec.Header()
+	// The scanner reuses recordBytes, so the unchanged record is copied.
+	unchanged := append(append([]byte(nil), recordBytes...), '\n')
+
+	if a, ok := rec.(arinc.AirportRecord); ok {
+		switch r.SectionCode {
+		case arinc.SectionCodeAirport:
+			p.airport(a.Airport().AirportID)
+		case arinc.SectionCodeHeliport:
+			h := p.airport(a.Airport().AirportID)
+			if !h.Heliport {
+				h.Heliport = true
+				h.Helipads = make(map[string]*geo.Point)
 			}
-			fmt.Fprintf(buf, "%s\n", toWrite)
 		}
-		return buf.Bytes(), nil
-	}
-	out := &bytes.Buffer{}
-	r := arinc.Record{}
-	dec := fixedwidth.NewDecoder(bytes.NewReader(recordBytes))
-	if err := dec.Decode(&r); err != nil {
-		return nil, fmt.Errorf("problem unmarshalling data: %v", err)
 	}
 
-	if r.SectionCode == arinc.SectionCodeNavaid {
-		switch r.SubsectionCode {
-		case arinc.SubsectionCodeNavaidNDB:
-			n := arinc.NDBNavaidRecord{}
-			if err := fixedwidth.Unmarshal(recordBytes, &n); err != nil {
-				return nil, fmt.Errorf("problem unmarshalling NDB: %v", err)
-			}
-			lat, lon, err := arinc.LatLon(n.NDBLatitude, n.NDBLongitude)
-			if err != nil {
-				return nil, fmt.Errorf("problem converting NDB latitude/longitude: %v", err)
-			}
-			p.OtherWaypoints[n.NDBID] = geo.NewPoint(lat, lon)
-		case arinc.SubsectionCodeNavaidVHF:
-			n := arinc.VHFNavaidRecord{}
-			if err := fixedwidth.Unmarshal(recordBytes, &n); err != nil {
-				return nil, fmt.Errorf("problem unmarshalling VOR: %v", err)
-			}
-			// Skip NDB/DME or DME with no corresponding VOR.
-			if n.VORLatitude == "" || n.VORLongitude == "" {
-				break
-			}
-			lat, lon, err := arinc.LatLon(n.VORLatitude, n.VORLongitude)
-			if err != nil {
-				return nil, fmt.Errorf("problem converting VOR %q latitude/longitude: %v", n.VORID, err)
+	switch rec := rec.(type) {
+	case *arinc.NDBNavaidRecord:
+		p.OtherWaypoints[rec.NDBID] = point(rec.NDB)
+		p.recordMagVar(rec.NDBID, rec.MagneticVar)
+	case *arinc.VHFNavaidRecord:
+		// Skip NDB/DME or DME with no corresponding VOR.
+		if !rec.VOR.Valid {
+			break
+		}
+		// The station declination is not recorded as the magnetic
+		// variation, since the two differ at VORs that have not been
+		// realigned recently.
+		p.OtherWaypoints[rec.VORID] = point(rec.VOR.Coordinate)
+	case *arinc.WaypointPrimaryRecord:
+		if r.SectionCode == arinc.SectionCodeEnroute {
+			p.OtherWaypoints[rec.WaypointID] = point(rec.Waypoint)
+			p.recordMagVar(rec.WaypointID, rec.DynamicMagVar)
+		} else {
+			p.Airports[rec.AirportID].Waypoints[rec.WaypointID] = point(rec.Waypoint)
+		}
+	case *arinc.TerminalNDBRecord, *arinc.AirportLocalizerMarkerRecord:
+		p.addTerminalNavaid(rec)
+	case *arinc.AirportPrimaryRecord:
+		p.Airports[rec.AirportID].MagVar = rec.MagneticVar
+		p.Airports[rec.AirportID].ICAOCode = rec.AirportEnrouteRecord.ICAOCode
+		p.Airports[rec.AirportID].Position = point(rec.AirportRefPoint)
+	case *arinc.HeliportPrimaryRecord:
+		p.Airports[rec.AirportID].MagVar = rec.MagneticVar
+		p.Airports[rec.AirportID].ICAOCode = rec.AirportEnrouteRecord.ICAOCode
+		p.Airports[rec.AirportID].Position = point(rec.HeliportRefPoint)
+	case *arinc.HelipadRecord:
+		p.Airports[rec.AirportID].Helipads[rec.HelipadID] = point(rec.Helipad)
+	case *arinc.AirportRunwayPrimaryRecord:
+		p.Airports[rec.AirportID].Runways[rec.RunwayID] = point(rec.Runway)
+	case *arinc.AirportProcedurePrimaryRecord:
+		if rec.SubsectionCode == arinc.SubsectionCodeApproachProcedure && rec.IsLocalizerFrontCourseApproach() {
+			if rec.IsFinalApproachFix() || rec.IsMissedApproachPoint() {
+				lc, ok := p.Airports[rec.AirportID].Approaches[rec.ProcedureID]
+				if !ok {
+					lc = &locApchData{}
+					p.Airports[rec.AirportID].Approaches[rec.ProcedureID] = lc
+				}
+				if rec.RecommendedNavaid != "" {
+					lc.LocalizerID = rec.RecommendedNavaid
+				}
+				if rec.IsFinalApproachFix() {
+					lc.FinalApproachFix = rec.FixID
+				} else {
+					lc.MissedApproachPoint = rec.FixID
+				}
 			}
-			p.OtherWaypoints[n.VORID] = geo.NewPoint(lat, lon)
 		}
-	} else if r.SectionCode == arinc.SectionCodeAirport || r.SectionCode == arinc.SectionCodeEnroute {
-		a := arinc.AirportEnrouteRecord{}
-		if err := fixedwidth.Unmarshal(recordBytes, &a); err != nil {
-			return nil, fmt.Errorf("problem unmarshalling airport: %v", err)
+	case *arinc.AirportPathPointPrimaryRecord:
+		if p.ValidatePathPoints {
+			p.validatePathPoint(rec)
 		}
-		if r.SectionCode == arinc.SectionCodeEnroute && r.SubsectionCode == arinc.SubsectionCodeEnrouteWaypoint {
-			wpt := arinc.WaypointPrimaryRecord{}
-			if err := fixedwidth.Unmarshal(recordBytes, &wpt); err != nil {
-				return nil, fmt.Errorf("problem unmarshalling waypoint: %v", err)
+	case *arinc.HoldingPatternRecord:
+		if p.Holdings != nil {
+			if err := p.collectHolding(rec); err != nil {
+				return nil, err
 			}
-			lat, lon, err := arinc.LatLon(wpt.WaypointLatitude, wpt.WaypointLongitude)
-			if err != nil {
-				return nil, fmt.Errorf("problem converting waypoint latitude/longitude: %v", err)
+		}
+	case *arinc.AirportMSARecord:
+		if p.MSAs != nil {
+			if err := p.collectMSA(rec); err != nil {
+				return nil, err
 			}
-			p.OtherWaypoints[wpt.WaypointID] = geo.NewPoint(lat, lon)
 		}
-		if r.SectionCode == arinc.SectionCodeAirport {
-			if _, ok := p.Airports[a.AirportID]; !ok {
-				p.Airports[a.AirportID] = &airportData{
-					Waypoints:  make(map[string]*geo.Point),
-					Approaches: make(map[string]*locApchData),
-				}
+	case *arinc.AirportTAARecord:
+		if p.TAAs != nil {
+			if err := p.collectTAA(rec); err != nil {
+				return nil, err
 			}
-			if a.SubsectionCode == arinc.SubsectionCodeAirportRefPoint {
-				aptRef := arinc.AirportPrimaryRecord{}
-				if err := fixedwidth.Unmarshal(recordBytes, &aptRef); err != nil {
-					return nil, fmt.Errorf("problem unmarshalling airport: %v", err)
-				}
-				v, _, err := arinc.ParseMagneticVar(aptRef.MagneticVar)
-				if err != nil {
-					return nil, fmt.Errorf("could not parse magnetic variation: %v", err)
-				}
-				p.Airports[a.AirportID].MagVar = v
+		}
+	case *arinc.ControlledAirspaceRecord:
+		if p.Airspace != nil {
+			if err := p.Airspace.AddControlled(rec); err != nil {
+				return nil, err
 			}
-			if a.SubsectionCode == arinc.SubsectionCodeTerminalWaypoint {
-				wpt := arinc.WaypointPrimaryRecord{}
-				if err := fixedwidth.Unmarshal(recordBytes, &wpt); err != nil {
-					return nil, fmt.Errorf("problem unmarshalling waypoint: %v", err)
-				}
-				lat, lon, err := arinc.LatLon(wpt.WaypointLatitude, wpt.WaypointLongitude)
-				if err != nil {
-					return nil, fmt.Errorf("problem converting waypoint latitude/longitude: %v", err)
-				}
-				p.Airports[wpt.AirportID].Waypoints[wpt.WaypointID] = geo.NewPoint(lat, lon)
+		}
+	case *arinc.RestrictiveAirspaceRecord:
+		if p.Airspace != nil {
+			if err := p.Airspace.AddRestrictive(rec); err != nil {
+				return nil, err
 			}
-			if a.SubsectionCode == arinc.SubsectionCodeApproachProcedure {
-				apch := arinc.AirportProcedurePrimaryRecord{}
-				if err := fixedwidth.Unmarshal(recordBytes, &apch); err != nil {
-					return nil, fmt.Errorf("problem unmarshalling procedure: %v", err)
-				}
-				if apch.IsLocalizerFrontCourseApproach() {
-					if apch.IsFinalApproachFix() {
-						lc, ok := p.Airports[apch.AirportID].Approaches[apch.ProcedureID]
-						if !ok {
-							lc = &locApchData{}
-							p.Airports[apch.AirportID].Approaches[apch.ProcedureID] = lc
-						}
-						lc.LocalizerID = apch.RecommendedNavaid
-						lc.FinalApproachFix = apch.FixID
-					}
+		}
+	case *arinc.AirportLocGSPrimaryRecord:
+		loc := rec
+		report := p.reportLocalizer(loc)
+		if dup, ok := p.DuplicateLocalizers[loc.LocalizerID]; ok && dup {
+			if isLDA(loc) {
+				log.Printf("Skipping duplicate localizer LDA facility: %q at %q", loc.LocalizerID, loc.AirportID)
+				// The override matched a localizer in the data, so it is
+				// marked as used even though it is not applied.
+				if p.findOverride(loc.AirportID, loc.LocalizerID) != nil {
+					log.Printf("Override for localizer %q at %q is not applied because the localizer was removed as a duplicate.", loc.LocalizerID, loc.AirportID)
 				}
+				report.SkipReason = skipDuplicateLDA
+				return nil, nil
 			}
-			if a.SubsectionCode == arinc.SubsectionCodeLocGS {
-				loc := arinc.AirportLocGSPrimaryRecord{}
-				if err := fixedwidth.Unmarshal(recordBytes, &loc); err != nil {
-					return nil, fmt.Errorf("problem unmarshalling data: %v", err)
-				}
+		}
+		contRecord, err := p.processLocalizer(loc, report)
+		if err != nil {
+			log.Printf("Skipping localizer %q at %q: %v", loc.LocalizerID, loc.AirportID, err)
+			report.SkipReason = err.Error()
+			return unchanged, nil
+		}
+		locBytes, err := arinc.Marshal(loc, recordBytes)
+		if err != nil {
+			return nil, err
+		}
+		contBytes, err := arinc.Marshal(contRecord, nil)
+		if err != nil {
+			return nil, err
+		}
+		out := &bytes.Buffer{}
+		fmt.Fprintf(out, "%s\n%s\n", locBytes, contBytes)
+		return out.Bytes(), nil
+	}
+	return unchanged, nil
+}
 
-				if dup, ok := p.DuplicateLocalizers[loc.LocalizerID]; ok && dup {
-					if loc.ILSCategory == "A" || loc.ILSCategory == "L" {
-						log.Printf("Skipping duplicate localizer LDA facility: %q at %q", loc.LocalizerID, loc.AirportID)
-						return nil, nil
-					}
-				}
-				contRecord, err := p.processLocalizer(&loc)
-				if err != nil {
-					log.Printf("Skipping localizer %q at %q: %v", loc.LocalizerID, loc.AirportID, err)
-					return writeRecord(out, r)
-				}
+// skipDuplicateLDA is the skip reason reported for duplicate LDA localizers
+// that were removed from the output.
+const skipDuplicateLDA = "removed duplicate LDA localizer"
 
-				// There is some bug in the fixedwidth parser that causes these fields to not be parsed properly.
-				// This is quick fix for the interim.
-				contRecord.Data = loc.Data
+// isLDA returns true if the localizer is an LDA facility, with or without a
+// glideslope.
+func isLDA(loc *arinc.AirportLocGSPrimaryRecord) bool {
+	return loc.ILSCategory == "A" || loc.ILSCategory == "L"
+}
 
-				return writeRecord(out, loc, contRecord)
-			}
+// reportLocalizer returns a new report entry for the localizer. The entry is
+// added to the processor's report, if there is one.
+func (p *processor) reportLocalizer(loc *arinc.AirportLocGSPrimaryRecord) *LocalizerReport {
+	// The bearing was decoded from the record, so it can be encoded.
+	published, _ := loc.LocalizerBearing.MarshalText()
+	r := &LocalizerReport{
+		Airport:          loc.AirportID,
+		LocalizerID:      loc.LocalizerID,
+		Category:         loc.ILSCategory,
+		PublishedBearing: string(published),
+	}
+	if a, ok := p.Airports[loc.AirportID]; ok {
+		if b, err := publishedTrueBearing(loc, a.MagVar); err == nil {
+			r.OldTrueBearing = &b
 		}
 	}
-	return writeRecord(out, r)
+	if p.Report != nil {
+		p.Report.Localizers = append(p.Report.Localizers, r)
+	}
+	return r
 }
 
-func (p *processor) processLocalizer(loc *arinc.AirportLocGSPrimaryRecord) (*arinc.AirportLocGSSimContinuationRecord, error) {
+// processLocalizer computes a new bearing for the localizer and returns a
+// simulation continuation record that contains it. If there is an override
+// for the localizer, it is applied instead. The outcome is recorded in report.
+func (p *processor) processLocalizer(loc *arinc.AirportLocGSPrimaryRecord, report *LocalizerReport) (*arinc.AirportLocGSSimContinuationRecord, error) {
 	a, ok := p.Airports[loc.AirportID]
 	if !ok {
 		// This case is pretty much impossible because the airport should have been added before this is called.
 		return nil, fmt.Errorf("found localizer %q without corresponding airport %q", loc.LocalizerID, loc.AirportID)
 	}
-	apch := a.ApproachForLoc(loc.LocalizerID)
-	if apch == nil {
-		return nil, fmt.Errorf("could not find corresponding approach for localizer %q", loc.LocalizerID)
-	}
-	fapWaypoint, ok := a.Waypoints[apch.FinalApproachFix]
-	if !ok {
-		eWpt, ok := p.OtherWaypoints[apch.FinalApproachFix]
-		if !ok {
-			return nil, fmt.Errorf("could not find corresponding waypoint for final approach fix %q,", apch.FinalApproachFix)
+	if o := p.findOverride(loc.AirportID, loc.LocalizerID); o != nil {
+		if o.Skip || o.TrueBearing == nil {
+			return nil, fmt.Errorf("localizer is skipped by an override")
+		}
+		report.ComputedTrueBearing = *o.TrueBearing
+		if report.OldTrueBearing != nil {
+			report.Delta = bearingDelta(*report.OldTrueBearing, *o.TrueBearing)
 		}
-		fapWaypoint = eWpt
+		report.Estimator = OverrideEstimatorID
+		return simContinuationRecord(loc, trueBearing(*o.TrueBearing), OverrideEstimatorID)
 	}
-	lat, lon, err := arinc.LatLon(loc.LocalizerLatitude, loc.LocalizerLongitude)
+	oldTrueBearing, err := publishedTrueBearing(loc, a.MagVar)
 	if err != nil {
-		return nil, fmt.Errorf("could not calculate latitude/longitude for localizer %q: %v", loc.LocalizerID, err)
+		return nil, err
+	}
+	data := &LocalizerData{
+		Record:     loc,
+		Position:   point(loc.Localizer),
+		MagVar:     a.MagVar,
+		EarthModel: p.EarthModel,
+	}
+	apchAirport := a
+	apchID, apch := a.ApproachForLoc(loc.LocalizerID)
+	if apch == nil {
+		apchAirport, apchID, apch = p.heliportApproachForLoc(a, loc)
 	}
-	locPosition := geo.NewPoint(lat, lon)
-	bearing := fapWaypoint.BearingTo(locPosition)
-	// This corects a bug in the golang-geo library that causes negative bearings.
-	if bearing < 0 {
-		bearing = 360 + bearing
+	if apch != nil {
+		data.ApproachID = apchID
+		data.FinalApproachFixID = apch.FinalApproachFix
+		report.FinalApproachFix = apch.FinalApproachFix
+		data.FinalApproachFix = p.findFix(apchAirport, apch.FinalApproachFix)
+		data.MissedApproachPointID = apch.MissedApproachPoint
+		data.MissedApproachPoint = p.findFix(apchAirport, apch.MissedApproachPoint)
 	}
+	data.RunwayThreshold = a.Runways[loc.RunwayIdentifier]
+	if oppositeID, err := arinc.OppositeRunwayID(loc.RunwayIdentifier); err == nil {
+		data.OppositeThreshold = a.Runways[oppositeID]
+	}
+	bearing, estimator, err := p.estimateBearing(data)
+	if err != nil {
+		return nil, err
+	}
+	report.ComputedTrueBearing = bearing
+	report.Estimator = estimator.ID()
 
-	// This section checks that the new bearing is close to the old one. If it
-	// is not, then a warning is logged but the new bearing is still used.
-	magVar := p.Airports[loc.AirportID].MagVar
-	oldBrg, isTrue, err := arinc.ParseBearing(loc.LocalizerBearing)
+	bearing, err = p.checkDeviation(report, bearing, oldTrueBearing)
 	if err != nil {
-		return nil, fmt.Errorf("could not parse localizer bearing: %v", err)
-	}
-	oldTrueBearing := oldBrg
-	if !isTrue {
-		oldTrueBearing = oldBrg - magVar
-		if oldTrueBearing < 0 {
-			oldTrueBearing = 360 + oldTrueBearing
-		} else if oldTrueBearing >= 360 {
-			oldTrueBearing = oldTrueBearing - 360
-		}
+		return nil, err
 	}
-	// The various checks ensure that false postives are not encountered when
-	// the final approach course is near 360 degrees. (i.e. 0.0 and 360.0)
-	if math.Abs(bearing-oldTrueBearing) > 5 && math.Abs(bearing+360-oldTrueBearing) > 5 && math.Abs(bearing-oldTrueBearing-360) > 5 {
-		log.Printf("New localizer bearing at %q (%f) is more than 5 degrees off the old one %f.", loc.LocalizerID, bearing, oldTrueBearing)
+	sourceID := report.Estimator
+	if report.Deviation == DeviationPublished.String() {
+		// The published bearing is written instead of the estimate.
+		sourceID = PublishedBearingEstimator{}.ID()
 	}
 
+	return simContinuationRecord(loc, trueBearing(bearing), sourceID)
+}
+
+// trueBearing returns the bearing in degrees as a true bearing. Estimators and
+// overrides always give true bearings.
+func trueBearing(deg float64) arinc.Bearing {
+	return arinc.Bearing{Value: deg, Reference: arinc.TrueNorth}
+}
+
+// simContinuationRecord marks the localizer as having a continuation record
+// and returns a simulation continuation record with the given true bearing,
+// whose bearing source notes the estimator that produced it. If the bearing
+// is not a true bearing or is out of range, an error is returned and the
+// localizer is not changed.
+func simContinuationRecord(loc *arinc.AirportLocGSPrimaryRecord, bearing arinc.Bearing, estimatorID string) (*arinc.AirportLocGSSimContinuationRecord, error) {
+	if _, err := bearing.EncodeTrueBearing(); err != nil {
+		return nil, err
+	}
 	loc.ContinuationRecordNumber = "1"
-	contRecord := &arinc.AirportLocGSSimContinuationRecord{
+	return &arinc.AirportLocGSSimContinuationRecord{
 		AirportEnrouteRecord:     loc.AirportEnrouteRecord,
 		LocalizerID:              loc.LocalizerID,
 		ILSCategory:              loc.ILSCategory,
 		ContinuationRecordNumber: "2",
 		ApplicationType:          arinc.ContinuationRecordSimulation,
-		LocalizerTrueBearing:     arinc.EncodeBearing(bearing),
-		LocalizerBearingSource:   arinc.LocalizerBearingSourceNotGovt,
+		LocalizerTrueBearing:     arinc.TrueBearing{Bearing: bearing},
+		LocalizerBearingSource:   bearingSource(estimatorID),
+		// The continuation record has the file record number and cycle date
+		// of the localizer record.
+		Data: loc.Data,
+	}, nil
+}
+
+// airport returns the data for the airport with the given identifier, adding
+// it if it is not known yet.
+func (p *processor) airport(id string) *airportData {
+	a, ok := p.Airports[id]
+	if !ok {
+		a = &airportData{
+			Waypoints:  make(map[string]*geo.Point),
+			Runways:    make(map[string]*geo.Point),
+			Approaches: make(map[string]*locApchData),
+		}
+		p.Airports[id] = a
+	}
+	return a
+}
+
+// addTerminalNavaid adds the position of a terminal NDB or of the compass
+// locator at a localizer marker to the waypoints of its airport, so that
+// procedures can use it as a fix. Procedures refer to a marker by the
+// identifier of its locator, so markers without one are ignored, as are other
+// records. If a locator has no position of its own, the position of the marker
+// is used, and if neither has a position, the locator is ignored.
+func (p *processor) addTerminalNavaid(rec arinc.TypedRecord) {
+	switch rec := rec.(type) {
+	case *arinc.TerminalNDBRecord:
+		p.airport(rec.AirportID).Waypoints[rec.NDBID] = point(rec.NDB)
+	case *arinc.AirportLocalizerMarkerRecord:
+		if rec.LocatorID == "" {
+			return
+		}
+		c := rec.Locator
+		if !c.Valid {
+			c = rec.Marker
+		}
+		if !c.Valid {
+			return
+		}
+		p.airport(rec.AirportID).Waypoints[rec.LocatorID] = point(c.Coordinate)
+	}
+}
+
+// point returns the point at the coordinate.
+func point(c arinc.Coordinate) *geo.Point {
+	return geo.NewPoint(c.Lat, c.Lon)
+}
+
+// heliportMaxLocDistance is the largest distance in kilometers between a
+// heliport and an airport whose localizer an approach to the heliport may use.
+const heliportMaxLocDistance = 50
+
+// heliportApproachForLoc returns the heliport, identifier, and data of an
+// approach to a heliport that specifies the localizer as the recommended
+// navaid. Localizers belong to airports, so a localizer approach to a heliport
+// uses the localizer of a nearby airport. Localizer IDs are not unique, so
+// only heliports with the same ICAO code as the airport a of the localizer and
+// within heliportMaxLocDistance of it are searched, in order of their
+// identifiers. If there is no such approach, nil is returned.
+func (p *processor) heliportApproachForLoc(a *airportData, loc *arinc.AirportLocGSPrimaryRecord) (*airportData, string, *locApchData) {
+	if a.Position == nil {
+		return nil, "", nil
+	}
+	var ids []string
+	for id, h := range p.Airports {
+		if h.Heliport && h.ICAOCode == a.ICAOCode && h.Position != nil && h.Position.GreatCircleDistance(a.Position) <= heliportMaxLocDistance {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		h := p.Airports[id]
+		if apchID, apch := h.ApproachForLoc(loc.LocalizerID); apch != nil {
+			return h, apchID, apch
+		}
+	}
+	return nil, "", nil
+}
+
+// findFix returns the position of the fix with the given identifier. Runways,
+// helipads, and terminal waypoints at the airport take precedence over other
+// waypoints. If the fix is not found, nil is returned.
+func (p *processor) findFix(a *airportData, id string) *geo.Point {
+	if id == "" {
+		return nil
+	}
+	if pt, ok := a.Runways[id]; ok {
+		return pt
+	}
+	if pt, ok := a.Helipads[id]; ok {
+		return pt
+	}
+	if pt, ok := a.Waypoints[id]; ok {
+		return pt
 	}
-	return contRecord, nil
+	return p.OtherWaypoints[id]
 }
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	geo "github.com/kellydunn/golang-geo"
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
 )
 
 type badReadSeeker struct {
@@ -101,7 +104,7 @@ func TestPreProcess(t *testing.T) {
 		},
 		{
 			name:      "LocalizerNotDuplicate",
-			processor: newProcessor(),
+			processor: newProcessor(RemoveDuplicateLocalizers(true)),
 			record:    "SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212",
 			wantProcessor: &processor{
 				Airports:       map[string]*airportData{},
@@ -109,6 +112,7 @@ func TestPreProcess(t *testing.T) {
 				DuplicateLocalizers: map[string]bool{
 					"IHWD": false,
 				},
+				RemoveDuplicateLocalizers: true,
 			},
 		},
 		{
@@ -119,6 +123,7 @@ func TestPreProcess(t *testing.T) {
 				DuplicateLocalizers: map[string]bool{
 					"IBUR": false,
 				},
+				RemoveDuplicateLocalizers: true,
 			},
 			record: "SUSAP KVNYK2IIBURA   010950RW34LN34115264W1182220920789                   1007+    0500   E0120                            296871905",
 			wantProcessor: &processor{
@@ -127,6 +132,51 @@ func TestPreProcess(t *testing.T) {
 				DuplicateLocalizers: map[string]bool{
 					"IBUR": true,
 				},
+				RemoveDuplicateLocalizers: true,
+			},
+		},
+		{
+			name:      "LocalizerNotRemovingDuplicates",
+			processor: newProcessor(),
+			record:    "SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212",
+			wantProcessor: &processor{
+				Airports:            map[string]*airportData{},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+			},
+		},
+		{
+			name:      "TerminalNDB",
+			processor: newProcessor(),
+			record:    "SUSAP KHWDK2NHW    K2000362H  W N37000000W121000000                       E0132           NARFERNE                         108052002",
+			wantProcessor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Waypoints: map[string]*geo.Point{
+							"HW": geo.NewPoint(37, -121),
+						},
+						Runways:    map[string]*geo.Point{},
+						Approaches: map[string]*locApchData{},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+			},
+		},
+		{
+			name:      "TerminalNDBBadLatLon",
+			processor: newProcessor(),
+			record:    "SUSAP KHWDK2NHW    K2000362H  W NBAD00000W121000000                       E0132           NARFERNE                         108052002",
+			wantErr:   true,
+		},
+		{
+			name:      "AirportWaypointNotParsed",
+			processor: newProcessor(),
+			record:    "SUSAP KHWDK2CSUDGE K20    W     NBAD00000W121000000                       E0132     NAR           SUDGE                    108112002",
+			wantProcessor: &processor{
+				Airports:            map[string]*airportData{},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
 			},
 		},
 	} {
@@ -234,14 +284,93 @@ func TestProcessRecord(t *testing.T) {
 				Airports: map[string]*airportData{
 					"KHWD": &airportData{
 						Waypoints:  map[string]*geo.Point{},
+						Runways:    map[string]*geo.Point{},
 						Approaches: map[string]*locApchData{},
-						MagVar:     -15.0,
+						MagVar:     arinc.MagVar{Value: -15.0},
+						ICAOCode:   "K2",
+						Position:   geo.NewPoint(37+39.0/60+32.14/3600, -(122 + 7.0/60 + 18.25/3600)),
 					},
 				},
 				OtherWaypoints:      map[string]*geo.Point{},
 				DuplicateLocalizers: map[string]bool{},
 			},
 		},
+		{
+			name:      "NewHeliport",
+			processor: newProcessor(),
+			record:    "SUSAH CA52K2A        0          N37393214W122071825E015000052         1800018000C    MNAR    HAYWARD HOSPITAL              107981608",
+			want:      "SUSAH CA52K2A        0          N37393214W122071825E015000052         1800018000C    MNAR    HAYWARD HOSPITAL              107981608\n",
+			wantProcessor: &processor{
+				Airports: map[string]*airportData{
+					"CA52": &airportData{
+						Heliport:   true,
+						Waypoints:  map[string]*geo.Point{},
+						Runways:    map[string]*geo.Point{},
+						Helipads:   map[string]*geo.Point{},
+						Approaches: map[string]*locApchData{},
+						MagVar:     arinc.MagVar{Value: -15.0},
+						ICAOCode:   "K2",
+						Position:   geo.NewPoint(37+39.0/60+32.14/3600, -(122 + 7.0/60 + 18.25/3600)),
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+			},
+		},
+		{
+			name:      "NewHeliportBadRefPoint",
+			processor: newProcessor(),
+			record:    "SUSAH CA52K2A        0          NBAD93214W122071825E015000052         1800018000C    MNAR    HAYWARD HOSPITAL              107981608",
+			wantErr:   true,
+		},
+		{
+			name:      "HeliportWaypoint",
+			processor: newProcessor(),
+			record:    "SUSAH CA52K2CHW    K20    W     N37354475W121595747                       E0132     NAR           HW                       108112002",
+			want:      "SUSAH CA52K2CHW    K20    W     N37354475W121595747                       E0132     NAR           HW                       108112002\n",
+			wantProcessor: &processor{
+				Airports: map[string]*airportData{
+					"CA52": &airportData{
+						Heliport: true,
+						Waypoints: map[string]*geo.Point{
+							"HW": geo.NewPoint(37+35.0/60+44.75/3600, -(121 + 59.0/60 + 57.47/3600)),
+						},
+						Runways:    map[string]*geo.Point{},
+						Helipads:   map[string]*geo.Point{},
+						Approaches: map[string]*locApchData{},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+			},
+		},
+		{
+			name:      "Helipad",
+			processor: newProcessor(),
+			record:    "SUSAH CA52K2HH1      0          N37380000W122030000                                                                        123452002",
+			want:      "SUSAH CA52K2HH1      0          N37380000W122030000                                                                        123452002\n",
+			wantProcessor: &processor{
+				Airports: map[string]*airportData{
+					"CA52": &airportData{
+						Heliport:  true,
+						Waypoints: map[string]*geo.Point{},
+						Runways:   map[string]*geo.Point{},
+						Helipads: map[string]*geo.Point{
+							"H1": geo.NewPoint(37+38.0/60, -(122 + 3.0/60)),
+						},
+						Approaches: map[string]*locApchData{},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+			},
+		},
+		{
+			name:      "HelipadBadLatLon",
+			processor: newProcessor(),
+			record:    "SUSAH CA52K2HH1      0          NBAD00000W122030000                                                                        123452002",
+			wantErr:   true,
+		},
 		{
 			name: "TerminalWaypoint",
 			processor: &processor{
@@ -284,6 +413,92 @@ func TestProcessRecord(t *testing.T) {
 			record:  "SUSAP KHWDK2CSUDGE K20    W     NBAD00000W121000000                       E0132     NAR           SUDGE                    108112002",
 			wantErr: true,
 		},
+		{
+			name:      "TerminalNDB",
+			processor: newProcessor(),
+			record:    "SUSAP KHWDK2NHW    K2000362H  W N37000000W121000000                       E0132           NARFERNE                         108052002",
+			want:      "SUSAP KHWDK2NHW    K2000362H  W N37000000W121000000                       E0132           NARFERNE                         108052002\n",
+			wantProcessor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Waypoints: map[string]*geo.Point{
+							"HW": geo.NewPoint(37, -121),
+						},
+						Runways:    map[string]*geo.Point{},
+						Approaches: map[string]*locApchData{},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+			},
+		},
+		{
+			name:      "TerminalNDBBadLatLon",
+			processor: newProcessor(),
+			record:    "SUSAP KHWDK2NHW    K2000362H  W NBAD00000W121000000                       E0132           NARFERNE                         108052002",
+			wantErr:   true,
+		},
+		{
+			name:      "LocatorOuterMarker",
+			processor: newProcessor(),
+			record:    "SUSAP KHWDK2MIHWDOM  000362RW28LN37000000W1210000002880N38000000W122000000H  W      HW    E0132  00010                     108062002",
+			want:      "SUSAP KHWDK2MIHWDOM  000362RW28LN37000000W1210000002880N38000000W122000000H  W      HW    E0132  00010                     108062002\n",
+			wantProcessor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Waypoints: map[string]*geo.Point{
+							"HW": geo.NewPoint(38, -122),
+						},
+						Runways:    map[string]*geo.Point{},
+						Approaches: map[string]*locApchData{},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+			},
+		},
+		{
+			name:      "LocatorWithoutPositionUsesMarker",
+			processor: newProcessor(),
+			record:    "SUSAP KHWDK2MIHWDOM  000362RW28LN37000000W1210000002880                   H  W      HW    E0132  00010                     108062002",
+			want:      "SUSAP KHWDK2MIHWDOM  000362RW28LN37000000W1210000002880                   H  W      HW    E0132  00010                     108062002\n",
+			wantProcessor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Waypoints: map[string]*geo.Point{
+							"HW": geo.NewPoint(37, -121),
+						},
+						Runways:    map[string]*geo.Point{},
+						Approaches: map[string]*locApchData{},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+			},
+		},
+		{
+			name:      "MarkerWithoutLocator",
+			processor: newProcessor(),
+			record:    "SUSAP KHWDK2MIHWDMM  0     RW28LN37000000W1210000002880                                   E0132  00010                     108072002",
+			want:      "SUSAP KHWDK2MIHWDMM  0     RW28LN37000000W1210000002880                                   E0132  00010                     108072002\n",
+			wantProcessor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Waypoints:  map[string]*geo.Point{},
+						Runways:    map[string]*geo.Point{},
+						Approaches: map[string]*locApchData{},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+			},
+		},
+		{
+			name:      "LocatorOuterMarkerBadLatLon",
+			processor: newProcessor(),
+			record:    "SUSAP KHWDK2MIHWDOM  000362RW28LN37000000W1210000002880N38000000WBAD000000H  W      HW    E0132  00010                     108062002",
+			wantErr:   true,
+		},
 		{
 			name: "ApproachProcedureLocNotFAF",
 			processor: &processor{
@@ -348,13 +563,10 @@ func TestProcessRecord(t *testing.T) {
 			},
 		},
 		{
-			name: "Localizer",
+			name: "ApproachProcedureLocMAP",
 			processor: &processor{
 				Airports: map[string]*airportData{
 					"KHWD": &airportData{
-						Waypoints: map[string]*geo.Point{
-							"FERNE": geo.NewPoint(37.59, -121.99),
-						},
 						Approaches: map[string]*locApchData{
 							"L28L": &locApchData{
 								FinalApproachFix: "FERNE",
@@ -366,18 +578,16 @@ func TestProcessRecord(t *testing.T) {
 				OtherWaypoints:      map[string]*geo.Point{},
 				DuplicateLocalizers: map[string]bool{},
 			},
-			record: "SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212",
-			want:   "SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212\nSUSAP KHWDK2IIHWD0   2S                            30341N                                                                  108901212\n",
+			record: "SUSAP KHWDK2FL28L  L      030RW28LK2PG0GY M    CF IHWDK2      1079000828800040PI    00105             -344          0 DS   108541212",
+			want:   "SUSAP KHWDK2FL28L  L      030RW28LK2PG0GY M    CF IHWDK2      1079000828800040PI    00105             -344          0 DS   108541212\n",
 			wantProcessor: &processor{
 				Airports: map[string]*airportData{
 					"KHWD": &airportData{
-						Waypoints: map[string]*geo.Point{
-							"FERNE": geo.NewPoint(37.59, -121.99),
-						},
 						Approaches: map[string]*locApchData{
 							"L28L": &locApchData{
-								FinalApproachFix: "FERNE",
-								LocalizerID:      "IHWD",
+								FinalApproachFix:    "FERNE",
+								MissedApproachPoint: "RW28L",
+								LocalizerID:         "IHWD",
 							},
 						},
 					},
@@ -387,70 +597,49 @@ func TestProcessRecord(t *testing.T) {
 			},
 		},
 		{
-			name: "SkipDuplicateLocalizer",
+			name: "LocalizerFallbackToMissedApproachPoint",
 			processor: &processor{
 				Airports: map[string]*airportData{
-					"KVNY": &airportData{
-						Waypoints: map[string]*geo.Point{
-							"SILEX": geo.NewPoint(34.20, -118.61),
+					"KHWD": &airportData{
+						Runways: map[string]*geo.Point{
+							"RW28L": geo.NewPoint(37.65518333, -122.11475833),
 						},
 						Approaches: map[string]*locApchData{
-							"LDA-C": &locApchData{
-								FinalApproachFix: "SILEX",
-								LocalizerID:      "IBUR",
+							"L28L": &locApchData{
+								FinalApproachFix:    "FERNE",
+								MissedApproachPoint: "RW28L",
+								LocalizerID:         "IHWD",
 							},
 						},
 					},
 				},
-				OtherWaypoints: map[string]*geo.Point{},
-				DuplicateLocalizers: map[string]bool{
-					"IBUR": true,
-				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
 			},
-			record: "SUSAP KVNYK2IIBURA   010950RW34LN34115264W1182220920789                   1007+    0500   E0120                            296871905\n",
-			want:   "",
+			record: "SUSAP KHWDK2IIHWD0   011150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212",
+			want:   "SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212\nSUSAP KHWDK2IIHWD0   2S                            30287M                                                                  108901212\n",
 			wantProcessor: &processor{
 				Airports: map[string]*airportData{
-					"KVNY": &airportData{
-						Waypoints: map[string]*geo.Point{
-							"SILEX": geo.NewPoint(34.20, -118.61),
+					"KHWD": &airportData{
+						Runways: map[string]*geo.Point{
+							"RW28L": geo.NewPoint(37.65518333, -122.11475833),
 						},
 						Approaches: map[string]*locApchData{
-							"LDA-C": &locApchData{
-								FinalApproachFix: "SILEX",
-								LocalizerID:      "IBUR",
+							"L28L": &locApchData{
+								FinalApproachFix:    "FERNE",
+								MissedApproachPoint: "RW28L",
+								LocalizerID:         "IHWD",
 							},
 						},
 					},
 				},
-				OtherWaypoints: map[string]*geo.Point{},
-				DuplicateLocalizers: map[string]bool{
-					"IBUR": true,
-				},
-			},
-		},
-		{
-			name: "SkipLocalizerNoApproach",
-			processor: &processor{
-				Airports:            map[string]*airportData{},
-				OtherWaypoints:      map[string]*geo.Point{},
-				DuplicateLocalizers: map[string]bool{},
-			},
-			record: "SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212",
-			want:   "SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212\n",
-			wantProcessor: &processor{
-				Airports: map[string]*airportData{
-					"KHWD": &airportData{
-						Waypoints:  map[string]*geo.Point{},
-						Approaches: map[string]*locApchData{},
-					},
-				},
 				OtherWaypoints:      map[string]*geo.Point{},
 				DuplicateLocalizers: map[string]bool{},
+				Deviations:          DeviationCounts{Checked: 1, Kept: 1},
 			},
 		},
 		{
-			name: "SkipLocalizerBadLatLon",
+			name: "Localizer",
 			processor: &processor{
 				Airports: map[string]*airportData{
 					"KHWD": &airportData{
@@ -468,8 +657,8 @@ func TestProcessRecord(t *testing.T) {
 				OtherWaypoints:      map[string]*geo.Point{},
 				DuplicateLocalizers: map[string]bool{},
 			},
-			record: "SUSAP KHWDK2IIHWD0   111150RW28LNBAD94620W1220746752879                   0109     0500   E0150                            108901212",
-			want:   "SUSAP KHWDK2IIHWD0   111150RW28LNBAD94620W1220746752879                   0109     0500   E0150                            108901212\n",
+			record: "SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212",
+			want:   "SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212\nSUSAP KHWDK2IIHWD0   2S                            30330F                                                                  108901212\n",
 			wantProcessor: &processor{
 				Airports: map[string]*airportData{
 					"KHWD": &airportData{
@@ -486,10 +675,11 @@ func TestProcessRecord(t *testing.T) {
 				},
 				OtherWaypoints:      map[string]*geo.Point{},
 				DuplicateLocalizers: map[string]bool{},
+				Deviations:          DeviationCounts{Checked: 1, Kept: 1},
 			},
 		},
 		{
-			name: "DontSkipDuplicateLocalizer",
+			name: "SkipDuplicateLocalizer",
 			processor: &processor{
 				Airports: map[string]*airportData{
 					"KVNY": &airportData{
@@ -504,11 +694,13 @@ func TestProcessRecord(t *testing.T) {
 						},
 					},
 				},
-				OtherWaypoints:      map[string]*geo.Point{},
-				DuplicateLocalizers: map[string]bool{},
+				OtherWaypoints: map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{
+					"IBUR": true,
+				},
 			},
 			record: "SUSAP KVNYK2IIBURA   010950RW34LN34115264W1182220920789                   1007+    0500   E0120                            296871905\n",
-			want:   "SUSAP KVNYK2IIBURA   110950RW34LN34115264W1182220920789                   1007+    0500   E0120                            296871905\nSUSAP KVNYK2IIBURA   2S                            09053N                                                                  296871905\n",
+			want:   "",
 			wantProcessor: &processor{
 				Airports: map[string]*airportData{
 					"KVNY": &airportData{
@@ -523,8 +715,10 @@ func TestProcessRecord(t *testing.T) {
 						},
 					},
 				},
-				OtherWaypoints:      map[string]*geo.Point{},
-				DuplicateLocalizers: map[string]bool{},
+				OtherWaypoints: map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{
+					"IBUR": true,
+				},
 			},
 		},
 		{
@@ -540,6 +734,7 @@ func TestProcessRecord(t *testing.T) {
 				Airports: map[string]*airportData{
 					"KHWD": &airportData{
 						Waypoints:  map[string]*geo.Point{},
+						Runways:    map[string]*geo.Point{},
 						Approaches: map[string]*locApchData{},
 					},
 				},
@@ -548,17 +743,23 @@ func TestProcessRecord(t *testing.T) {
 			},
 		},
 		{
-			name: "SkipLocalizerBadLatLon",
+			name:      "LocalizerBadLatLon",
+			processor: newProcessor(),
+			record:    "SUSAP KHWDK2IIHWD0   111150RW28LNBAD94620W1220746752879                   0109     0500   E0150                            108901212",
+			wantErr:   true,
+		},
+		{
+			name: "DontSkipDuplicateLocalizer",
 			processor: &processor{
 				Airports: map[string]*airportData{
-					"KHWD": &airportData{
+					"KVNY": &airportData{
 						Waypoints: map[string]*geo.Point{
-							"FERNE": geo.NewPoint(37.59, -121.99),
+							"SILEX": geo.NewPoint(34.20, -118.61),
 						},
 						Approaches: map[string]*locApchData{
-							"L28L": &locApchData{
-								FinalApproachFix: "FERNE",
-								LocalizerID:      "IHWD",
+							"LDA-C": &locApchData{
+								FinalApproachFix: "SILEX",
+								LocalizerID:      "IBUR",
 							},
 						},
 					},
@@ -566,24 +767,46 @@ func TestProcessRecord(t *testing.T) {
 				OtherWaypoints:      map[string]*geo.Point{},
 				DuplicateLocalizers: map[string]bool{},
 			},
-			record: "SUSAP KHWDK2IIHWD0   111150RW28LNBAD94620W1220746752879                   0109     0500   E0150                            108901212",
-			want:   "SUSAP KHWDK2IIHWD0   111150RW28LNBAD94620W1220746752879                   0109     0500   E0150                            108901212\n",
+			record: "SUSAP KVNYK2IIBURA   010950RW34LN34115264W1182220920789                   1007+    0500   E0120                            296871905\n",
+			want:   "SUSAP KVNYK2IIBURA   110950RW34LN34115264W1182220920789                   1007+    0500   E0120                            296871905\nSUSAP KVNYK2IIBURA   2S                            09053F                                                                  296871905\n",
 			wantProcessor: &processor{
 				Airports: map[string]*airportData{
-					"KHWD": &airportData{
+					"KVNY": &airportData{
 						Waypoints: map[string]*geo.Point{
-							"FERNE": geo.NewPoint(37.59, -121.99),
+							"SILEX": geo.NewPoint(34.20, -118.61),
 						},
 						Approaches: map[string]*locApchData{
-							"L28L": &locApchData{
-								FinalApproachFix: "FERNE",
-								LocalizerID:      "IHWD",
+							"LDA-C": &locApchData{
+								FinalApproachFix: "SILEX",
+								LocalizerID:      "IBUR",
 							},
 						},
 					},
 				},
 				OtherWaypoints:      map[string]*geo.Point{},
 				DuplicateLocalizers: map[string]bool{},
+				Deviations:          DeviationCounts{Checked: 1, Kept: 1},
+			},
+		},
+		{
+			name: "SkipLocalizerNoApproach",
+			processor: &processor{
+				Airports:            map[string]*airportData{},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+			},
+			record: "SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212",
+			want:   "SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212\n",
+			wantProcessor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Waypoints:  map[string]*geo.Point{},
+						Runways:    map[string]*geo.Point{},
+						Approaches: map[string]*locApchData{},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
 			},
 		},
 		{
@@ -605,7 +828,7 @@ func TestProcessRecord(t *testing.T) {
 				DuplicateLocalizers: map[string]bool{},
 			},
 			record: "SUSAP KSACK2IISAC1   111030RW02 N38311332W1212917310191N38302558W1212950951089 10860600300E01405700020                     973081402",
-			want:   "SUSAP KSACK2IISAC1   111030RW02 N38311332W1212917310191N38302558W1212950951089 10860600300E01405700020                     973081402\nSUSAP KSACK2IISAC1   2S                            03105N                                                                  973081402\n",
+			want:   "SUSAP KSACK2IISAC1   111030RW02 N38311332W1212917310191N38302558W1212950951089 10860600300E01405700020                     973081402\nSUSAP KSACK2IISAC1   2S                            03116F                                                                  973081402\n",
 			wantProcessor: &processor{
 				Airports: map[string]*airportData{
 					"KSAC": &airportData{
@@ -621,6 +844,7 @@ func TestProcessRecord(t *testing.T) {
 					"SAC": geo.NewPoint(38.44, -121.55),
 				},
 				DuplicateLocalizers: map[string]bool{},
+				Deviations:          DeviationCounts{Checked: 1, Kept: 1},
 			},
 		},
 
@@ -657,6 +881,138 @@ func TestProcessRecord(t *testing.T) {
 				DuplicateLocalizers: map[string]bool{},
 			},
 		},
+		{
+			name: "Runway",
+			processor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Waypoints:  map[string]*geo.Point{},
+						Runways:    map[string]*geo.Point{},
+						Approaches: map[string]*locApchData{},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+			},
+			record: "SUSAP KHWDK2GRW28L   0056942840 N37000000W122000000         -0017200050067635150RIHWD0                                     108881707",
+			want:   "SUSAP KHWDK2GRW28L   0056942840 N37000000W122000000         -0017200050067635150RIHWD0                                     108881707\n",
+			wantProcessor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Waypoints: map[string]*geo.Point{},
+						Runways: map[string]*geo.Point{
+							"RW28L": geo.NewPoint(37, -122),
+						},
+						Approaches: map[string]*locApchData{},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+			},
+		},
+		{
+			name: "RunwayBadLatLon",
+			processor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Waypoints:  map[string]*geo.Point{},
+						Runways:    map[string]*geo.Point{},
+						Approaches: map[string]*locApchData{},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+			},
+			record:  "SUSAP KHWDK2GRW28L   0056942840 NBAD00000W122000000         -0017200050067635150RIHWD0                                     108881707",
+			wantErr: true,
+		},
+		{
+			name: "LocalizerRunwayThreshold",
+			processor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Runways: map[string]*geo.Point{
+							"RW28L": geo.NewPoint(37.65518333, -122.11475833),
+							"RW10R": geo.NewPoint(37.66093056, -122.12734722),
+						},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+				Estimators:          []BearingEstimator{RunwayThresholdEstimator{}},
+			},
+			record: "SUSAP KHWDK2IIHWD0   011150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212",
+			want:   "SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212\nSUSAP KHWDK2IIHWD0   2S                            29987R                                                                  108901212\n",
+			wantProcessor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Runways: map[string]*geo.Point{
+							"RW28L": geo.NewPoint(37.65518333, -122.11475833),
+							"RW10R": geo.NewPoint(37.66093056, -122.12734722),
+						},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+				Deviations:          DeviationCounts{Checked: 1, Kept: 1},
+				Estimators:          []BearingEstimator{RunwayThresholdEstimator{}},
+			},
+		},
+		{
+			name: "LocalizerRunwayThresholdNoOpposite",
+			processor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Runways: map[string]*geo.Point{
+							"RW28L": geo.NewPoint(37.65518333, -122.11475833),
+						},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+				Estimators:          []BearingEstimator{RunwayThresholdEstimator{}},
+			},
+			record: "SUSAP KHWDK2IIHWD0   011150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212",
+			want:   "SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212\nSUSAP KHWDK2IIHWD0   2S                            30287R                                                                  108901212\n",
+			wantProcessor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Runways: map[string]*geo.Point{
+							"RW28L": geo.NewPoint(37.65518333, -122.11475833),
+						},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+				Deviations:          DeviationCounts{Checked: 1, Kept: 1},
+				Estimators:          []BearingEstimator{RunwayThresholdEstimator{}},
+			},
+		},
+		{
+			name: "SkipLocalizerNoRunway",
+			processor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Runways: map[string]*geo.Point{},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+				Estimators:          []BearingEstimator{RunwayThresholdEstimator{}},
+			},
+			record: "SUSAP KHWDK2IIHWD0   011150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212",
+			want:   "SUSAP KHWDK2IIHWD0   011150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212\n",
+			wantProcessor: &processor{
+				Airports: map[string]*airportData{
+					"KHWD": &airportData{
+						Runways: map[string]*geo.Point{},
+					},
+				},
+				OtherWaypoints:      map[string]*geo.Point{},
+				DuplicateLocalizers: map[string]bool{},
+				Estimators:          []BearingEstimator{RunwayThresholdEstimator{}},
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := tt.processor.processRecord([]byte(tt.record))
@@ -696,6 +1052,12 @@ func TestProcessCompleteFile(t *testing.T) {
 			inFile:      "test_data.txt",
 			wantOutFile: "test_data_out.txt",
 		},
+		{
+			name:        "RunwayThreshold",
+			inFile:      "test_data.txt",
+			options:     []Option{BearingEstimators(RunwayThresholdEstimator{})},
+			wantOutFile: "test_data_runway_out.txt",
+		},
 		{
 			name:        "LocDuplicatesDoNotRemove",
 			inFile:      "test_data_locduplicates.txt",
@@ -729,3 +1091,151 @@ func TestProcessCompleteFile(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessTerminalNavaidFAF(t *testing.T) {
+	// The final approach fix of the approach is a compass locator, whose
+	// record comes after the localizer in the data.
+	head := []string{
+		"SUSAP KHWDK2AHWD     0     056YHN37393214W122071825E015000052         1800018000C    MNAR    HAYWARD EXECUTIVE             107981608",
+		"SUSAP KHWDK2FL28L  L      020HW   K2PN0E  F    CF IHWDK2      1079007428800053PI  + 02500                 OAK   K2D 0 DS   108521310",
+	}
+	loc := "SUSAP KHWDK2IIHWD0   011150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212"
+	wantLoc := []string{
+		"SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212",
+		"SUSAP KHWDK2IIHWD0   2S                            30294F                                                                  108901212",
+	}
+	for _, tt := range []struct {
+		name    string
+		locator string
+	}{
+		{
+			name:    "TerminalNDB",
+			locator: "SUSAP KHWDK2NHW    K2000362H  W N37354475W121595747                       E0132           NARFERNE                         108052002",
+		},
+		{
+			name:    "LocatorOuterMarker",
+			locator: "SUSAP KHWDK2MIHWDOM  000362RW28LN37354475W1215957472880                   H  W      HW    E0132  00010                     108062002",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			in := strings.Join(append(head, loc, tt.locator), "\n")
+			want := strings.Join(append(append(head, wantLoc...), tt.locator), "\n") + "\n"
+			var got bytes.Buffer
+			if err := Process(strings.NewReader(in), &got); err != nil {
+				t.Fatalf("Process() = %v want <nil>", err)
+			}
+			if diff := cmp.Diff(want, got.String()); diff != "" {
+				t.Errorf("Process() out content not as expected (-want +got): %s", diff)
+			}
+			got.Reset()
+			if err := ProcessStream(strings.NewReader(in), &got); err != nil {
+				t.Fatalf("ProcessStream() = %v want <nil>", err)
+			}
+			if diff := cmp.Diff(want, got.String()); diff != "" {
+				t.Errorf("ProcessStream() out content not as expected (-want +got): %s", diff)
+			}
+			got.Reset()
+			gz := gzipData(t, []byte(in))
+			if err := ProcessArchive(bytes.NewReader(gz), int64(len(gz)), &got); err != nil {
+				t.Fatalf("ProcessArchive() = %v want <nil>", err)
+			}
+			if diff := cmp.Diff(want, got.String()); diff != "" {
+				t.Errorf("ProcessArchive() out content not as expected (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestProcessHeliportLocApproach(t *testing.T) {
+	// The localizer approach is to a heliport, and its final approach fix is
+	// a heliport waypoint. The localizer belongs to a nearby airport, which
+	// has no approach that uses it.
+	tail := []string{
+		"SUSAH CA52K2CHW    K20    W     N37354475W121595747                       E0132     NAR           HW                       108112002",
+		"SUSAH CA52K2FL28L  L      020HW   K2HC0E  F    CF IHWDK2      1079007428800053PI  + 02500                 OAK   K2D 0 DS   108521310",
+		"SUSAP KHWDK2AHWD     0     056YHN37393214W122071825E015000052         1800018000C    MNAR    HAYWARD EXECUTIVE             107981608",
+	}
+	loc := "SUSAP KHWDK2IIHWD0   011150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212"
+	enhancedLoc := []string{
+		"SUSAP KHWDK2IIHWD0   111150RW28LN37394620W1220746752879                   0109     0500   E0150                            108901212",
+		"SUSAP KHWDK2IIHWD0   2S                            30294F                                                                  108901212",
+	}
+	for _, tt := range []struct {
+		name     string
+		heliport string
+		wantLoc  []string
+	}{
+		{
+			name:     "Nearby",
+			heliport: "SUSAH CA52K2A        0          N37393214W122071825E015000052         1800018000C    MNAR    HAYWARD HOSPITAL              107981608",
+			wantLoc:  enhancedLoc,
+		},
+		{
+			name:     "OtherICAOCode",
+			heliport: "SUSAH CA52K1A        0          N37393214W122071825E015000052         1800018000C    MNAR    HAYWARD HOSPITAL              107981608",
+			wantLoc:  []string{loc},
+		},
+		{
+			name:     "TooFar",
+			heliport: "SUSAH CA52K2A        0          N38393214W122071825E015000052         1800018000C    MNAR    HAYWARD HOSPITAL              107981608",
+			wantLoc:  []string{loc},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			head := append([]string{tt.heliport}, tail...)
+			in := strings.Join(append(head, loc), "\n")
+			want := strings.Join(append(head, tt.wantLoc...), "\n") + "\n"
+			var got bytes.Buffer
+			if err := Process(strings.NewReader(in), &got); err != nil {
+				t.Fatalf("Process() = %v want <nil>", err)
+			}
+			if diff := cmp.Diff(want, got.String()); diff != "" {
+				t.Errorf("Process() out content not as expected (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestEarthModelBearing(t *testing.T) {
+	const tolerance = 0.00001
+	from := geo.NewPoint(37.59, -121.99)
+	to := geo.NewPoint(37.66283333, -122.12965278)
+	for _, tt := range []struct {
+		name    string
+		model   EarthModel
+		want    float64
+		wantErr bool
+	}{
+		{
+			name:  "WGS84",
+			model: EarthModelWGS84,
+			want:  303.29629,
+		},
+		{
+			name:  "Sphere",
+			model: EarthModelSphere,
+			want:  303.40722,
+		},
+		{
+			name:    "Unknown",
+			model:   EarthModel(-1),
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.model.Bearing(from, to)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Bearing() = _, <nil> want _, <non-nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Bearing() = _, %v want _, <nil>", err)
+			}
+			if math.Abs(got-tt.want) > tolerance {
+				t.Errorf("Bearing() = %.5f want %.5f", got, tt.want)
+			}
+		})
+	}
+}
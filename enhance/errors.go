@@ -0,0 +1,168 @@
+package enhance
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	fixedwidth "github.com/ianlopshire/go-fixedwidth"
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+)
+
+// RecordError is returned when a record in the input data cannot be
+// processed. It identifies the record so that it can be found in the data.
+type RecordError struct {
+	// Line is the 1-based line number of the record in the input data.
+	Line int
+	// FileRecordNumber is the file record number of the record, or empty if
+	// it could not be parsed.
+	FileRecordNumber string
+	// SectionCode and SubsectionCode are the section and subsection of the
+	// record, or empty if they could not be parsed.
+	SectionCode    string
+	SubsectionCode string
+	// Field is the name of the field in the arinc package record type that
+	// has an invalid value, or empty if the error is not specific to a field.
+	Field string
+	// Record is the raw record.
+	Record string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements error.
+func (e *RecordError) Error() string {
+	details := []string{}
+	if e.FileRecordNumber != "" {
+		details = append(details, fmt.Sprintf("file record %s", e.FileRecordNumber))
+	}
+	if e.SectionCode != "" {
+		details = append(details, fmt.Sprintf("section %s%s", e.SectionCode, e.SubsectionCode))
+	}
+	if e.Field != "" {
+		details = append(details, fmt.Sprintf("field %s", e.Field))
+	}
+	var detail string
+	if len(details) > 0 {
+		detail = fmt.Sprintf(" (%s)", strings.Join(details, ", "))
+	}
+	return fmt.Sprintf("could not process record on line %d%s: %v", e.Line, detail, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// RecordErrors is returned when ContinueOnError is enabled and one or more
+// records could not be processed.
+type RecordErrors struct {
+	// Errors lists the errors in the order that the records appear in the
+	// input data.
+	Errors []*RecordError
+	// Aborted is true if processing was stopped because there were more
+	// errors than the maximum.
+	Aborted bool
+}
+
+// maxErrorsInMessage is the maximum number of errors that are listed in the
+// message of a RecordErrors.
+const maxErrorsInMessage = 10
+
+// Error implements error.
+func (e *RecordErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d records could not be processed", len(e.Errors))
+	if e.Aborted {
+		b.WriteString(", giving up")
+	}
+	for i, err := range e.Errors {
+		if i == maxErrorsInMessage {
+			fmt.Fprintf(&b, "; and %d more", len(e.Errors)-i)
+			break
+		}
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// As finds the first error in Errors that matches target, so that errors.As
+// can find a *RecordError (or the errors it wraps) in a RecordErrors.
+func (e *RecordErrors) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContinueOnError is an option that passes records that cannot be processed
+// through to the output unchanged instead of stopping. Once all of the data
+// is processed, a *RecordErrors that lists every such record is returned. If
+// more than maxErrors records cannot be processed, processing stops early.
+// A maxErrors of zero or less allows any number of errors.
+func ContinueOnError(maxErrors int) Option {
+	return func(p *processor) {
+		p.ContinueOnError = true
+		p.MaxErrors = maxErrors
+	}
+}
+
+// recordFailed handles an error processing the record on the given line. If
+// ContinueOnError is enabled, the error is collected and the record is
+// returned unchanged so that it can be written to the output. Otherwise, or if
+// there are too many errors, an error is returned.
+func (p *processor) recordFailed(line int, recordBytes []byte, err error) ([]byte, error) {
+	recErr := newRecordError(line, recordBytes, err)
+	if !p.ContinueOnError {
+		return nil, recErr
+	}
+	p.Errors = append(p.Errors, recErr)
+	if p.MaxErrors > 0 && len(p.Errors) > p.MaxErrors {
+		return nil, &RecordErrors{Errors: p.Errors, Aborted: true}
+	}
+	log.Printf("Passing through record that could not be processed: %v", recErr)
+	out := make([]byte, 0, len(recordBytes)+1)
+	out = append(out, recordBytes...)
+	return append(out, '\n'), nil
+}
+
+// recordErrors returns the errors collected while processing, or nil if there
+// were none.
+func (p *processor) recordErrors() error {
+	if len(p.Errors) == 0 {
+		return nil
+	}
+	return &RecordErrors{Errors: p.Errors}
+}
+
+// newRecordError returns a RecordError for the record on the given line.
+func newRecordError(line int, recordBytes []byte, err error) *RecordError {
+	e := &RecordError{
+		Line:   line,
+		Record: string(recordBytes),
+		Err:    err,
+	}
+	r := arinc.Record{}
+	if fixedwidth.Unmarshal(recordBytes, &r) == nil {
+		e.FileRecordNumber = r.FileRecordNumber
+	}
+	if section, subsection, err := arinc.SectionAndSubsection(recordBytes); err == nil {
+		e.SectionCode = section
+		e.SubsectionCode = subsection
+	}
+	// The arinc package record types decode their fields while they are
+	// parsed, so a malformed field is reported by the decoder.
+	var te *fixedwidth.UnmarshalTypeError
+	if errors.As(err, &te) {
+		e.Field = te.Field
+	}
+	return e
+}
@@ -0,0 +1,277 @@
+package enhance
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	fixedwidth "github.com/ianlopshire/go-fixedwidth"
+)
+
+func TestRecordError(t *testing.T) {
+	const goodRecord = "SUSAP KHWDK2CSUDGE K20    W     N37000000W121000000                       E0132     NAR           SUDGE                    108112002"
+	for _, tt := range []struct {
+		name string
+		data []string
+		want *RecordError
+	}{
+		{
+			name: "AirportWaypointLatitude",
+			data: []string{
+				goodRecord,
+				"SUSAP KHWDK2CSUDGE K20    W     NBAD00000W121000000                       E0132     NAR           SUDGE                    108112002",
+			},
+			want: &RecordError{
+				Line:             2,
+				FileRecordNumber: "10811",
+				SectionCode:      "P",
+				SubsectionCode:   "C",
+				Field:            "Waypoint",
+				Record:           "SUSAP KHWDK2CSUDGE K20    W     NBAD00000W121000000                       E0132     NAR           SUDGE                    108112002",
+			},
+		},
+		{
+			name: "EnrouteWaypointLongitude",
+			data: []string{
+				"SUSAEAENRT   SUNOL K20    C  RL N37000000W12B000000                       E0132     NAR           SUNOL                    459212002",
+			},
+			want: &RecordError{
+				Line:             1,
+				FileRecordNumber: "45921",
+				SectionCode:      "E",
+				SubsectionCode:   "A",
+				Field:            "Waypoint",
+				Record:           "SUSAEAENRT   SUNOL K20    C  RL N37000000W12B000000                       E0132     NAR           SUNOL                    459212002",
+			},
+		},
+		{
+			name: "MagneticVariation",
+			data: []string{
+				goodRecord,
+				goodRecord,
+				"SUSAP KHWDK2AHWD     0     056YHN37393214W122071825X015000052         1800018000C    MNAR    HAYWARD EXECUTIVE             107981608",
+			},
+			want: &RecordError{
+				Line:             3,
+				FileRecordNumber: "10798",
+				SectionCode:      "P",
+				SubsectionCode:   "A",
+				Field:            "MagneticVar",
+				Record:           "SUSAP KHWDK2AHWD     0     056YHN37393214W122071825X015000052         1800018000C    MNAR    HAYWARD EXECUTIVE             107981608",
+			},
+		},
+	} {
+		in := strings.Join(tt.data, "\n")
+		for _, process := range []struct {
+			name string
+			fn   func() error
+		}{
+			{"Process", func() error { return Process(strings.NewReader(in), ioutil.Discard) }},
+			{"ProcessStream", func() error { return ProcessStream(strings.NewReader(in), ioutil.Discard) }},
+		} {
+			t.Run(tt.name+process.name, func(t *testing.T) {
+				err := process.fn()
+				var got *RecordError
+				if !errors.As(err, &got) {
+					t.Fatalf("%s() = %v want *RecordError", process.name, err)
+				}
+				if got.Err == nil {
+					t.Errorf("RecordError.Err = <nil> want <non-nil>")
+				}
+				gotNoErr := *got
+				gotNoErr.Err = nil
+				if diff := cmp.Diff(tt.want, &gotNoErr); diff != "" {
+					t.Errorf("%s() error had diffs (-want +got): %s", process.name, diff)
+				}
+			})
+		}
+	}
+}
+
+func TestRecordErrorMessage(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		err  *RecordError
+		want string
+	}{
+		{
+			name: "AllDetails",
+			err: &RecordError{
+				Line:             12,
+				FileRecordNumber: "10811",
+				SectionCode:      "P",
+				SubsectionCode:   "C",
+				Field:            "Waypoint",
+				Err:              errors.New("bad latitude"),
+			},
+			want: "could not process record on line 12 (file record 10811, section PC, field Waypoint): bad latitude",
+		},
+		{
+			name: "LineOnly",
+			err: &RecordError{
+				Line: 3,
+				Err:  errors.New("bad record"),
+			},
+			want: "could not process record on line 3: bad record",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q want %q", got, tt.want)
+			}
+			if got := errors.Unwrap(tt.err); got != tt.err.Err {
+				t.Errorf("Unwrap() = %v want %v", got, tt.err.Err)
+			}
+		})
+	}
+}
+
+func TestProcessNoRecordErrorOnWriteFailure(t *testing.T) {
+	in := bytes.NewReader([]byte("SUSAP KHWDK2CBOGRE K20    W     N37372195W122023769                       E0133     NAR           BOGRE                    107992002"))
+	err := Process(in, &badWriter{})
+	var recErr *RecordError
+	if errors.As(err, &recErr) {
+		t.Errorf("Process() = %v want an error that is not a *RecordError", err)
+	}
+}
+
+func TestContinueOnError(t *testing.T) {
+	const (
+		good = "SUSAP KHWDK2CBOGRE K20    W     N37372195W122023769                       E0133     NAR           BOGRE                    107992002"
+		bad  = "SUSAP KHWDK2CBOGRE K20    W     NBADDATA!W122023769                       E0133     NAR           BOGRE                    107992002"
+	)
+	in := strings.Join([]string{good, bad, good, bad, good}, "\n")
+	for _, tt := range []struct {
+		name        string
+		maxErrors   int
+		want        string
+		wantLines   []int
+		wantAborted bool
+	}{
+		{
+			name:      "Unlimited",
+			want:      strings.Join([]string{good, bad, good, bad, good}, "\n") + "\n",
+			wantLines: []int{2, 4},
+		},
+		{
+			name:      "AtLimit",
+			maxErrors: 2,
+			want:      strings.Join([]string{good, bad, good, bad, good}, "\n") + "\n",
+			wantLines: []int{2, 4},
+		},
+		{
+			name:        "OverLimit",
+			maxErrors:   1,
+			want:        strings.Join([]string{good, bad, good}, "\n") + "\n",
+			wantLines:   []int{2, 4},
+			wantAborted: true,
+		},
+	} {
+		for _, process := range []struct {
+			name string
+			fn   func(out *bytes.Buffer, opts ...Option) error
+		}{
+			{"Process", func(out *bytes.Buffer, opts ...Option) error {
+				return Process(strings.NewReader(in), out, opts...)
+			}},
+			{"ProcessStream", func(out *bytes.Buffer, opts ...Option) error {
+				return ProcessStream(strings.NewReader(in), out, opts...)
+			}},
+		} {
+			t.Run(tt.name+process.name, func(t *testing.T) {
+				var out bytes.Buffer
+				err := process.fn(&out, ContinueOnError(tt.maxErrors))
+				var got *RecordErrors
+				if !errors.As(err, &got) {
+					t.Fatalf("%s() = %v want *RecordErrors", process.name, err)
+				}
+				var gotLines []int
+				for _, e := range got.Errors {
+					gotLines = append(gotLines, e.Line)
+				}
+				if diff := cmp.Diff(tt.wantLines, gotLines); diff != "" {
+					t.Errorf("%s() error lines had diffs (-want +got): %s", process.name, diff)
+				}
+				if got.Aborted != tt.wantAborted {
+					t.Errorf("%s() Aborted = %t want %t", process.name, got.Aborted, tt.wantAborted)
+				}
+				var first *RecordError
+				if !errors.As(err, &first) {
+					t.Fatalf("%s() = %v want *RecordError", process.name, err)
+				}
+				if first.Line != tt.wantLines[0] {
+					t.Errorf("%s() first *RecordError line = %d want %d", process.name, first.Line, tt.wantLines[0])
+				}
+				if diff := cmp.Diff(tt.want, out.String()); diff != "" {
+					t.Errorf("%s() out content not as expected (-want +got): %s", process.name, diff)
+				}
+			})
+		}
+	}
+}
+
+func TestContinueOnErrorNoErrors(t *testing.T) {
+	in := strings.NewReader("SUSAP KHWDK2CBOGRE K20    W     N37372195W122023769                       E0133     NAR           BOGRE                    107992002")
+	if err := Process(in, ioutil.Discard, ContinueOnError(0)); err != nil {
+		t.Errorf("Process() = %v want <nil>", err)
+	}
+}
+
+func TestRecordErrorsAs(t *testing.T) {
+	var te *fixedwidth.UnmarshalTypeError
+	if errors.As(&RecordErrors{}, &te) {
+		t.Errorf("errors.As(&RecordErrors{}, *fixedwidth.UnmarshalTypeError) = true want false")
+	}
+	errs := &RecordErrors{Errors: []*RecordError{
+		{Line: 1, Err: errors.New("bad")},
+		{Line: 2, Err: &fixedwidth.UnmarshalTypeError{Field: "Waypoint", Cause: errors.New("bad latitude")}},
+	}}
+	if !errors.As(errs, &te) {
+		t.Fatalf("errors.As(%v, *fixedwidth.UnmarshalTypeError) = false want true", errs)
+	}
+	if te.Field != "Waypoint" {
+		t.Errorf("errors.As() field = %q want %q", te.Field, "Waypoint")
+	}
+}
+
+func TestRecordErrorsMessage(t *testing.T) {
+	var errs []*RecordError
+	for i := 1; i <= 12; i++ {
+		errs = append(errs, &RecordError{Line: i, Err: errors.New("bad")})
+	}
+	for _, tt := range []struct {
+		name string
+		err  *RecordErrors
+		want string
+	}{
+		{
+			name: "One",
+			err:  &RecordErrors{Errors: errs[:1]},
+			want: "1 records could not be processed: could not process record on line 1: bad",
+		},
+		{
+			name: "Aborted",
+			err:  &RecordErrors{Errors: errs[:2], Aborted: true},
+			want: "2 records could not be processed, giving up: could not process record on line 1: bad; could not process record on line 2: bad",
+		},
+		{
+			name: "Truncated",
+			err:  &RecordErrors{Errors: errs},
+			want: "12 records could not be processed: " +
+				"could not process record on line 1: bad; could not process record on line 2: bad; " +
+				"could not process record on line 3: bad; could not process record on line 4: bad; " +
+				"could not process record on line 5: bad; could not process record on line 6: bad; " +
+				"could not process record on line 7: bad; could not process record on line 8: bad; " +
+				"could not process record on line 9: bad; could not process record on line 10: bad; and 2 more",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q want %q", got, tt.want)
+			}
+		})
+	}
+}
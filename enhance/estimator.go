@@ -0,0 +1,207 @@
+package enhance
+
+import (
+	"fmt"
+	"strings"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+)
+
+// LocalizerData is the navigation data surrounding a localizer that is
+// available to a BearingEstimator. Fixes that could not be found in the input
+// data are nil.
+type LocalizerData struct {
+	// Record is the localizer record being enhanced.
+	Record *arinc.AirportLocGSPrimaryRecord
+	// Position is the position of the localizer antenna.
+	Position *geo.Point
+	// MagVar is the magnetic variation at the airport.
+	MagVar arinc.MagVar
+	// ApproachID is the identifier of an approach that uses the localizer,
+	// or empty if there is none.
+	ApproachID string
+	// FinalApproachFixID is the identifier of the final approach fix of the
+	// approach.
+	FinalApproachFixID string
+	FinalApproachFix   *geo.Point
+	// MissedApproachPointID is the identifier of the missed approach point
+	// of the approach.
+	MissedApproachPointID string
+	MissedApproachPoint   *geo.Point
+	// RunwayThreshold is the landing threshold of the runway served by the
+	// localizer, and OppositeThreshold is the threshold at the other end of
+	// the same runway.
+	RunwayThreshold   *geo.Point
+	OppositeThreshold *geo.Point
+	// EarthModel is the earth model that estimators should use to compute
+	// bearings.
+	EarthModel EarthModel
+}
+
+// BearingEstimator estimates the true bearing of a localizer course.
+type BearingEstimator interface {
+	// ID returns a short identifier for the estimator. It is recorded in the
+	// report of each localizer that the estimator computed a bearing for, and
+	// the built-in estimators are noted in the bearing source of the
+	// simulation continuation record.
+	ID() string
+	// EstimateBearing returns the estimated true bearing of the localizer
+	// course in degrees, in the range [0, 360). If the bearing cannot be
+	// estimated from the available data, an error is returned.
+	EstimateBearing(loc *LocalizerData) (float64, error)
+}
+
+// estimatorBearingSources are the localizer bearing sources written to the
+// simulation continuation record, by estimator ID, so that the output notes
+// which estimator produced each bearing. The bearings of other estimators are
+// marked with the bearing source for non-government sources.
+var estimatorBearingSources = map[string]string{
+	"FAF":               "F",
+	"MAP":               "M",
+	"RWY":               "R",
+	"PUB":               "P",
+	OverrideEstimatorID: "O",
+}
+
+// bearingSource returns the localizer bearing source for a bearing produced
+// by the estimator with the given ID.
+func bearingSource(estimatorID string) string {
+	if source, ok := estimatorBearingSources[estimatorID]; ok {
+		return source
+	}
+	return arinc.LocalizerBearingSourceNotGovt
+}
+
+// defaultEstimators is the chain of estimators used if none are specified.
+var defaultEstimators = []BearingEstimator{
+	FinalApproachFixEstimator{},
+	MissedApproachPointEstimator{},
+	RunwayThresholdEstimator{},
+}
+
+// BearingEstimators is an option that sets the chain of estimators used to
+// compute localizer bearings. The estimators are tried in order, and the
+// bearing from the first one that succeeds is used. A localizer is only left
+// unenhanced if every estimator fails. By default, the chain is
+// FinalApproachFixEstimator, MissedApproachPointEstimator, and
+// RunwayThresholdEstimator.
+func BearingEstimators(estimators ...BearingEstimator) Option {
+	return func(p *processor) {
+		p.Estimators = estimators
+	}
+}
+
+// estimateBearing runs the estimator chain for the localizer, and returns the
+// first bearing that is successfully estimated along with the estimator that
+// produced it.
+func (p *processor) estimateBearing(loc *LocalizerData) (float64, BearingEstimator, error) {
+	estimators := p.Estimators
+	if estimators == nil {
+		estimators = defaultEstimators
+	}
+	var errs []string
+	for _, e := range estimators {
+		bearing, err := e.EstimateBearing(loc)
+		if err == nil {
+			return bearing, e, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", e.ID(), err))
+	}
+	return 0, nil, fmt.Errorf("no estimator could compute a bearing for localizer %q (%s)", loc.Record.LocalizerID, strings.Join(errs, "; "))
+}
+
+// FinalApproachFixEstimator estimates the localizer course as the bearing
+// from the final approach fix of an approach that uses the localizer to the
+// localizer antenna.
+type FinalApproachFixEstimator struct{}
+
+// ID implements BearingEstimator.
+func (FinalApproachFixEstimator) ID() string {
+	return "FAF"
+}
+
+// EstimateBearing implements BearingEstimator.
+func (FinalApproachFixEstimator) EstimateBearing(loc *LocalizerData) (float64, error) {
+	if loc.ApproachID == "" {
+		return 0, fmt.Errorf("could not find corresponding approach for localizer %q", loc.Record.LocalizerID)
+	}
+	if loc.FinalApproachFix == nil {
+		return 0, fmt.Errorf("could not find corresponding waypoint for final approach fix %q", loc.FinalApproachFixID)
+	}
+	return loc.EarthModel.Bearing(loc.FinalApproachFix, loc.Position)
+}
+
+// MissedApproachPointEstimator estimates the localizer course as the bearing
+// from the missed approach point of an approach that uses the localizer to
+// the localizer antenna. This is accurate when the missed approach point lies
+// on the localizer course, which is usually the case when it is at the runway
+// threshold.
+type MissedApproachPointEstimator struct{}
+
+// ID implements BearingEstimator.
+func (MissedApproachPointEstimator) ID() string {
+	return "MAP"
+}
+
+// EstimateBearing implements BearingEstimator.
+func (MissedApproachPointEstimator) EstimateBearing(loc *LocalizerData) (float64, error) {
+	if loc.ApproachID == "" {
+		return 0, fmt.Errorf("could not find corresponding approach for localizer %q", loc.Record.LocalizerID)
+	}
+	if loc.MissedApproachPoint == nil {
+		return 0, fmt.Errorf("could not find corresponding waypoint for missed approach point %q", loc.MissedApproachPointID)
+	}
+	return loc.EarthModel.Bearing(loc.MissedApproachPoint, loc.Position)
+}
+
+// RunwayThresholdEstimator estimates the localizer course as the bearing
+// along the runway served by the localizer, from its landing threshold to the
+// threshold at the opposite end. If the opposite threshold is not known, the
+// bearing from the landing threshold to the localizer antenna is used instead.
+// This is accurate for localizers that are aligned with the runway
+// centerline, which is true of most ILS installations.
+type RunwayThresholdEstimator struct{}
+
+// ID implements BearingEstimator.
+func (RunwayThresholdEstimator) ID() string {
+	return "RWY"
+}
+
+// EstimateBearing implements BearingEstimator.
+func (RunwayThresholdEstimator) EstimateBearing(loc *LocalizerData) (float64, error) {
+	if loc.RunwayThreshold == nil {
+		return 0, fmt.Errorf("could not find runway %q for localizer %q", loc.Record.RunwayIdentifier, loc.Record.LocalizerID)
+	}
+	to := loc.Position
+	if loc.OppositeThreshold != nil {
+		to = loc.OppositeThreshold
+	}
+	return loc.EarthModel.Bearing(loc.RunwayThreshold, to)
+}
+
+// PublishedBearingEstimator estimates the localizer course from the published
+// localizer bearing, converted to a true bearing with the magnetic variation
+// at the airport. It is only as accurate as the published bearing, which is
+// rounded to a tenth of a degree, but it does not depend on any other records.
+type PublishedBearingEstimator struct{}
+
+// ID implements BearingEstimator.
+func (PublishedBearingEstimator) ID() string {
+	return "PUB"
+}
+
+// EstimateBearing implements BearingEstimator.
+func (PublishedBearingEstimator) EstimateBearing(loc *LocalizerData) (float64, error) {
+	return publishedTrueBearing(loc.Record, loc.MagVar)
+}
+
+// publishedTrueBearing returns the published bearing of the localizer
+// converted to a true bearing.
+func publishedTrueBearing(loc *arinc.AirportLocGSPrimaryRecord, magVar arinc.MagVar) (float64, error) {
+	trueBrg, err := loc.LocalizerBearing.True(magVar)
+	if err != nil {
+		return 0, fmt.Errorf("could not convert localizer bearing: %v", err)
+	}
+	return trueBrg.Value, nil
+}
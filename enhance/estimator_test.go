@@ -0,0 +1,245 @@
+package enhance
+
+import (
+	"math"
+	"testing"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+)
+
+func TestEstimators(t *testing.T) {
+	const tolerance = 0.00001
+	record := &arinc.AirportLocGSPrimaryRecord{
+		LocalizerID:      "IHWD",
+		RunwayIdentifier: "RW28L",
+		LocalizerBearing: arinc.Bearing{Value: 287.9},
+	}
+	locPosition := geo.NewPoint(37.66283333, -122.12965278)
+	for _, tt := range []struct {
+		name      string
+		estimator BearingEstimator
+		data      *LocalizerData
+		want      float64
+		wantErr   bool
+	}{
+		{
+			name:      "FinalApproachFix",
+			estimator: FinalApproachFixEstimator{},
+			data: &LocalizerData{
+				Record:             record,
+				Position:           locPosition,
+				ApproachID:         "L28L",
+				FinalApproachFixID: "FERNE",
+				FinalApproachFix:   geo.NewPoint(37.59, -121.99),
+			},
+			want: 303.29629,
+		},
+		{
+			name:      "FinalApproachFixNoApproach",
+			estimator: FinalApproachFixEstimator{},
+			data: &LocalizerData{
+				Record:   record,
+				Position: locPosition,
+			},
+			wantErr: true,
+		},
+		{
+			name:      "FinalApproachFixMissingWaypoint",
+			estimator: FinalApproachFixEstimator{},
+			data: &LocalizerData{
+				Record:             record,
+				Position:           locPosition,
+				ApproachID:         "L28L",
+				FinalApproachFixID: "FERNE",
+			},
+			wantErr: true,
+		},
+		{
+			name:      "MissedApproachPoint",
+			estimator: MissedApproachPointEstimator{},
+			data: &LocalizerData{
+				Record:                record,
+				Position:              locPosition,
+				ApproachID:            "L28L",
+				MissedApproachPointID: "RW28L",
+				MissedApproachPoint:   geo.NewPoint(37.65518333, -122.11475833),
+			},
+			want: 302.86910,
+		},
+		{
+			name:      "MissedApproachPointNoApproach",
+			estimator: MissedApproachPointEstimator{},
+			data: &LocalizerData{
+				Record:   record,
+				Position: locPosition,
+			},
+			wantErr: true,
+		},
+		{
+			name:      "MissedApproachPointMissingWaypoint",
+			estimator: MissedApproachPointEstimator{},
+			data: &LocalizerData{
+				Record:                record,
+				Position:              locPosition,
+				ApproachID:            "L28L",
+				MissedApproachPointID: "RW28L",
+			},
+			wantErr: true,
+		},
+		{
+			name:      "RunwayThreshold",
+			estimator: RunwayThresholdEstimator{},
+			data: &LocalizerData{
+				Record:            record,
+				Position:          locPosition,
+				RunwayThreshold:   geo.NewPoint(37.65518333, -122.11475833),
+				OppositeThreshold: geo.NewPoint(37.66093056, -122.12734722),
+			},
+			want: 299.87015,
+		},
+		{
+			name:      "RunwayThresholdNoOpposite",
+			estimator: RunwayThresholdEstimator{},
+			data: &LocalizerData{
+				Record:          record,
+				Position:        locPosition,
+				RunwayThreshold: geo.NewPoint(37.65518333, -122.11475833),
+			},
+			want: 302.86910,
+		},
+		{
+			name:      "RunwayThresholdMissingRunway",
+			estimator: RunwayThresholdEstimator{},
+			data: &LocalizerData{
+				Record:   record,
+				Position: locPosition,
+			},
+			wantErr: true,
+		},
+		{
+			name:      "PublishedMagnetic",
+			estimator: PublishedBearingEstimator{},
+			data: &LocalizerData{
+				Record: record,
+				MagVar: arinc.MagVar{Value: -15},
+			},
+			want: 302.9,
+		},
+		{
+			name:      "PublishedMagneticWrapsAround",
+			estimator: PublishedBearingEstimator{},
+			data: &LocalizerData{
+				Record: &arinc.AirportLocGSPrimaryRecord{LocalizerBearing: arinc.Bearing{Value: 355}},
+				MagVar: arinc.MagVar{Value: -15},
+			},
+			want: 10,
+		},
+		{
+			name:      "PublishedTrue",
+			estimator: PublishedBearingEstimator{},
+			data: &LocalizerData{
+				Record: &arinc.AirportLocGSPrimaryRecord{LocalizerBearing: arinc.Bearing{Value: 303, Reference: arinc.TrueNorth}},
+				MagVar: arinc.MagVar{Value: -15},
+			},
+			want: 303,
+		},
+		{
+			name:      "PublishedMagneticWhereBearingsAreTrue",
+			estimator: PublishedBearingEstimator{},
+			data: &LocalizerData{
+				Record: &arinc.AirportLocGSPrimaryRecord{LocalizerBearing: arinc.Bearing{Value: 303}},
+				MagVar: arinc.MagVar{Reference: arinc.TrueNorth},
+			},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.estimator.EstimateBearing(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("EstimateBearing() = _, <nil> want _, <non-nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EstimateBearing() = _, %v want _, <nil>", err)
+			}
+			if math.Abs(got-tt.want) > tolerance {
+				t.Errorf("EstimateBearing() = %.5f want %.5f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateBearingChain(t *testing.T) {
+	data := &LocalizerData{
+		Record: &arinc.AirportLocGSPrimaryRecord{
+			LocalizerID:      "IHWD",
+			LocalizerBearing: arinc.Bearing{Value: 287.9},
+		},
+		Position: geo.NewPoint(37.66283333, -122.12965278),
+		MagVar:   arinc.MagVar{Value: -15},
+	}
+	for _, tt := range []struct {
+		name       string
+		estimators []BearingEstimator
+		want       float64
+		wantID     string
+		wantErr    bool
+	}{
+		{
+			name:       "FallsBack",
+			estimators: []BearingEstimator{FinalApproachFixEstimator{}, RunwayThresholdEstimator{}, PublishedBearingEstimator{}},
+			want:       302.9,
+			wantID:     "PUB",
+		},
+		{
+			name:       "AllFail",
+			estimators: []BearingEstimator{FinalApproachFixEstimator{}, RunwayThresholdEstimator{}},
+			wantErr:    true,
+		},
+		{
+			name:    "DefaultChainFails",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newProcessor(BearingEstimators(tt.estimators...))
+			got, e, err := p.estimateBearing(data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("estimateBearing() = _, _, <nil> want _, _, <non-nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("estimateBearing() = _, _, %v want _, _, <nil>", err)
+			}
+			if math.Abs(got-tt.want) > 0.00001 {
+				t.Errorf("estimateBearing() = %f, _, _ want %f, _, _", got, tt.want)
+			}
+			if e.ID() != tt.wantID {
+				t.Errorf("estimateBearing() estimator = %q want %q", e.ID(), tt.wantID)
+			}
+		})
+	}
+}
+
+func TestBearingSource(t *testing.T) {
+	for _, tt := range []struct {
+		estimatorID string
+		want        string
+	}{
+		{FinalApproachFixEstimator{}.ID(), "F"},
+		{MissedApproachPointEstimator{}.ID(), "M"},
+		{RunwayThresholdEstimator{}.ID(), "R"},
+		{PublishedBearingEstimator{}.ID(), "P"},
+		{OverrideEstimatorID, "O"},
+		{"CUSTOM", "N"},
+	} {
+		if got := bearingSource(tt.estimatorID); got != tt.want {
+			t.Errorf("bearingSource(%q) = %q want %q", tt.estimatorID, got, tt.want)
+		}
+	}
+}
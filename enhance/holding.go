@@ -0,0 +1,204 @@
+package enhance
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+	"github.com/wallaceicy06/enhance-faa-cifp/geodesy"
+)
+
+const (
+	// metersPerNauticalMile is the length of a nautical mile in meters.
+	metersPerNauticalMile = 1852.0
+	// defaultHoldingSpeed is the speed in knots used to compute the geometry
+	// of holding patterns that do not specify a holding speed.
+	defaultHoldingSpeed = 230
+	// defaultHoldingLegTime is the inbound leg time used for holding patterns
+	// that specify neither a leg length nor a leg time.
+	defaultHoldingLegTime = time.Minute
+)
+
+// Holding is a holding pattern whose fix has been resolved to a position.
+type Holding struct {
+	arinc.HoldingPattern
+	// Fix is the position of the holding fix, or nil if the fix could not be
+	// found in the data.
+	Fix *geo.Point
+	// MagVar is the magnetic variation used to convert the inbound course to a
+	// true course. For terminal holding patterns it is the variation at the
+	// airport, and for enroute holding patterns it is the variation at the
+	// fix. It is nil if the variation is unknown, such as at a VOR, whose
+	// station declination may differ from the variation.
+	MagVar *arinc.MagVar
+}
+
+// CollectHoldings is an option that decodes every holding pattern in the
+// data, resolves its fix against the navaids and waypoints in the data, and
+// appends it to holdings once all of the data is processed. Holding patterns
+// whose fix cannot be found are included with a nil Fix.
+func CollectHoldings(holdings *[]*Holding) Option {
+	return func(p *processor) {
+		p.Holdings = holdings
+		p.OtherMagVars = make(map[string]arinc.MagVar)
+	}
+}
+
+// collectHolding decodes the holding pattern record so that it can be resolved
+// once all of the fixes are known.
+func (p *processor) collectHolding(rec *arinc.HoldingPatternRecord) error {
+	if rec.ContinuationRecordNumber != "0" && rec.ContinuationRecordNumber != "1" {
+		return nil
+	}
+	h, err := rec.HoldingPattern()
+	if err != nil {
+		return fmt.Errorf("could not decode holding pattern at %q: %v", rec.FixID, err)
+	}
+	p.PendingHoldings = append(p.PendingHoldings, &Holding{HoldingPattern: *h})
+	return nil
+}
+
+// recordMagVar records the magnetic variation at an enroute fix for resolving
+// enroute holding patterns. Fixes without a magnetic variation are ignored.
+func (p *processor) recordMagVar(id string, magVar arinc.NullMagVar) {
+	if p.Holdings == nil || !magVar.Valid {
+		return
+	}
+	p.OtherMagVars[id] = magVar.MagVar
+}
+
+// resolveHoldings resolves the fixes of the collected holding patterns and
+// appends them to Holdings.
+func (p *processor) resolveHoldings() {
+	if p.Holdings == nil {
+		return
+	}
+	for _, h := range p.PendingHoldings {
+		if a, ok := p.Airports[h.RegionCode]; ok && !h.IsEnroute() {
+			h.Fix = p.findFix(a, h.FixID)
+			v := a.MagVar
+			h.MagVar = &v
+		} else {
+			h.Fix = p.OtherWaypoints[h.FixID]
+			if v, ok := p.OtherMagVars[h.FixID]; ok {
+				h.MagVar = &v
+			}
+		}
+		if h.Fix == nil {
+			log.Printf("Could not find fix %q for holding pattern in region %q", h.FixID, h.RegionCode)
+		} else if h.MagVar == nil && h.InboundCourse.Reference == arinc.MagneticNorth {
+			log.Printf("Magnetic variation at fix %q for holding pattern in region %q is unknown, so its inbound course cannot be converted to a true course", h.FixID, h.RegionCode)
+		}
+		*p.Holdings = append(*p.Holdings, h)
+	}
+	p.PendingHoldings = nil
+}
+
+// TrueInboundCourse returns the true course of the inbound leg in degrees in
+// the range [0, 360). If a magnetic course cannot be converted because the
+// magnetic variation is unknown or bearings are true, an error is returned.
+func (h *Holding) TrueInboundCourse() (float64, error) {
+	if h.InboundCourse.Reference == arinc.TrueNorth {
+		return h.InboundCourse.Value, nil
+	}
+	if h.MagVar == nil {
+		return 0, fmt.Errorf("inbound course of holding pattern at %q: magnetic variation is unknown", h.FixID)
+	}
+	course, err := h.InboundCourse.True(*h.MagVar)
+	if err != nil {
+		return 0, fmt.Errorf("inbound course of holding pattern at %q: %v", h.FixID, err)
+	}
+	return course.Value, nil
+}
+
+// Racetrack returns the outline of the holding pattern as a closed polyline
+// that starts and ends at the fix, for drawing on a map. Each turn is
+// approximated by pointsPerTurn segments. Turns are flown at standard rate at
+// the holding speed, or 230 knots if there is none, and a leg time is
+// converted to a length at the same speed. The inbound leg is one minute if
+// neither a leg length nor a leg time is given. Wind is not considered.
+func (h *Holding) Racetrack(pointsPerTurn int) ([]*geo.Point, error) {
+	if h.Fix == nil {
+		return nil, fmt.Errorf("fix %q of holding pattern has no position", h.FixID)
+	}
+	if pointsPerTurn < 1 {
+		return nil, fmt.Errorf("invalid number of points per turn %d", pointsPerTurn)
+	}
+	speed := float64(h.Speed)
+	if speed == 0 {
+		speed = defaultHoldingSpeed
+	}
+	legLength := h.LegLength
+	switch {
+	case legLength != 0:
+	case h.LegTime != 0:
+		legLength = speed * h.LegTime.Hours()
+	default:
+		legLength = speed * defaultHoldingLegTime.Hours()
+	}
+	// A standard rate turn takes two minutes to turn 360 degrees.
+	radius := speed / 30 / (2 * math.Pi)
+
+	// side is the direction of the turns relative to the inbound course.
+	side := 90.0
+	if h.TurnDirection == arinc.TurnLeft {
+		side = -90
+	}
+	course, err := h.TrueInboundCourse()
+	if err != nil {
+		return nil, err
+	}
+	move := func(from *geo.Point, azi, nm float64) (*geo.Point, error) {
+		lat, lon, err := geodesy.Destination(from.Lat(), from.Lng(), math.Mod(azi+720, 360), nm*metersPerNauticalMile)
+		if err != nil {
+			return nil, err
+		}
+		return geo.NewPoint(lat, lon), nil
+	}
+	// The inbound leg arrives at the fix on the inbound course, so it starts
+	// one leg length from the fix along the reciprocal. The course at the
+	// start of the leg differs slightly from the course at the fix because
+	// of the convergence of the meridians.
+	lat, lon, azi, err := geodesy.WGS84.Direct(h.Fix.Lat(), h.Fix.Lng(), math.Mod(course+180, 360), legLength*metersPerNauticalMile)
+	if err != nil {
+		return nil, err
+	}
+	legStart := geo.NewPoint(lat, lon)
+	startCourse := azi + 180
+	// The turn from the inbound to the outbound leg is centered abeam the
+	// fix, and the turn back to the inbound leg is centered abeam the start
+	// of the inbound leg.
+	center1, err := move(h.Fix, course+side, radius)
+	if err != nil {
+		return nil, err
+	}
+	center2, err := move(legStart, startCourse+side, radius)
+	if err != nil {
+		return nil, err
+	}
+	var points []*geo.Point
+	for _, turn := range []struct {
+		center *geo.Point
+		start  float64
+	}{
+		{center1, course - side},
+		{center2, startCourse + side},
+	} {
+		for i := 0; i <= pointsPerTurn; i++ {
+			azi := turn.start + 2*side*float64(i)/float64(pointsPerTurn)
+			pt, err := move(turn.center, azi, radius)
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, pt)
+		}
+	}
+	// The first point of the first turn is the fix, and the last point of the
+	// second turn is the start of the inbound leg.
+	points[0] = h.Fix
+	points[len(points)-1] = legStart
+	return append(points, h.Fix), nil
+}
@@ -0,0 +1,243 @@
+package enhance
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+	"github.com/wallaceicy06/enhance-faa-cifp/geodesy"
+)
+
+func TestCollectHoldings(t *testing.T) {
+	in := strings.Join([]string{
+		"SUSAEAENRT   SUNOL K20    C  RL N37000000W121000000                       E0132     NAR           SUNOL                    459212002",
+		"SUSAEPENRTK2                 SUNOLK2EA01150R   100500017000230                                    SUNOL                    459892002",
+		"SUSAEPKHWDK2                 BOGREK2PC02880L040  03000                                            BOGRE                    459892002",
+		"SUSAEPENRTK2                 NOFIXK2EA01150R   100500017000230                                    NOFIX                    459892002",
+		"SUSAD        PYE   K2011370VDHW N38000000W122000000    N38044712W122520418E0170013402     NARPOINT REYES                   236192002",
+		"SUSAEPENRTK2                 PYE  K2D 01150R   100500017000230                                    PYE                      459892002",
+		"SUSAP KHWDK2AHWD     0     056YHN37393214W122071825E015000052         1800018000C    MNAR    HAYWARD EXECUTIVE             107981608",
+		"SUSAP KHWDK2CBOGRE K20    W     N37372195W122023769                       E0133     NAR           BOGRE                    107992002",
+	}, "\n")
+	type holdingSummary struct {
+		RegionCode string
+		FixID      string
+		HasFix     bool
+		Lat, Lng   float64
+		MagVar     *arinc.MagVar
+	}
+	want := []holdingSummary{
+		{RegionCode: "ENRT", FixID: "SUNOL", HasFix: true, Lat: 37, Lng: -121, MagVar: &arinc.MagVar{Value: -13.2}},
+		{RegionCode: "KHWD", FixID: "BOGRE", HasFix: true, Lat: 37.622764, Lng: -122.043803, MagVar: &arinc.MagVar{Value: -15}},
+		{RegionCode: "ENRT", FixID: "NOFIX"},
+		// The station declination of a VOR is not its magnetic variation.
+		{RegionCode: "ENRT", FixID: "PYE", HasFix: true, Lat: 38, Lng: -122},
+	}
+	for _, process := range []struct {
+		name string
+		fn   func(out *bytes.Buffer, opts ...Option) error
+	}{
+		{"Process", func(out *bytes.Buffer, opts ...Option) error {
+			return Process(strings.NewReader(in), out, opts...)
+		}},
+		{"ProcessStream", func(out *bytes.Buffer, opts ...Option) error {
+			return ProcessStream(strings.NewReader(in), out, opts...)
+		}},
+	} {
+		t.Run(process.name, func(t *testing.T) {
+			var holdings []*Holding
+			var out bytes.Buffer
+			if err := process.fn(&out, CollectHoldings(&holdings)); err != nil {
+				t.Fatalf("%s() = %v want <nil>", process.name, err)
+			}
+			if diff := cmp.Diff(in+"\n", out.String()); diff != "" {
+				t.Errorf("%s() out content not as expected (-want +got): %s", process.name, diff)
+			}
+			var got []holdingSummary
+			for _, h := range holdings {
+				s := holdingSummary{RegionCode: h.RegionCode, FixID: h.FixID, MagVar: h.MagVar}
+				if h.Fix != nil {
+					s.HasFix = true
+					// Round to about 10 cm to compare with the expected values.
+					s.Lat = math.Round(h.Fix.Lat()*1e6) / 1e6
+					s.Lng = math.Round(h.Fix.Lng()*1e6) / 1e6
+				}
+				got = append(got, s)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("%s() holdings had diffs (-want +got): %s", process.name, diff)
+			}
+		})
+	}
+}
+
+func TestCollectHoldingsBadRecord(t *testing.T) {
+	in := strings.NewReader("SUSAEPENRTK2                 SUNOLK2EA01100X   100500017000230                                    SUNOL                    459892002")
+	var holdings []*Holding
+	var out bytes.Buffer
+	if err := Process(in, &out, CollectHoldings(&holdings)); err == nil {
+		t.Errorf("Process() = <nil> want <non-nil>")
+	}
+	// Holding patterns are only decoded when they are collected.
+	in.Seek(0, 0)
+	if err := Process(in, &out); err != nil {
+		t.Errorf("Process() without CollectHoldings = %v want <nil>", err)
+	}
+}
+
+func TestTrueInboundCourse(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		holding *Holding
+		want    float64
+		wantErr bool
+	}{
+		{
+			name:    "EastVariation",
+			holding: &Holding{HoldingPattern: arinc.HoldingPattern{InboundCourse: arinc.Bearing{Value: 115}}, MagVar: &arinc.MagVar{Value: -13.2}},
+			want:    128.2,
+		},
+		{
+			name:    "WestVariationWraps",
+			holding: &Holding{HoldingPattern: arinc.HoldingPattern{InboundCourse: arinc.Bearing{Value: 5}}, MagVar: &arinc.MagVar{Value: 10}},
+			want:    355,
+		},
+		{
+			name:    "True",
+			holding: &Holding{HoldingPattern: arinc.HoldingPattern{InboundCourse: arinc.Bearing{Value: 5, Reference: arinc.TrueNorth}}, MagVar: &arinc.MagVar{Value: 10}},
+			want:    5,
+		},
+		{
+			name:    "TrueUnknownVariation",
+			holding: &Holding{HoldingPattern: arinc.HoldingPattern{InboundCourse: arinc.Bearing{Value: 5, Reference: arinc.TrueNorth}}},
+			want:    5,
+		},
+		{
+			name:    "UnknownVariation",
+			holding: &Holding{HoldingPattern: arinc.HoldingPattern{InboundCourse: arinc.Bearing{Value: 5}}},
+			wantErr: true,
+		},
+		{
+			name:    "MagneticWhereBearingsAreTrue",
+			holding: &Holding{HoldingPattern: arinc.HoldingPattern{InboundCourse: arinc.Bearing{Value: 5}}, MagVar: &arinc.MagVar{Reference: arinc.TrueNorth}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.holding.TrueInboundCourse()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("TrueInboundCourse() = _, <nil> want _, <non-nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TrueInboundCourse() = _, %v want _, <nil>", err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("TrueInboundCourse() = %f want %f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRacetrack(t *testing.T) {
+	const (
+		pointsPerTurn = 8
+		distTolerance = 1.0
+		aziTolerance  = 0.01
+	)
+	fix := geo.NewPoint(37.622764, -122.039914)
+	inverse := func(from, to *geo.Point) (float64, float64) {
+		dist, azi, _, err := geodesy.WGS84.Inverse(from.Lat(), from.Lng(), to.Lat(), to.Lng())
+		if err != nil {
+			t.Fatalf("Inverse() = _, _, _, %v want _, _, _, <nil>", err)
+		}
+		return dist, azi
+	}
+	for _, tt := range []struct {
+		name        string
+		holding     *Holding
+		wantLength  float64
+		wantRadius  float64
+		wantOutSide float64
+	}{
+		{
+			name: "RightLegLength",
+			holding: &Holding{
+				HoldingPattern: arinc.HoldingPattern{InboundCourse: arinc.Bearing{Value: 288, Reference: arinc.TrueNorth}, TurnDirection: arinc.TurnRight, LegLength: 4, Speed: 200},
+				Fix:            fix,
+			},
+			wantLength:  4,
+			wantRadius:  200 / (60 * math.Pi),
+			wantOutSide: 18,
+		},
+		{
+			name: "LeftLegTime",
+			holding: &Holding{
+				HoldingPattern: arinc.HoldingPattern{InboundCourse: arinc.Bearing{Value: 288}, TurnDirection: arinc.TurnLeft, LegTime: 90 * time.Second},
+				Fix:            fix,
+				MagVar:         &arinc.MagVar{Value: -15},
+			},
+			wantLength:  230 * 1.5 / 60,
+			wantRadius:  230 / (60 * math.Pi),
+			wantOutSide: 213,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.holding.Racetrack(pointsPerTurn)
+			if err != nil {
+				t.Fatalf("Racetrack() = _, %v want _, <nil>", err)
+			}
+			if want := 2*(pointsPerTurn+1) + 1; len(got) != want {
+				t.Fatalf("Racetrack() returned %d points want %d", len(got), want)
+			}
+			if got[0] != fix || got[len(got)-1] != fix {
+				t.Errorf("Racetrack() does not start and end at the fix")
+			}
+			course, err := tt.holding.TrueInboundCourse()
+			if err != nil {
+				t.Fatalf("TrueInboundCourse() = _, %v want _, <nil>", err)
+			}
+			checks := []struct {
+				name           string
+				from, to       *geo.Point
+				wantNM, wantAz float64
+				aziTolerance   float64
+			}{
+				{"EndOfFirstTurn", fix, got[pointsPerTurn], 2 * tt.wantRadius, tt.wantOutSide, aziTolerance},
+				// The outbound leg is offset from the inbound leg, so the
+				// meridians converge between them.
+				{"OutboundLeg", got[pointsPerTurn], got[pointsPerTurn+1], tt.wantLength, math.Mod(course+180, 360), 0.05},
+				// The inbound leg is checked from the fix, where the inbound
+				// course is defined.
+				{"InboundLeg", fix, got[2*pointsPerTurn+1], tt.wantLength, math.Mod(course+180, 360), aziTolerance},
+			}
+			for _, c := range checks {
+				dist, azi := inverse(c.from, c.to)
+				if math.Abs(dist-c.wantNM*metersPerNauticalMile) > distTolerance {
+					t.Errorf("%s length = %f m want %f m", c.name, dist, c.wantNM*metersPerNauticalMile)
+				}
+				if math.Abs(bearingDelta(c.wantAz, azi)) > c.aziTolerance {
+					t.Errorf("%s azimuth = %f want %f", c.name, azi, c.wantAz)
+				}
+			}
+		})
+	}
+}
+
+func TestRacetrackErrors(t *testing.T) {
+	h := &Holding{HoldingPattern: arinc.HoldingPattern{FixID: "NOFIX", TurnDirection: arinc.TurnRight}}
+	if _, err := h.Racetrack(8); err == nil {
+		t.Errorf("Racetrack() with no fix = _, <nil> want _, <non-nil>")
+	}
+	h.Fix = geo.NewPoint(37, -122)
+	if _, err := h.Racetrack(0); err == nil {
+		t.Errorf("Racetrack(0) = _, <nil> want _, <non-nil>")
+	}
+}
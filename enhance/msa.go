@@ -0,0 +1,113 @@
+package enhance
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+	"github.com/wallaceicy06/enhance-faa-cifp/geodesy"
+)
+
+// MSA is a minimum sector altitude whose center has been resolved to a
+// position.
+type MSA struct {
+	arinc.MSA
+	// Center is the position of the MSA center, or nil if the center could
+	// not be found in the data.
+	Center *geo.Point
+	// MagVar is the magnetic variation at the airport. It is used to convert
+	// true bearings to the magnetic sector bearings.
+	MagVar arinc.MagVar
+}
+
+// MSAs holds the minimum sector altitudes of each airport, keyed by airport
+// identifier.
+type MSAs map[string][]*MSA
+
+// CollectMSAs is an option that decodes every minimum sector altitude in the
+// data, resolves its center against the navaids and waypoints in the data,
+// and adds it to msas once all of the data is processed. MSAs whose center
+// cannot be found are included with a nil Center.
+func CollectMSAs(msas MSAs) Option {
+	return func(p *processor) {
+		p.MSAs = msas
+	}
+}
+
+// collectMSA decodes the MSA record so that it can be resolved once all of the
+// fixes are known.
+func (p *processor) collectMSA(rec *arinc.AirportMSARecord) error {
+	if rec.ContinuationRecordNumber != "0" && rec.ContinuationRecordNumber != "1" {
+		return nil
+	}
+	m, err := rec.MSA()
+	if err != nil {
+		return fmt.Errorf("could not decode MSA at %q: %v", rec.MSACenter, err)
+	}
+	p.PendingMSAs = append(p.PendingMSAs, &MSA{MSA: *m})
+	return nil
+}
+
+// resolveMSAs resolves the centers of the collected MSAs and adds them to
+// MSAs.
+func (p *processor) resolveMSAs() {
+	if p.MSAs == nil {
+		return
+	}
+	for _, m := range p.PendingMSAs {
+		if a, ok := p.Airports[m.AirportID]; ok {
+			m.Center = p.findFix(a, m.CenterID)
+			m.MagVar = a.MagVar
+		} else {
+			m.Center = p.OtherWaypoints[m.CenterID]
+		}
+		if m.Center == nil {
+			log.Printf("Could not find center %q for MSA at airport %q", m.CenterID, m.AirportID)
+		}
+		p.MSAs[m.AirportID] = append(p.MSAs[m.AirportID], m)
+	}
+	p.PendingMSAs = nil
+}
+
+// Sector returns the sector of the MSA that the point is in. If the point is
+// outside of the radius of its sector, or the MSA has no center, nil is
+// returned.
+func (m *MSA) Sector(pt *geo.Point) (*arinc.MSASector, error) {
+	if m.Center == nil {
+		return nil, nil
+	}
+	dist, bearing, _, err := geodesy.WGS84.Inverse(pt.Lat(), pt.Lng(), m.Center.Lat(), m.Center.Lng())
+	if err != nil {
+		return nil, fmt.Errorf("could not compute bearing to MSA center %q: %v", m.CenterID, err)
+	}
+	brg := arinc.Bearing{Value: math.Mod(bearing+360, 360), Reference: arinc.TrueNorth}
+	if !m.BearingsAreTrue {
+		if brg, err = brg.Magnetic(m.MagVar); err != nil {
+			return nil, fmt.Errorf("could not convert bearing to MSA center %q: %v", m.CenterID, err)
+		}
+	}
+	s := m.MSA.Sector(brg.Value)
+	if s == nil || dist > float64(s.Radius)*metersPerNauticalMile {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// Altitude returns the minimum sector altitude at the point for the airport.
+// If the point is in more than one of the airport's MSAs, the highest altitude
+// is returned. If the point is in none of them, ok is false.
+func (m MSAs) Altitude(airportID string, pt *geo.Point) (_ arinc.Altitude, ok bool, _ error) {
+	var alt arinc.Altitude
+	for _, msa := range m[airportID] {
+		s, err := msa.Sector(pt)
+		if err != nil {
+			return arinc.Altitude{}, false, err
+		}
+		if s != nil && (!ok || s.Altitude.Feet > alt.Feet) {
+			alt, ok = s.Altitude, true
+		}
+	}
+	return alt, ok, nil
+}
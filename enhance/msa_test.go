@@ -0,0 +1,147 @@
+package enhance
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+)
+
+func TestCollectMSAs(t *testing.T) {
+	in := strings.Join([]string{
+		"SUSAP KHWDK2AHWD     0     056YHN37393214W122071825E015000052         1800018000C    MNAR    HAYWARD EXECUTIVE             107981608",
+		"SUSAP KHWDK2CBOGRE K20    W     N37372195W122023769                       E0133     NAR           BOGRE                    107992002",
+		"SUSAP KHWDK2SBOGREK2PC                0   0001800402518036006025                                                       M   107992002",
+		"SUSAP KHWDK2SNOFIXK2PC                0   00036004025                                                                  M   107992002",
+	}, "\n")
+	for _, process := range []struct {
+		name string
+		fn   func(out *bytes.Buffer, opts ...Option) error
+	}{
+		{"Process", func(out *bytes.Buffer, opts ...Option) error {
+			return Process(strings.NewReader(in), out, opts...)
+		}},
+		{"ProcessStream", func(out *bytes.Buffer, opts ...Option) error {
+			return ProcessStream(strings.NewReader(in), out, opts...)
+		}},
+	} {
+		t.Run(process.name, func(t *testing.T) {
+			msas := make(MSAs)
+			var out bytes.Buffer
+			if err := process.fn(&out, CollectMSAs(msas)); err != nil {
+				t.Fatalf("%s() = %v want <nil>", process.name, err)
+			}
+			if diff := cmp.Diff(in+"\n", out.String()); diff != "" {
+				t.Errorf("%s() out content not as expected (-want +got): %s", process.name, diff)
+			}
+			got := msas["KHWD"]
+			if len(got) != 2 {
+				t.Fatalf("%s() collected %d MSAs want 2", process.name, len(got))
+			}
+			if got[0].CenterID != "BOGRE" || got[0].Center == nil || got[0].MagVar != (arinc.MagVar{Value: -15}) {
+				t.Errorf("%s() first MSA = %q at %v with variation %v want \"BOGRE\" with a center and variation 15.0E", process.name, got[0].CenterID, got[0].Center, got[0].MagVar)
+			}
+			if got[1].CenterID != "NOFIX" || got[1].Center != nil {
+				t.Errorf("%s() second MSA = %q at %v want \"NOFIX\" with no center", process.name, got[1].CenterID, got[1].Center)
+			}
+		})
+	}
+}
+
+func TestCollectMSAsBadRecord(t *testing.T) {
+	in := strings.NewReader("SUSAP KHWDK2SBOGREK2PC                0   0001X00402518036006025                                                       M   107992002")
+	var out bytes.Buffer
+	if err := Process(in, &out, CollectMSAs(make(MSAs))); err == nil {
+		t.Errorf("Process() = <nil> want <non-nil>")
+	}
+	// MSAs are only decoded when they are collected.
+	in.Seek(0, 0)
+	if err := Process(in, &out); err != nil {
+		t.Errorf("Process() without CollectMSAs = %v want <nil>", err)
+	}
+}
+
+func TestMSAsAltitude(t *testing.T) {
+	center := geo.NewPoint(37.622764, -122.039914)
+	msas := MSAs{
+		"KHWD": {
+			{
+				MSA: arinc.MSA{
+					AirportID: "KHWD",
+					CenterID:  "BOGRE",
+					Sectors: []*arinc.MSASector{
+						{FromBearing: 0, ToBearing: 180, Altitude: arinc.Altitude{Feet: 4000}, Radius: 25},
+						{FromBearing: 180, ToBearing: 360, Altitude: arinc.Altitude{Feet: 6000}, Radius: 25},
+					},
+				},
+				Center: center,
+				MagVar: arinc.MagVar{Value: -15},
+			},
+			{
+				MSA: arinc.MSA{
+					AirportID:       "KHWD",
+					CenterID:        "OAK",
+					BearingsAreTrue: true,
+					Sectors: []*arinc.MSASector{
+						{FromBearing: 0, ToBearing: 360, Altitude: arinc.Altitude{Feet: 5000}, Radius: 10},
+					},
+				},
+				Center: geo.NewPoint(37.7, -122.25),
+			},
+		},
+	}
+	for _, tt := range []struct {
+		name      string
+		airportID string
+		pt        *geo.Point
+		want      arinc.Altitude
+		wantOK    bool
+	}{
+		{
+			// The true bearing to the center is 180, which is 165 magnetic.
+			name:      "NorthOfCenter",
+			airportID: "KHWD",
+			pt:        geo.NewPoint(37.8, -122.039914),
+			want:      arinc.Altitude{Feet: 4000},
+			wantOK:    true,
+		},
+		{
+			// The true bearing to the center is 010, which is 355 magnetic.
+			name:      "SouthOfCenter",
+			airportID: "KHWD",
+			pt:        geo.NewPoint(37.45, -122.08),
+			want:      arinc.Altitude{Feet: 6000},
+			wantOK:    true,
+		},
+		{
+			name:      "HighestOfOverlappingMSAs",
+			airportID: "KHWD",
+			pt:        geo.NewPoint(37.65, -122.15),
+			want:      arinc.Altitude{Feet: 5000},
+			wantOK:    true,
+		},
+		{
+			name:      "OutsideRadius",
+			airportID: "KHWD",
+			pt:        geo.NewPoint(39, -122.039914),
+		},
+		{
+			name:      "UnknownAirport",
+			airportID: "KOAK",
+			pt:        center,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := msas.Altitude(tt.airportID, tt.pt)
+			if err != nil {
+				t.Fatalf("Altitude() = _, _, %v want _, _, <nil>", err)
+			}
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Altitude() = %v, %t, _ want %v, %t, _", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package enhance
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// overrideSkip is the bearing column value that marks a localizer to be
+// skipped in an overrides file.
+const overrideSkip = "SKIP"
+
+// OverrideEstimatorID is the estimator ID reported for localizers whose
+// bearing was set by an override.
+const OverrideEstimatorID = "OVR"
+
+// Override replaces the computed bearing of a single localizer.
+type Override struct {
+	// Airport is the identifier of the airport the localizer belongs to.
+	Airport string `json:"airport"`
+	// LocalizerID is the identifier of the localizer.
+	LocalizerID string `json:"localizer_id"`
+	// Skip is true if the localizer should be left unenhanced.
+	Skip bool `json:"skip,omitempty"`
+	// TrueBearing is the true bearing to write for the localizer, in the
+	// range [0, 360). It is nil if Skip is true.
+	TrueBearing *float64 `json:"true_bearing,omitempty"`
+}
+
+// localizerKey uniquely identifies a localizer.
+type localizerKey struct {
+	Airport     string
+	LocalizerID string
+}
+
+// ReadOverrides reads localizer overrides in CSV format from r. Each line has
+// three fields: the airport identifier, the localizer identifier, and either a
+// true bearing in degrees or SKIP to leave the localizer unenhanced. Lines
+// that start with # are ignored. For example:
+//
+//	# airport,localizer_id,true_bearing
+//	KBUR,IBUR,90.86
+//	KVNY,IBUR,SKIP
+func ReadOverrides(r io.Reader) ([]Override, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = 3
+	cr.TrimLeadingSpace = true
+	var overrides []Override
+	seen := make(map[localizerKey]bool)
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read overrides: %v", err)
+		}
+		n := len(overrides) + 1
+		o := Override{
+			Airport:     strings.TrimSpace(fields[0]),
+			LocalizerID: strings.TrimSpace(fields[1]),
+		}
+		if o.Airport == "" || o.LocalizerID == "" {
+			return nil, fmt.Errorf("override %d: airport and localizer ID must not be empty", n)
+		}
+		if bearing := strings.TrimSpace(fields[2]); strings.EqualFold(bearing, overrideSkip) {
+			o.Skip = true
+		} else {
+			b, err := strconv.ParseFloat(bearing, 64)
+			if err != nil {
+				return nil, fmt.Errorf("override %d: could not parse bearing: %v", n, err)
+			}
+			if b < 0 || b >= 360 {
+				return nil, fmt.Errorf("override %d: bearing %f is not in the range [0, 360)", n, b)
+			}
+			o.TrueBearing = &b
+		}
+		k := localizerKey{o.Airport, o.LocalizerID}
+		if seen[k] {
+			return nil, fmt.Errorf("override %d: duplicate override for localizer %q at %q", n, o.LocalizerID, o.Airport)
+		}
+		seen[k] = true
+		overrides = append(overrides, o)
+	}
+	return overrides, nil
+}
+
+// BearingOverrides is an option that applies the overrides to the localizers
+// they match. An override takes precedence over the bearing estimators and
+// the deviation policy. Overrides that do not match any localizer in the
+// input data are logged and listed in the report.
+func BearingOverrides(overrides []Override) Option {
+	return func(p *processor) {
+		p.Overrides = make(map[localizerKey]*Override)
+		for i := range overrides {
+			o := overrides[i]
+			p.Overrides[localizerKey{o.Airport, o.LocalizerID}] = &o
+		}
+	}
+}
+
+// findOverride returns the override for the localizer and marks it as used.
+// If there is none, nil is returned.
+func (p *processor) findOverride(airport, localizerID string) *Override {
+	k := localizerKey{airport, localizerID}
+	o, ok := p.Overrides[k]
+	if !ok {
+		return nil
+	}
+	if p.UsedOverrides == nil {
+		p.UsedOverrides = make(map[localizerKey]bool)
+	}
+	p.UsedOverrides[k] = true
+	return o
+}
+
+// reportUnusedOverrides logs every override that did not match a localizer,
+// and adds them to the report, sorted by airport and localizer ID.
+func (p *processor) reportUnusedOverrides() {
+	var unused []Override
+	for k, o := range p.Overrides {
+		if !p.UsedOverrides[k] {
+			unused = append(unused, *o)
+		}
+	}
+	sort.Slice(unused, func(i, j int) bool {
+		if unused[i].Airport != unused[j].Airport {
+			return unused[i].Airport < unused[j].Airport
+		}
+		return unused[i].LocalizerID < unused[j].LocalizerID
+	})
+	for _, o := range unused {
+		log.Printf("Override for localizer %q at %q does not match any localizer.", o.LocalizerID, o.Airport)
+	}
+	if p.Report != nil {
+		p.Report.UnusedOverrides = unused
+	}
+}
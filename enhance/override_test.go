@@ -0,0 +1,158 @@
+package enhance
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadOverrides(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		data    string
+		want    []Override
+		wantErr bool
+	}{
+		{
+			name: "Good",
+			data: "# airport,localizer_id,true_bearing\n" +
+				"KBUR,IBUR,90.86\n" +
+				"KVNY, IBUR, skip\n",
+			want: []Override{
+				{Airport: "KBUR", LocalizerID: "IBUR", TrueBearing: floatPtr(90.86)},
+				{Airport: "KVNY", LocalizerID: "IBUR", Skip: true},
+			},
+		},
+		{
+			name: "North",
+			data: "KSFO,ISFO,0\n",
+			want: []Override{
+				{Airport: "KSFO", LocalizerID: "ISFO", TrueBearing: floatPtr(0)},
+			},
+		},
+		{
+			name: "Empty",
+			data: "",
+		},
+		{
+			name:    "WrongFieldCount",
+			data:    "KBUR,IBUR\n",
+			wantErr: true,
+		},
+		{
+			name:    "EmptyLocalizerID",
+			data:    "KBUR,,90.86\n",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidBearing",
+			data:    "KBUR,IBUR,ABC\n",
+			wantErr: true,
+		},
+		{
+			name:    "BearingOutOfRange",
+			data:    "KBUR,IBUR,360\n",
+			wantErr: true,
+		},
+		{
+			name:    "Duplicate",
+			data:    "KBUR,IBUR,90.86\nKBUR,IBUR,SKIP\n",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadOverrides(strings.NewReader(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ReadOverrides() = _, <nil> want _, <non-nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadOverrides() = _, %v want _, <nil>", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ReadOverrides() had diffs (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestProcessWithOverrides(t *testing.T) {
+	testData, err := ioutil.ReadFile("test_data_locduplicates.txt")
+	if err != nil {
+		t.Fatalf("Could not read test data file: %v", err)
+	}
+	overrides := []Override{
+		{Airport: "KBUR", LocalizerID: "IBUR", TrueBearing: floatPtr(91.5)},
+		{Airport: "KVNY", LocalizerID: "IVNY", Skip: true},
+		{Airport: "KXYZ", LocalizerID: "IXYZ", TrueBearing: floatPtr(10)},
+		{Airport: "KVNY", LocalizerID: "IBUR", TrueBearing: floatPtr(90.9)},
+	}
+	var out bytes.Buffer
+	report, err := ProcessWithReport(bytes.NewReader(testData), &out, RemoveDuplicateLocalizers(true), BearingOverrides(overrides))
+	if err != nil {
+		t.Fatalf("ProcessWithReport() = _, %v want _, <nil>", err)
+	}
+
+	wantLines := []string{
+		"SUSAP KBURK2IIBUR1   2S                            09150O                   ",
+	}
+	for _, l := range wantLines {
+		if !strings.Contains(out.String(), l) {
+			t.Errorf("Process() output is missing line %q", l)
+		}
+	}
+	if strings.Contains(out.String(), "SUSAP KVNYK2IIVNY1   2S") {
+		t.Errorf("Process() output has a continuation record for skipped localizer IVNY")
+	}
+
+	got := make(map[string]string)
+	for _, l := range report.Localizers {
+		got[l.Airport+" "+l.LocalizerID] = l.Estimator + "/" + l.SkipReason
+	}
+	want := map[string]string{
+		"KBUR IBUR": "OVR/",
+		"KVNY IBUR": "/removed duplicate LDA localizer",
+		"KVNY IVNY": "/localizer is skipped by an override",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ProcessWithReport() localizers had diffs (-want +got): %s", diff)
+	}
+	if diff := cmp.Diff([]Override{overrides[2]}, report.UnusedOverrides); diff != "" {
+		t.Errorf("ProcessWithReport() unused overrides had diffs (-want +got): %s", diff)
+	}
+}
+
+func TestOverrideJSON(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		override Override
+		want     string
+	}{
+		{
+			name:     "North",
+			override: Override{Airport: "KSFO", LocalizerID: "ISFO", TrueBearing: floatPtr(0)},
+			want:     `{"airport":"KSFO","localizer_id":"ISFO","true_bearing":0}`,
+		},
+		{
+			name:     "Skip",
+			override: Override{Airport: "KVNY", LocalizerID: "IBUR", Skip: true},
+			want:     `{"airport":"KVNY","localizer_id":"IBUR","skip":true}`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.override)
+			if err != nil {
+				t.Fatalf("json.Marshal() = _, %v want _, <nil>", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s want %s", got, tt.want)
+			}
+		})
+	}
+}
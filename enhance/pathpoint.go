@@ -0,0 +1,63 @@
+package enhance
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+)
+
+// PathPointReport describes a path point whose FAS data block CRC does not
+// match the published CRC. Such a path point should not be used for an
+// approach.
+type PathPointReport struct {
+	// Airport is the identifier of the airport or heliport of the approach.
+	Airport string `json:"airport"`
+	// Approach is the identifier of the approach procedure.
+	Approach string `json:"approach"`
+	// Runway is the runway or helipad of the approach.
+	Runway string `json:"runway"`
+	// ReferencePathID is the identifier of the final approach segment.
+	ReferencePathID string `json:"reference_path_id"`
+	// PublishedCRC is the CRC in the record.
+	PublishedCRC string `json:"published_crc"`
+	// ComputedCRC is the CRC of the FAS data block in hexadecimal, or empty
+	// if it could not be computed.
+	ComputedCRC string `json:"computed_crc,omitempty"`
+	// Reason explains why the path point failed validation.
+	Reason string `json:"reason"`
+}
+
+// ValidatePathPoints is an option that enables or disables validation of the
+// CRC of every path point record. Path points whose CRC does not match their
+// FAS data block are logged and listed in the report. They are written
+// unchanged either way.
+func ValidatePathPoints(enabled bool) Option {
+	return func(p *processor) {
+		p.ValidatePathPoints = enabled
+	}
+}
+
+// validatePathPoint checks the CRC of the path point record, and reports the
+// path point if it does not match.
+func (p *processor) validatePathPoint(rec *arinc.AirportPathPointPrimaryRecord) {
+	err := rec.ValidateCRC()
+	if err == nil {
+		return
+	}
+	r := &PathPointReport{
+		Airport:         rec.AirportID,
+		Approach:        rec.ApproachProcedureID,
+		Runway:          rec.RunwayOrHelipadID,
+		ReferencePathID: rec.ReferencePathID,
+		PublishedCRC:    rec.FASDataCRCRemainder,
+		Reason:          err.Error(),
+	}
+	if crc, err := rec.FASDataCRC(); err == nil {
+		r.ComputedCRC = fmt.Sprintf("%08X", crc)
+	}
+	log.Printf("Path point for %q %q at %q is corrupt: %s", r.Approach, r.Runway, r.Airport, r.Reason)
+	if p.Report != nil {
+		p.Report.CorruptPathPoints = append(p.Report.CorruptPathPoints, r)
+	}
+}
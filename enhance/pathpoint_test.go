@@ -0,0 +1,56 @@
+package enhance
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidatePathPoints(t *testing.T) {
+	in := strings.Join([]string{
+		"SUSAP KHWDK2PR28L  RW28L001A0001W28A0N3739450000W12207300000+001180300N3730000000W12207300000106050008000550F400500B0B82C38123452002",
+		"SUSAP KHWDK2PR28L  RW28L002E      +00118+00118LPV       21473                                                              123462002",
+		"SUSAP KHWDK2PR28R  RW28R001A0001W28B0N3739450000W12207300000+001180310N3730000000W12207300000106050008000550F400500078B2ED2123472002",
+		"SUSAP KHWDK2PR28R  RW28R002E      +00118+00118LPV       21474                                                              123482002",
+	}, "\n")
+	for _, tt := range []struct {
+		name string
+		opts []Option
+		want []*PathPointReport
+	}{
+		{
+			name: "Disabled",
+		},
+		{
+			name: "Enabled",
+			opts: []Option{ValidatePathPoints(true)},
+			want: []*PathPointReport{
+				{
+					Airport:         "KHWD",
+					Approach:        "R28R",
+					Runway:          "RW28R",
+					ReferencePathID: "W28B",
+					PublishedCRC:    "078B2ED2",
+					ComputedCRC:     "22F09C92",
+					Reason:          "published CRC 078B2ED2 does not match computed CRC 22F09C92",
+				},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			report, err := ProcessWithReport(strings.NewReader(in), &out, tt.opts...)
+			if err != nil {
+				t.Fatalf("ProcessWithReport() = _, %v want _, <nil>", err)
+			}
+			if diff := cmp.Diff(in+"\n", out.String()); diff != "" {
+				t.Errorf("ProcessWithReport() out content not as expected (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, report.CorruptPathPoints); diff != "" {
+				t.Errorf("ProcessWithReport() corrupt path points had diffs (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package enhance
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// LocalizerReport describes the outcome of enhancing a single localizer.
+type LocalizerReport struct {
+	// Airport is the identifier of the airport the localizer belongs to.
+	Airport string `json:"airport"`
+	// LocalizerID is the identifier of the localizer.
+	LocalizerID string `json:"localizer_id"`
+	// Category is the ILS category (or LDA, SDF, etc.) of the localizer.
+	Category string `json:"category"`
+	// PublishedBearing is the bearing as it appears in the localizer record.
+	PublishedBearing string `json:"published_bearing"`
+	// OldTrueBearing is the published bearing converted to a true bearing,
+	// or nil if the published bearing could not be converted.
+	OldTrueBearing *float64 `json:"old_true_bearing,omitempty"`
+	// ComputedTrueBearing is the new true bearing written to the simulation
+	// continuation record. If the published deviation policy was applied, it
+	// is the old true bearing.
+	ComputedTrueBearing float64 `json:"computed_true_bearing"`
+	// Delta is the signed difference from the old to the computed true
+	// bearing in degrees, in the range (-180, 180]. The deviation threshold
+	// applies to its absolute value. It is zero if OldTrueBearing is nil.
+	Delta float64 `json:"delta"`
+	// FinalApproachFix is the final approach fix of the approach that uses
+	// the localizer, if any.
+	FinalApproachFix string `json:"final_approach_fix"`
+	// Estimator is the ID of the estimator that computed the bearing, even if
+	// the bearing was then rejected by the deviation policy.
+	Estimator string `json:"estimator"`
+	// Deviation is the deviation policy that was applied because the
+	// computed bearing exceeded the deviation threshold, or empty if it did
+	// not.
+	Deviation string `json:"deviation,omitempty"`
+	// SkipReason explains why the localizer was not enhanced. It is empty if
+	// the localizer was enhanced.
+	SkipReason string `json:"skip_reason,omitempty"`
+}
+
+// Enhanced returns true if the localizer was enhanced with a new bearing.
+func (l *LocalizerReport) Enhanced() bool {
+	return l.SkipReason == ""
+}
+
+// Report is a summary of the changes made while processing ARINC data.
+type Report struct {
+	// Localizers lists every localizer in the input data in the order that it
+	// was encountered.
+	Localizers []*LocalizerReport `json:"localizers"`
+	// Deviations counts the localizers whose computed bearing exceeded the
+	// deviation threshold, by the decision that was made for them.
+	Deviations DeviationCounts `json:"deviations"`
+	// UnusedOverrides lists the overrides that did not match any localizer
+	// in the input data.
+	UnusedOverrides []Override `json:"unused_overrides,omitempty"`
+	// CorruptPathPoints lists the path points whose FAS data block CRC does
+	// not match the published CRC, if path point validation is enabled.
+	CorruptPathPoints []*PathPointReport `json:"corrupt_path_points,omitempty"`
+}
+
+// ReportTo is an option that records the outcome of processing into r.
+func ReportTo(r *Report) Option {
+	return func(p *processor) {
+		p.Report = r
+	}
+}
+
+// ProcessWithReport is like Process, but also returns a report of every
+// localizer that was processed. If an error occurs, the report describes the
+// data processed up to that point.
+func ProcessWithReport(in io.ReadSeeker, out io.Writer, opts ...Option) (*Report, error) {
+	r := &Report{}
+	// The options are copied so that the caller's slice is not modified.
+	opts = append(append([]Option(nil), opts...), ReportTo(r))
+	err := Process(in, out, opts...)
+	return r, err
+}
+
+// WriteJSON writes the report to w as an indented JSON object.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("could not encode report: %v", err)
+	}
+	return nil
+}
+
+// csvHeader is the header row written by WriteCSV.
+var csvHeader = []string{
+	"airport",
+	"localizer_id",
+	"category",
+	"published_bearing",
+	"old_true_bearing",
+	"computed_true_bearing",
+	"delta",
+	"final_approach_fix",
+	"estimator",
+	"deviation",
+	"skip_reason",
+}
+
+// csvPathPointHeader is the header row of the corrupt path point section
+// written by WriteCSV.
+var csvPathPointHeader = []string{
+	"path_point_airport",
+	"approach",
+	"runway",
+	"reference_path_id",
+	"published_crc",
+	"computed_crc",
+	"reason",
+}
+
+// WriteCSV writes the report to w as CSV with one row per localizer. The
+// computed bearing and delta are left empty for localizers that were not
+// enhanced, and the old bearing and delta are left empty if the published
+// bearing could not be converted. If there are corrupt path points, they
+// follow in a section of their own after an empty row, with one row per path
+// point.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("could not write report: %v", err)
+	}
+	formatBearing := func(b float64) string {
+		return strconv.FormatFloat(b, 'f', 4, 64)
+	}
+	for _, l := range r.Localizers {
+		var old, computed, delta string
+		if l.OldTrueBearing != nil {
+			old = formatBearing(*l.OldTrueBearing)
+		}
+		if l.Enhanced() {
+			computed = formatBearing(l.ComputedTrueBearing)
+			if l.OldTrueBearing != nil {
+				delta = formatBearing(l.Delta)
+			}
+		}
+		row := []string{
+			l.Airport,
+			l.LocalizerID,
+			l.Category,
+			l.PublishedBearing,
+			old,
+			computed,
+			delta,
+			l.FinalApproachFix,
+			l.Estimator,
+			l.Deviation,
+			l.SkipReason,
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("could not write report: %v", err)
+		}
+	}
+	if len(r.CorruptPathPoints) > 0 {
+		if err := cw.Write(nil); err != nil {
+			return fmt.Errorf("could not write report: %v", err)
+		}
+		if err := cw.Write(csvPathPointHeader); err != nil {
+			return fmt.Errorf("could not write report: %v", err)
+		}
+	}
+	for _, pp := range r.CorruptPathPoints {
+		row := []string{
+			pp.Airport,
+			pp.Approach,
+			pp.Runway,
+			pp.ReferencePathID,
+			pp.PublishedCRC,
+			pp.ComputedCRC,
+			pp.Reason,
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("could not write report: %v", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("could not write report: %v", err)
+	}
+	return nil
+}
+
+// bearingDelta returns the signed difference from one bearing to another in
+// degrees, in the range (-180, 180].
+func bearingDelta(from, to float64) float64 {
+	d := math.Mod(to-from, 360)
+	if d <= -180 {
+		d += 360
+	} else if d > 180 {
+		d -= 360
+	}
+	return d
+}
@@ -0,0 +1,308 @@
+package enhance
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProcessWithReport(t *testing.T) {
+	testData, err := ioutil.ReadFile("test_data_locduplicates.txt")
+	if err != nil {
+		t.Fatalf("Could not read test data file: %v", err)
+	}
+	var out bytes.Buffer
+	got, err := ProcessWithReport(bytes.NewReader(testData), &out, RemoveDuplicateLocalizers(true))
+	if err != nil {
+		t.Fatalf("ProcessWithReport() = _, %v want _, <nil>", err)
+	}
+	want := &Report{
+		Localizers: []*LocalizerReport{
+			{
+				Airport:             "KBUR",
+				LocalizerID:         "IBUR",
+				Category:            "1",
+				PublishedBearing:    "0789",
+				OldTrueBearing:      floatPtr(90.9),
+				ComputedTrueBearing: 90.86,
+				Delta:               -0.04,
+				FinalApproachFix:    "BUDDE",
+				Estimator:           "FAF",
+			},
+			{
+				Airport:          "KVNY",
+				LocalizerID:      "IBUR",
+				Category:         "A",
+				PublishedBearing: "0789",
+				OldTrueBearing:   floatPtr(90.9),
+				SkipReason:       "removed duplicate LDA localizer",
+			},
+			{
+				Airport:             "KVNY",
+				LocalizerID:         "IVNY",
+				Category:            "1",
+				PublishedBearing:    "1635",
+				OldTrueBearing:      floatPtr(175.5),
+				ComputedTrueBearing: 175.50,
+				Delta:               0,
+				FinalApproachFix:    "FURRY",
+				Estimator:           "FAF",
+			},
+		},
+		Deviations: DeviationCounts{Checked: 2},
+	}
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(x, y float64) bool { return math.Abs(x-y) < 0.01 })); diff != "" {
+		t.Errorf("ProcessWithReport() report had diffs (-want +got): %s", diff)
+	}
+}
+
+func TestProcessWithReportDoesNotModifyOptions(t *testing.T) {
+	testData, err := ioutil.ReadFile("test_data_locduplicates.txt")
+	if err != nil {
+		t.Fatalf("Could not read test data file: %v", err)
+	}
+	called := false
+	opts := make([]Option, 1, 2)
+	opts[0] = RemoveDuplicateLocalizers(true)
+	spare := opts[:2]
+	spare[1] = func(*processor) { called = true }
+	if _, err := ProcessWithReport(bytes.NewReader(testData), ioutil.Discard, opts...); err != nil {
+		t.Fatalf("ProcessWithReport() = _, %v want _, <nil>", err)
+	}
+	spare[1](&processor{})
+	if !called {
+		t.Errorf("ProcessWithReport() modified the spare capacity of the options")
+	}
+}
+
+var testReport = &Report{
+	Localizers: []*LocalizerReport{
+		{
+			Airport:             "KHWD",
+			LocalizerID:         "IHWD",
+			Category:            "0",
+			PublishedBearing:    "2879",
+			OldTrueBearing:      floatPtr(302.9),
+			ComputedTrueBearing: 302.94,
+			Delta:               0.04,
+			FinalApproachFix:    "FERNE",
+			Estimator:           "FAF",
+		},
+		{
+			Airport:          "KSAC",
+			LocalizerID:      "ISAC",
+			Category:         "1",
+			PublishedBearing: "0191",
+			OldTrueBearing:   floatPtr(33.1),
+			FinalApproachFix: "SAC",
+			SkipReason:       "no estimator could compute a bearing",
+		},
+		{
+			Airport:             "KVNY",
+			LocalizerID:         "IBUR",
+			Category:            "A",
+			PublishedBearing:    "0789",
+			OldTrueBearing:      floatPtr(90.9),
+			ComputedTrueBearing: 90.9,
+			Delta:               -11.1,
+			FinalApproachFix:    "BUDDE",
+			Estimator:           "FAF",
+			Deviation:           "published",
+		},
+		{
+			Airport:             "PHNL",
+			LocalizerID:         "IIUM",
+			Category:            "1",
+			PublishedBearing:    "BAD",
+			ComputedTrueBearing: 79.5,
+			FinalApproachFix:    "MKK",
+			Estimator:           "FAF",
+		},
+	},
+	Deviations: DeviationCounts{Checked: 3, Published: 1},
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestReportWriteJSON(t *testing.T) {
+	var got bytes.Buffer
+	if err := testReport.WriteJSON(&got); err != nil {
+		t.Fatalf("WriteJSON() = %v want <nil>", err)
+	}
+	want := `{
+  "localizers": [
+    {
+      "airport": "KHWD",
+      "localizer_id": "IHWD",
+      "category": "0",
+      "published_bearing": "2879",
+      "old_true_bearing": 302.9,
+      "computed_true_bearing": 302.94,
+      "delta": 0.04,
+      "final_approach_fix": "FERNE",
+      "estimator": "FAF"
+    },
+    {
+      "airport": "KSAC",
+      "localizer_id": "ISAC",
+      "category": "1",
+      "published_bearing": "0191",
+      "old_true_bearing": 33.1,
+      "computed_true_bearing": 0,
+      "delta": 0,
+      "final_approach_fix": "SAC",
+      "estimator": "",
+      "skip_reason": "no estimator could compute a bearing"
+    },
+    {
+      "airport": "KVNY",
+      "localizer_id": "IBUR",
+      "category": "A",
+      "published_bearing": "0789",
+      "old_true_bearing": 90.9,
+      "computed_true_bearing": 90.9,
+      "delta": -11.1,
+      "final_approach_fix": "BUDDE",
+      "estimator": "FAF",
+      "deviation": "published"
+    },
+    {
+      "airport": "PHNL",
+      "localizer_id": "IIUM",
+      "category": "1",
+      "published_bearing": "BAD",
+      "computed_true_bearing": 79.5,
+      "delta": 0,
+      "final_approach_fix": "MKK",
+      "estimator": "FAF"
+    }
+  ],
+  "deviations": {
+    "checked": 3,
+    "kept": 0,
+    "published": 1,
+    "dropped": 0
+  }
+}
+`
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("WriteJSON() had diffs (-want +got): %s", diff)
+	}
+}
+
+func TestReportWriteCSV(t *testing.T) {
+	localizerRows := "airport,localizer_id,category,published_bearing,old_true_bearing,computed_true_bearing,delta,final_approach_fix,estimator,deviation,skip_reason\n" +
+		"KHWD,IHWD,0,2879,302.9000,302.9400,0.0400,FERNE,FAF,,\n" +
+		"KSAC,ISAC,1,0191,33.1000,,,SAC,,,no estimator could compute a bearing\n" +
+		"KVNY,IBUR,A,0789,90.9000,90.9000,-11.1000,BUDDE,FAF,published,\n" +
+		"PHNL,IIUM,1,BAD,,79.5000,,MKK,FAF,,\n"
+	withPathPoints := *testReport
+	withPathPoints.CorruptPathPoints = []*PathPointReport{
+		{
+			Airport:         "KHWD",
+			Approach:        "R28L",
+			Runway:          "RW28L",
+			ReferencePathID: "W28A",
+			PublishedCRC:    "00000000",
+			ComputedCRC:     "8B6F3A9C",
+			Reason:          "FAS data CRC 00000000 does not match computed CRC 8B6F3A9C",
+		},
+		{
+			Airport:         "KSAC",
+			Approach:        "R02",
+			Runway:          "RW02",
+			ReferencePathID: "W02A",
+			PublishedCRC:    "ZZZZZZZZ",
+			Reason:          "invalid FAS data CRC \"ZZZZZZZZ\"",
+		},
+	}
+	for _, tt := range []struct {
+		name   string
+		report *Report
+		want   string
+	}{
+		{
+			name:   "Localizers",
+			report: testReport,
+			want:   localizerRows,
+		},
+		{
+			name:   "CorruptPathPoints",
+			report: &withPathPoints,
+			want: localizerRows +
+				"\n" +
+				"path_point_airport,approach,runway,reference_path_id,published_crc,computed_crc,reason\n" +
+				"KHWD,R28L,RW28L,W28A,00000000,8B6F3A9C,FAS data CRC 00000000 does not match computed CRC 8B6F3A9C\n" +
+				"KSAC,R02,RW02,W02A,ZZZZZZZZ,,\"invalid FAS data CRC \"\"ZZZZZZZZ\"\"\"\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bytes.Buffer
+			if err := tt.report.WriteCSV(&got); err != nil {
+				t.Fatalf("WriteCSV() = %v want <nil>", err)
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("WriteCSV() had diffs (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestReportWriteBadWriter(t *testing.T) {
+	if err := testReport.WriteJSON(&badWriter{}); err == nil {
+		t.Errorf("WriteJSON() = <nil> want <non-nil>")
+	}
+	if err := testReport.WriteCSV(&badWriter{}); err == nil {
+		t.Errorf("WriteCSV() = <nil> want <non-nil>")
+	}
+}
+
+func TestBearingDelta(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		from, to float64
+		want     float64
+	}{
+		{
+			name: "Clockwise",
+			from: 10,
+			to:   15,
+			want: 5,
+		},
+		{
+			name: "CounterClockwise",
+			from: 15,
+			to:   10,
+			want: -5,
+		},
+		{
+			name: "AcrossNorthClockwise",
+			from: 358,
+			to:   2,
+			want: 4,
+		},
+		{
+			name: "AcrossNorthCounterClockwise",
+			from: 2,
+			to:   358,
+			want: -4,
+		},
+		{
+			name: "Opposite",
+			from: 0,
+			to:   180,
+			want: 180,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bearingDelta(tt.from, tt.to); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("bearingDelta(%f, %f) = %f want %f", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package enhance
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+)
+
+// defaultStreamBufferLimit is the stream buffer limit in bytes used if none is
+// specified.
+const defaultStreamBufferLimit = 16 << 20
+
+// StreamBufferLimit is an option that sets the maximum number of bytes of an
+// airport's output that ProcessStream holds back while waiting to find out
+// whether an LDA localizer is a duplicate. If the limit is exceeded, the held
+// output is written and the LDA localizers in it are kept. A limit of zero or
+// less selects the default of 16 MiB.
+func StreamBufferLimit(bytes int) Option {
+	return func(p *processor) {
+		p.StreamBufferLimit = bytes
+	}
+}
+
+// ProcessStream is like Process, but reads the data in a single pass, so it
+// can be used with input that cannot be rewound, such as a pipe.
+//
+// Terminal NDB and localizer marker records come after the localizers of their
+// airport in the data, so the localizers of an airport are not processed
+// until the records of the next airport start. Output from the first
+// localizer of an airport onwards is held in memory until then.
+//
+// If duplicate localizer removal is enabled, whether an LDA localizer is a
+// duplicate is not known until a localizer with the same ID appears later in
+// the data. An LDA localizer is removed if its duplicate belongs to the same
+// airport or an earlier one. An LDA localizer whose duplicate belongs to a
+// later airport is kept, and a warning is logged when the duplicate is found.
+//
+// The memory used is bounded by StreamBufferLimit.
+func ProcessStream(in io.Reader, out io.Writer, opts ...Option) error {
+	p := newProcessor(opts...)
+	limit := p.StreamBufferLimit
+	if limit <= 0 {
+		limit = defaultStreamBufferLimit
+	}
+	w := &streamWriter{out: out, limit: limit, process: p.processDeferred}
+
+	s := bufio.NewScanner(in)
+	for line := 1; s.Scan(); line++ {
+		if err := w.startRecord(recordAirport(s.Bytes())); err != nil {
+			return err
+		}
+		loc, err := parseLocalizer(s.Bytes())
+		if err == nil && loc != nil {
+			if p.RemoveDuplicateLocalizers {
+				p.collectLocalizer(loc)
+				if p.DuplicateLocalizers[loc.LocalizerID] {
+					w.checkWritten(loc.LocalizerID)
+				}
+			}
+			// The scanner reuses its buffer, so the record is copied.
+			c := streamChunk{record: append([]byte(nil), s.Bytes()...), line: line, loc: loc}
+			if err := w.hold(c); err != nil {
+				return err
+			}
+			continue
+		}
+		var processed []byte
+		if err == nil {
+			processed, err = p.processRecord(s.Bytes())
+		}
+		if err != nil {
+			if processed, err = p.recordFailed(line, s.Bytes(), err); err != nil {
+				return err
+			}
+		}
+		if err := w.write(processed); err != nil {
+			return err
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("problem parsing data: %v", err)
+	}
+	if err := w.flush(); err != nil {
+		return err
+	}
+	p.reportUnusedOverrides()
+	p.reportDeviations()
+	p.resolveHoldings()
+	p.resolveMSAs()
+	p.resolveTAAs()
+	return p.recordErrors()
+}
+
+// processDeferred processes a localizer record whose processing was deferred
+// until the end of its airport.
+func (p *processor) processDeferred(line int, recordBytes []byte) ([]byte, error) {
+	processed, err := p.processRecord(recordBytes)
+	if err != nil {
+		return p.recordFailed(line, recordBytes, err)
+	}
+	return processed, nil
+}
+
+// recordAirport returns the airport or heliport identifier and ICAO code of
+// the record, or an empty string if the record does not belong to an airport
+// or heliport.
+func recordAirport(recordBytes []byte) string {
+	section, _, err := arinc.SectionAndSubsection(recordBytes)
+	if err != nil || (section != arinc.SectionCodeAirport && section != arinc.SectionCodeHeliport) {
+		return ""
+	}
+	return string(recordBytes[6:12])
+}
+
+// parseLocalizer returns the localizer record if the record is an airport
+// localizer record, or nil otherwise. Other records are not parsed.
+func parseLocalizer(recordBytes []byte) (*arinc.AirportLocGSPrimaryRecord, error) {
+	section, subsection, err := arinc.SectionAndSubsection(recordBytes)
+	if err != nil || section != arinc.SectionCodeAirport || subsection != arinc.SubsectionCodeLocGS {
+		return nil, nil
+	}
+	rec, err := arinc.Parse(recordBytes)
+	if err != nil {
+		return nil, err
+	}
+	loc, _ := rec.(*arinc.AirportLocGSPrimaryRecord)
+	return loc, nil
+}
+
+// streamChunk is the output of a single input record held by a streamWriter.
+type streamChunk struct {
+	data []byte
+	// record is the localizer record whose processing is deferred, or nil if
+	// the chunk has already been processed into data.
+	record []byte
+	line   int
+	loc    *arinc.AirportLocGSPrimaryRecord
+}
+
+// size returns the number of bytes held for the chunk.
+func (c streamChunk) size() int {
+	return len(c.data) + len(c.record)
+}
+
+// streamWriter writes processed records to out, holding back output from the
+// first localizer of an airport onwards until the airport ends.
+type streamWriter struct {
+	out    io.Writer
+	limit  int
+	chunks []streamChunk
+	size   int
+	// process processes a deferred localizer record.
+	process func(line int, recordBytes []byte) ([]byte, error)
+	// airport is the airport of the most recent record, as returned by
+	// recordAirport.
+	airport string
+	// written holds the LDA localizers that were written before they could
+	// be resolved, by localizer ID.
+	written map[string]*arinc.AirportLocGSPrimaryRecord
+}
+
+// startRecord processes the deferred localizers and writes all held output if
+// the record belongs to a different airport than the previous one.
+func (w *streamWriter) startRecord(airport string) error {
+	if airport == w.airport {
+		return nil
+	}
+	w.airport = airport
+	return w.flush()
+}
+
+// write writes data, or holds it if there are deferred localizers.
+func (w *streamWriter) write(data []byte) error {
+	if len(w.chunks) == 0 {
+		return w.writeOut(data)
+	}
+	return w.hold(streamChunk{data: data})
+}
+
+// hold holds c, and flushes the held output if the limit is exceeded.
+func (w *streamWriter) hold(c streamChunk) error {
+	w.chunks = append(w.chunks, c)
+	w.size += c.size()
+	if w.size <= w.limit {
+		return nil
+	}
+	for _, c := range w.chunks {
+		if c.record != nil {
+			log.Printf("Processing localizer %q at %q before the rest of its airport is read because the stream buffer limit was reached.", c.loc.LocalizerID, c.loc.AirportID)
+		}
+	}
+	return w.flush()
+}
+
+// checkWritten logs a warning if an LDA localizer with the given ID was
+// already written, since it can no longer be removed as a duplicate.
+func (w *streamWriter) checkWritten(localizerID string) {
+	if loc, ok := w.written[localizerID]; ok {
+		log.Printf("Keeping duplicate localizer LDA facility %q at %q because it was already written when its duplicate was found.", loc.LocalizerID, loc.AirportID)
+		delete(w.written, localizerID)
+	}
+}
+
+// flush processes the deferred localizers and writes all held output. The LDA
+// localizers that are kept are remembered, so that a warning can be logged if
+// their duplicate is found later.
+func (w *streamWriter) flush() error {
+	for _, c := range w.chunks {
+		data := c.data
+		if c.record != nil {
+			var err error
+			if data, err = w.process(c.line, c.record); err != nil {
+				return err
+			}
+			if len(data) > 0 && isLDA(c.loc) {
+				if w.written == nil {
+					w.written = make(map[string]*arinc.AirportLocGSPrimaryRecord)
+				}
+				w.written[c.loc.LocalizerID] = c.loc
+			}
+		}
+		if err := w.writeOut(data); err != nil {
+			return err
+		}
+	}
+	w.chunks = nil
+	w.size = 0
+	return nil
+}
+
+// writeOut writes data to out.
+func (w *streamWriter) writeOut(data []byte) error {
+	if _, err := w.out.Write(data); err != nil {
+		return fmt.Errorf("could not write processed data: %v", err)
+	}
+	return nil
+}
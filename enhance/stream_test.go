@@ -0,0 +1,206 @@
+package enhance
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProcessStreamCompleteFile(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		inFile      string
+		options     []Option
+		wantOutFile string
+	}{
+		{
+			name:        "Basic",
+			inFile:      "test_data.txt",
+			wantOutFile: "test_data_out.txt",
+		},
+		{
+			name:        "LocDuplicatesDoNotRemove",
+			inFile:      "test_data_locduplicates.txt",
+			wantOutFile: "test_data_locduplicates_donotremove_out.txt",
+		},
+		{
+			name:        "LocDuplicatesRemove",
+			inFile:      "test_data_locduplicates.txt",
+			options:     []Option{RemoveDuplicateLocalizers(true)},
+			wantOutFile: "test_data_locduplicates_remove_out.txt",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			testData, err := ioutil.ReadFile(tt.inFile)
+			if err != nil {
+				t.Fatalf("Could not read test data file: %v", err)
+			}
+			want, err := ioutil.ReadFile(tt.wantOutFile)
+			if err != nil {
+				t.Fatalf("Could not read test data file: %v", err)
+			}
+
+			var got bytes.Buffer
+			if err := ProcessStream(bytes.NewBuffer(testData), &got, tt.options...); err != nil {
+				t.Fatalf("ProcessStream() = %v want <nil>", err)
+			}
+			if diff := cmp.Diff(want, got.Bytes()); diff != "" {
+				t.Errorf("ProcessStream() out content not as expected: %s", diff)
+			}
+		})
+	}
+}
+
+// cutLine returns data without the line that starts with prefix, and the
+// line that was removed.
+func cutLine(t *testing.T, data, prefix string) (string, string) {
+	t.Helper()
+	var rest []string
+	var cut string
+	for _, l := range strings.SplitAfter(data, "\n") {
+		if cut == "" && strings.HasPrefix(l, prefix) {
+			cut = l
+			continue
+		}
+		rest = append(rest, l)
+	}
+	if cut == "" {
+		t.Fatalf("Could not find line starting with %q", prefix)
+	}
+	return strings.Join(rest, ""), cut
+}
+
+// insertLine returns data with line inserted before the line that starts with
+// prefix.
+func insertLine(t *testing.T, data, line, prefix string) string {
+	t.Helper()
+	i := strings.Index(data, "\n"+prefix)
+	if i < 0 {
+		t.Fatalf("Could not find line starting with %q", prefix)
+	}
+	return data[:i+1] + line + data[i+1:]
+}
+
+func TestProcessStreamDuplicateAfterLDA(t *testing.T) {
+	testData, err := ioutil.ReadFile("test_data_locduplicates.txt")
+	if err != nil {
+		t.Fatalf("Could not read test data file: %v", err)
+	}
+	removed, err := ioutil.ReadFile("test_data_locduplicates_remove_out.txt")
+	if err != nil {
+		t.Fatalf("Could not read test data file: %v", err)
+	}
+	// Move the KVNY LDA localizer in front of the KBUR localizer that it
+	// duplicates, so that it is not known to be a duplicate when it is read.
+	// It is also before the KVNY airport record, so it is not enhanced.
+	otherAirport, lda := cutLine(t, string(testData), "SUSAP KVNYK2IIBURA")
+	otherAirport = insertLine(t, otherAirport, lda, "SUSAP KBURK2IIBUR1   0")
+	// Move it to KBUR as well, so that it is resolved within the airport.
+	sameAirportLDA := strings.Replace(lda, "KVNY", "KBUR", 1)
+	sameAirport := strings.Replace(otherAirport, lda, sameAirportLDA, 1)
+	// Once it is at KBUR, it is enhanced if it is kept.
+	sameAirportLDAOut := "SUSAP KBURK2IIBURA   110950RW34LN34115264W1182220920789                   1007+    0500   E0120                            296871905\n" +
+		"SUSAP KBURK2IIBURA   2S                            09086F                                                                  296871905\n"
+
+	for _, tt := range []struct {
+		name    string
+		in      string
+		options []Option
+		want    string
+	}{
+		{
+			name:    "SameAirportRemoved",
+			in:      sameAirport,
+			options: []Option{RemoveDuplicateLocalizers(true)},
+			want:    string(removed),
+		},
+		{
+			name:    "OtherAirportKept",
+			in:      otherAirport,
+			options: []Option{RemoveDuplicateLocalizers(true)},
+			want:    insertLine(t, string(removed), lda, "SUSAP KBURK2IIBUR1   1"),
+		},
+		{
+			name:    "BufferLimitExceeded",
+			in:      sameAirport,
+			options: []Option{RemoveDuplicateLocalizers(true), StreamBufferLimit(1)},
+			want:    insertLine(t, string(removed), sameAirportLDAOut, "SUSAP KBURK2IIBUR1   1"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bytes.Buffer
+			if err := ProcessStream(strings.NewReader(tt.in), &got, tt.options...); err != nil {
+				t.Fatalf("ProcessStream() = %v want <nil>", err)
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("ProcessStream() out content not as expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestProcessStreamReport(t *testing.T) {
+	testData, err := ioutil.ReadFile("test_data_locduplicates.txt")
+	if err != nil {
+		t.Fatalf("Could not read test data file: %v", err)
+	}
+	want, err := ProcessWithReport(bytes.NewReader(testData), ioutil.Discard, RemoveDuplicateLocalizers(true))
+	if err != nil {
+		t.Fatalf("ProcessWithReport() = _, %v want _, <nil>", err)
+	}
+	got := &Report{}
+	if err := ProcessStream(bytes.NewBuffer(testData), ioutil.Discard, RemoveDuplicateLocalizers(true), ReportTo(got)); err != nil {
+		t.Fatalf("ProcessStream() = %v want <nil>", err)
+	}
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(x, y float64) bool { return math.Abs(x-y) < 0.00001 })); diff != "" {
+		t.Errorf("ProcessStream() report had diffs (-want +got): %s", diff)
+	}
+}
+
+func TestProcessStreamBadWriter(t *testing.T) {
+	in := strings.NewReader("SUSAP KHWDK2CBOGRE K20    W     N37372195W122023769                       E0133     NAR           BOGRE                    107992002")
+	if err := ProcessStream(in, &badWriter{}); err == nil {
+		t.Fatalf("ProcessStream() = <nil> want <non-nil>")
+	}
+}
+
+func TestRecordAirport(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		record string
+		want   string
+	}{
+		{
+			name:   "Airport",
+			record: "SUSAP KBURK2IIBUR1   010950RW08 N34115264W1182220910789N34115527W1182154266809-12260500300E01206000725                     365471903",
+			want:   "KBURK2",
+		},
+		{
+			name:   "Heliport",
+			record: "SUSAH 05CAK2AABC01   ",
+			want:   "05CAK2",
+		},
+		{
+			name:   "Enroute",
+			record: "SUSAEAENRT   BUDDE K20    R     N34115813W118172088                       E0120     NAR           BUDDE                    365441903",
+		},
+		{
+			name:   "Header",
+			record: "HDR01FAACIFP18      001P013203800762004 ",
+		},
+		{
+			name:   "TooShort",
+			record: "SUSAP KBUR",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recordAirport([]byte(tt.record)); got != tt.want {
+				t.Errorf("recordAirport(%q) = %q want %q", tt.record, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package enhance
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+	"github.com/wallaceicy06/enhance-faa-cifp/geodesy"
+)
+
+// TAA is a terminal arrival area sector whose IAF has been resolved to a
+// position.
+type TAA struct {
+	arinc.TAA
+	// IAF is the position of the IAF, or nil if it could not be found in the
+	// data.
+	IAF *geo.Point
+	// MagVar is the magnetic variation at the airport. It is used to convert
+	// true bearings to the magnetic sector bearings.
+	MagVar arinc.MagVar
+}
+
+// TAAs holds the terminal arrival area sectors of each airport, keyed by
+// airport identifier.
+type TAAs map[string][]*TAA
+
+// CollectTAAs is an option that decodes every terminal arrival area sector in
+// the data, resolves its IAF against the navaids and waypoints in the data,
+// and adds it to taas once all of the data is processed. Sectors whose IAF
+// cannot be found are included with a nil IAF.
+func CollectTAAs(taas TAAs) Option {
+	return func(p *processor) {
+		p.TAAs = taas
+	}
+}
+
+// collectTAA decodes the TAA record so that it can be resolved once all of the
+// fixes are known.
+func (p *processor) collectTAA(rec *arinc.AirportTAARecord) error {
+	if rec.ContinuationRecordNumber != "0" && rec.ContinuationRecordNumber != "1" {
+		return nil
+	}
+	t, err := rec.TAA()
+	if err != nil {
+		return fmt.Errorf("could not decode TAA sector %q of %q: %v", rec.SectorID, rec.ApproachID, err)
+	}
+	p.PendingTAAs = append(p.PendingTAAs, &TAA{TAA: *t})
+	return nil
+}
+
+// resolveTAAs resolves the IAFs of the collected TAA sectors and adds them to
+// TAAs.
+func (p *processor) resolveTAAs() {
+	if p.TAAs == nil {
+		return
+	}
+	for _, t := range p.PendingTAAs {
+		if a, ok := p.Airports[t.AirportID]; ok {
+			t.IAF = p.findFix(a, t.IAFID)
+			t.MagVar = a.MagVar
+		} else {
+			t.IAF = p.OtherWaypoints[t.IAFID]
+		}
+		if t.IAF == nil {
+			log.Printf("Could not find IAF %q for TAA of %q at airport %q", t.IAFID, t.ApproachID, t.AirportID)
+		}
+		p.TAAs[t.AirportID] = append(p.TAAs[t.AirportID], t)
+	}
+	p.PendingTAAs = nil
+}
+
+// Band returns the band of the TAA sector that the point is in. If the point
+// is outside of the sector, or the sector has no IAF, nil is returned.
+func (t *TAA) Band(pt *geo.Point) (*arinc.TAABand, error) {
+	if t.IAF == nil {
+		return nil, nil
+	}
+	dist, bearing, _, err := geodesy.WGS84.Inverse(pt.Lat(), pt.Lng(), t.IAF.Lat(), t.IAF.Lng())
+	if err != nil {
+		return nil, fmt.Errorf("could not compute bearing to TAA IAF %q: %v", t.IAFID, err)
+	}
+	brg := arinc.Bearing{Value: math.Mod(bearing+360, 360), Reference: arinc.TrueNorth}
+	if !t.BearingsAreTrue {
+		if brg, err = brg.Magnetic(t.MagVar); err != nil {
+			return nil, fmt.Errorf("could not convert bearing to TAA IAF %q: %v", t.IAFID, err)
+		}
+	}
+	return t.TAA.Band(brg.Value, dist/metersPerNauticalMile), nil
+}
+
+// Altitude returns the TAA altitude at the point for the approach at the
+// airport. The sectors of a TAA do not overlap, so the altitude is that of the
+// first band that contains the point. If the point is in none of the
+// approach's sectors, ok is false.
+func (t TAAs) Altitude(airportID, approachID string, pt *geo.Point) (_ arinc.Altitude, ok bool, _ error) {
+	for _, taa := range t[airportID] {
+		if taa.ApproachID != approachID {
+			continue
+		}
+		b, err := taa.Band(pt)
+		if err != nil {
+			return arinc.Altitude{}, false, err
+		}
+		if b != nil {
+			return b.Altitude, true, nil
+		}
+	}
+	return arinc.Altitude{}, false, nil
+}
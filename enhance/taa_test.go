@@ -0,0 +1,145 @@
+package enhance
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/wallaceicy06/enhance-faa-cifp/arinc"
+)
+
+const taaTestData = `SUSAP KHWDK2AHWD     0     056YHN37393214W122071825E015000052         1800018000C    MNAR    HAYWARD EXECUTIVE             107981608
+SUSAP KHWDK2CJOBUS K20    W     N37300471W121464565                       E0132     NAR           JOBUS                    108072002
+SUSAP KHWDK2KR28L  C      JOBUSK2PC   0 195015M00150571530060                                                              108801310
+SUSAP KHWDK2KR28L  L      JOBUSK2PC   0 015105M0030050                                                                     108811310
+SUSAP KHWDK2KR10R  C      NOFIXK2PC   0 015195M0030050                                                                     108821310`
+
+func TestCollectTAAs(t *testing.T) {
+	for _, process := range []struct {
+		name string
+		fn   func(out *bytes.Buffer, opts ...Option) error
+	}{
+		{"Process", func(out *bytes.Buffer, opts ...Option) error {
+			return Process(strings.NewReader(taaTestData), out, opts...)
+		}},
+		{"ProcessStream", func(out *bytes.Buffer, opts ...Option) error {
+			return ProcessStream(strings.NewReader(taaTestData), out, opts...)
+		}},
+	} {
+		t.Run(process.name, func(t *testing.T) {
+			taas := make(TAAs)
+			var out bytes.Buffer
+			if err := process.fn(&out, CollectTAAs(taas)); err != nil {
+				t.Fatalf("%s() = %v want <nil>", process.name, err)
+			}
+			if diff := cmp.Diff(taaTestData+"\n", out.String()); diff != "" {
+				t.Errorf("%s() out content not as expected (-want +got): %s", process.name, diff)
+			}
+			got := taas["KHWD"]
+			if len(got) != 3 {
+				t.Fatalf("%s() collected %d TAA sectors want 3", process.name, len(got))
+			}
+			for _, taa := range got[:2] {
+				if taa.IAFID != "JOBUS" || taa.IAF == nil || taa.MagVar != (arinc.MagVar{Value: -15}) {
+					t.Errorf("%s() TAA sector %q = %q at %v with variation %v want \"JOBUS\" with a position and variation 15.0E", process.name, taa.SectorID, taa.IAFID, taa.IAF, taa.MagVar)
+				}
+			}
+			if got[2].IAFID != "NOFIX" || got[2].IAF != nil {
+				t.Errorf("%s() third TAA sector = %q at %v want \"NOFIX\" with no position", process.name, got[2].IAFID, got[2].IAF)
+			}
+		})
+	}
+}
+
+func TestCollectTAAsBadRecord(t *testing.T) {
+	in := strings.NewReader("SUSAP KHWDK2KR28L  C      JOBUSK2PC   0 195015M00150571500060                                                              108801310")
+	var out bytes.Buffer
+	if err := Process(in, &out, CollectTAAs(make(TAAs))); err == nil {
+		t.Errorf("Process() = <nil> want <non-nil>")
+	}
+	// TAAs are only decoded when they are collected.
+	in.Seek(0, 0)
+	if err := Process(in, &out); err != nil {
+		t.Errorf("Process() without CollectTAAs = %v want <nil>", err)
+	}
+}
+
+func TestTAAsAltitude(t *testing.T) {
+	taas := make(TAAs)
+	var out bytes.Buffer
+	if err := Process(strings.NewReader(taaTestData), &out, CollectTAAs(taas)); err != nil {
+		t.Fatalf("Process() = %v want <nil>", err)
+	}
+	for _, tt := range []struct {
+		name       string
+		airportID  string
+		approachID string
+		pt         *geo.Point
+		want       arinc.Altitude
+		wantOK     bool
+	}{
+		{
+			// The true bearing to the IAF is 270, which is 255 magnetic.
+			name:       "StraightInInnerBand",
+			airportID:  "KHWD",
+			approachID: "R28L",
+			pt:         geo.NewPoint(37.50131, -121.569),
+			want:       arinc.Altitude{Feet: 5700},
+			wantOK:     true,
+		},
+		{
+			name:       "StraightInOuterBand",
+			airportID:  "KHWD",
+			approachID: "R28L",
+			pt:         geo.NewPoint(37.50131, -121.359),
+			want:       arinc.Altitude{Feet: 6000},
+			wantOK:     true,
+		},
+		{
+			// The true bearing to the IAF is 090, which is 075 magnetic.
+			name:       "LeftBase",
+			airportID:  "KHWD",
+			approachID: "R28L",
+			pt:         geo.NewPoint(37.50131, -121.99),
+			want:       arinc.Altitude{Feet: 5000},
+			wantOK:     true,
+		},
+		{
+			// The true bearing to the IAF is 180, which is 165 magnetic.
+			name:       "OutsideSectors",
+			airportID:  "KHWD",
+			approachID: "R28L",
+			pt:         geo.NewPoint(37.7, -121.77935),
+		},
+		{
+			name:       "BeyondOuterArc",
+			airportID:  "KHWD",
+			approachID: "R28L",
+			pt:         geo.NewPoint(37.50131, -120.9),
+		},
+		{
+			name:       "UnresolvedIAF",
+			airportID:  "KHWD",
+			approachID: "R10R",
+			pt:         geo.NewPoint(37.50131, -121.99),
+		},
+		{
+			name:       "UnknownApproach",
+			airportID:  "KHWD",
+			approachID: "R28R",
+			pt:         geo.NewPoint(37.50131, -121.569),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := taas.Altitude(tt.airportID, tt.approachID, tt.pt)
+			if err != nil {
+				t.Fatalf("Altitude() = _, _, %v want _, _, <nil>", err)
+			}
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Altitude() = %v, %t, _ want %v, %t, _", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Package geodesy solves geodesic problems on a reference ellipsoid.
+//
+// The spherical formulas used by most geographic libraries are accurate to a
+// few tenths of a percent, which is not good enough when the goal is to
+// compute a localizer course to within hundredths of a degree. The functions
+// in this package use Vincenty's formulae on an ellipsoid of revolution, which
+// are accurate to well under a millimeter on the WGS-84 ellipsoid.
+package geodesy
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	// maxIterations is the maximum number of iterations performed before
+	// giving up on a solution to the inverse problem.
+	maxIterations = 200
+	// convergence is the change in longitude on the auxiliary sphere (in
+	// radians) below which the inverse solution is considered converged. This
+	// corresponds to roughly 0.006 mm on the earth.
+	convergence = 1e-12
+)
+
+// ErrNoConvergence is returned when the inverse problem does not converge.
+// This only happens for points that are nearly antipodal.
+var ErrNoConvergence = errors.New("geodesic inverse solution did not converge")
+
+// Ellipsoid is an ellipsoid of revolution that models the shape of the earth.
+type Ellipsoid struct {
+	// A is the semi-major axis in meters.
+	A float64
+	// F is the flattening.
+	F float64
+}
+
+// WGS84 is the World Geodetic System 1984 ellipsoid used by GPS and by the
+// coordinates in ARINC 424 data.
+var WGS84 = Ellipsoid{A: 6378137, F: 1 / 298.257223563}
+
+// b returns the semi-minor axis of the ellipsoid in meters.
+func (e Ellipsoid) b() float64 {
+	return e.A * (1 - e.F)
+}
+
+// Inverse computes the length of the geodesic between two points and the
+// azimuths of the geodesic at each point. Latitudes and longitudes are in
+// decimal degrees. The distance is returned in meters. The initial azimuth
+// azi1 is the bearing from the first point towards the second, and the final
+// azimuth azi2 is the bearing of the geodesic as it arrives at the second
+// point. Both azimuths are in degrees in the range [0, 360). If the points
+// coincide, all return values are zero.
+func (e Ellipsoid) Inverse(lat1, lon1, lat2, lon2 float64) (dist, azi1, azi2 float64, err error) {
+	f := e.F
+	b := e.b()
+
+	L := radians(lon2 - lon1)
+	tanU1 := (1 - f) * math.Tan(radians(lat1))
+	cosU1 := 1 / math.Sqrt(1+tanU1*tanU1)
+	sinU1 := tanU1 * cosU1
+	tanU2 := (1 - f) * math.Tan(radians(lat2))
+	cosU2 := 1 / math.Sqrt(1+tanU2*tanU2)
+	sinU2 := tanU2 * cosU2
+
+	var sinLambda, cosLambda, sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+	lambda := L
+	converged := false
+	for i := 0; i < maxIterations; i++ {
+		sinLambda, cosLambda = math.Sincos(lambda)
+		sinSigma = math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			return 0, 0, 0, nil
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+		// On an equatorial line cosSqAlpha is zero and cos2SigmaM is unused.
+		cos2SigmaM = 0
+		if cosSqAlpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		C := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+		prev := lambda
+		lambda = L + (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-prev) < convergence {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return 0, 0, 0, ErrNoConvergence
+	}
+
+	uSq := cosSqAlpha * (e.A*e.A - b*b) / (b * b)
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+	dist = b * A * (sigma - deltaSigma)
+	azi1 = math.Atan2(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+	azi2 = math.Atan2(cosU1*sinLambda, -sinU1*cosU2+cosU1*sinU2*cosLambda)
+	return dist, normalize(degrees(azi1)), normalize(degrees(azi2)), nil
+}
+
+// InitialBearing returns the initial azimuth of the geodesic from the first
+// point to the second on the WGS-84 ellipsoid, in degrees in the range
+// [0, 360).
+func InitialBearing(lat1, lon1, lat2, lon2 float64) (float64, error) {
+	_, azi1, _, err := WGS84.Inverse(lat1, lon1, lat2, lon2)
+	return azi1, err
+}
+
+// Direct computes the point at the given distance in meters along the
+// geodesic that starts at the first point with the initial azimuth azi1 in
+// degrees. It returns the latitude and longitude of the point in decimal
+// degrees, with the longitude in the range [-180, 180), and the azimuth of
+// the geodesic at the point in degrees in the range [0, 360).
+func (e Ellipsoid) Direct(lat1, lon1, azi1, dist float64) (lat2, lon2, azi2 float64, err error) {
+	f := e.F
+	b := e.b()
+
+	sinAlpha1, cosAlpha1 := math.Sincos(radians(azi1))
+	tanU1 := (1 - f) * math.Tan(radians(lat1))
+	cosU1 := 1 / math.Sqrt(1+tanU1*tanU1)
+	sinU1 := tanU1 * cosU1
+	sigma1 := math.Atan2(tanU1, cosAlpha1)
+	sinAlpha := cosU1 * sinAlpha1
+	cosSqAlpha := 1 - sinAlpha*sinAlpha
+	uSq := cosSqAlpha * (e.A*e.A - b*b) / (b * b)
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+
+	var sinSigma, cosSigma, cos2SigmaM float64
+	sigma := dist / (b * A)
+	converged := false
+	for i := 0; i < maxIterations; i++ {
+		cos2SigmaM = math.Cos(2*sigma1 + sigma)
+		sinSigma, cosSigma = math.Sincos(sigma)
+		deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+			B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+		prev := sigma
+		sigma = dist/(b*A) + deltaSigma
+		if math.Abs(sigma-prev) < convergence {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return 0, 0, 0, ErrNoConvergence
+	}
+	cos2SigmaM = math.Cos(2*sigma1 + sigma)
+	sinSigma, cosSigma = math.Sincos(sigma)
+
+	x := sinU1*sinSigma - cosU1*cosSigma*cosAlpha1
+	phi2 := math.Atan2(sinU1*cosSigma+cosU1*sinSigma*cosAlpha1, (1-f)*math.Hypot(sinAlpha, x))
+	lambda := math.Atan2(sinSigma*sinAlpha1, cosU1*cosSigma-sinU1*sinSigma*cosAlpha1)
+	C := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+	L := lambda - (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+	lon2 = normalize(lon1+degrees(L)+180) - 180
+	return degrees(phi2), lon2, normalize(degrees(math.Atan2(sinAlpha, -x))), nil
+}
+
+// Destination returns the latitude and longitude of the point at the given
+// distance in meters from the first point along the geodesic with the initial
+// azimuth azi1 on the WGS-84 ellipsoid.
+func Destination(lat1, lon1, azi1, dist float64) (lat2, lon2 float64, err error) {
+	lat2, lon2, _, err = WGS84.Direct(lat1, lon1, azi1, dist)
+	return lat2, lon2, err
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// normalize returns the equivalent angle in the range [0, 360).
+func normalize(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
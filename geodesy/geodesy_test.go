@@ -0,0 +1,194 @@
+package geodesy
+
+import (
+	"math"
+	"testing"
+)
+
+// dms converts degrees, minutes, and seconds to decimal degrees.
+func dms(deg, min, sec float64) float64 {
+	if deg < 0 {
+		return deg - min/60 - sec/3600
+	}
+	return deg + min/60 + sec/3600
+}
+
+func TestInverse(t *testing.T) {
+	const (
+		distTolerance = 0.001
+		aziTolerance  = 1e-6
+	)
+	for _, tt := range []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		wantDist               float64
+		wantAzi1, wantAzi2     float64
+	}{
+		{
+			// Vincenty (1975), Flinders Peak to Buninyong, as published by
+			// Geoscience Australia.
+			name:     "FlindersPeakBuninyong",
+			lat1:     dms(-37, 57, 3.72030),
+			lon1:     dms(144, 25, 29.52440),
+			lat2:     dms(-37, 39, 10.15610),
+			lon2:     dms(143, 55, 35.38390),
+			wantDist: 54972.271,
+			wantAzi1: dms(306, 52, 5.37),
+			wantAzi2: dms(307, 10, 25.07),
+		},
+		{
+			// GeographicLib documentation, JFK to LHR.
+			name:     "JFKToLHR",
+			lat1:     40.6,
+			lon1:     -73.8,
+			lat2:     51.6,
+			lon2:     -0.5,
+			wantDist: 5551759.400319,
+			wantAzi1: 51.198882845580,
+			wantAzi2: 107.821776737393,
+		},
+		{
+			name:     "Meridian",
+			lat1:     0,
+			lon1:     0,
+			lat2:     1,
+			lon2:     0,
+			wantDist: 110574.388557,
+			wantAzi1: 0,
+			wantAzi2: 0,
+		},
+		{
+			name:     "Equator",
+			lat1:     0,
+			lon1:     0,
+			lat2:     0,
+			lon2:     -1,
+			wantDist: 111319.490793,
+			wantAzi1: 270,
+			wantAzi2: 270,
+		},
+		{
+			name: "Coincident",
+			lat1: 37.5,
+			lon1: -122,
+			lat2: 37.5,
+			lon2: -122,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dist, azi1, azi2, err := WGS84.Inverse(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+			if err != nil {
+				t.Fatalf("Inverse() = _, _, _, %v want _, _, _, <nil>", err)
+			}
+			if math.Abs(dist-tt.wantDist) > distTolerance {
+				t.Errorf("Inverse() dist = %.6f want %.6f", dist, tt.wantDist)
+			}
+			if math.Abs(azi1-tt.wantAzi1) > aziTolerance {
+				t.Errorf("Inverse() azi1 = %.9f want %.9f", azi1, tt.wantAzi1)
+			}
+			if math.Abs(azi2-tt.wantAzi2) > aziTolerance {
+				t.Errorf("Inverse() azi2 = %.9f want %.9f", azi2, tt.wantAzi2)
+			}
+		})
+	}
+}
+
+func TestInverseNearlyAntipodal(t *testing.T) {
+	if _, _, _, err := WGS84.Inverse(0, 0, 0.5, 179.7); err != ErrNoConvergence {
+		t.Errorf("Inverse() = _, _, _, %v want _, _, _, %v", err, ErrNoConvergence)
+	}
+}
+
+func TestInitialBearing(t *testing.T) {
+	got, err := InitialBearing(40.6, -73.8, 51.6, -0.5)
+	if err != nil {
+		t.Fatalf("InitialBearing() = _, %v want _, <nil>", err)
+	}
+	if want := 51.198882845580; math.Abs(got-want) > 1e-6 {
+		t.Errorf("InitialBearing() = %.9f want %.9f", got, want)
+	}
+}
+
+func TestDirect(t *testing.T) {
+	const (
+		posTolerance = 1e-8
+		aziTolerance = 1e-6
+	)
+	for _, tt := range []struct {
+		name             string
+		lat1, lon1, azi1 float64
+		dist             float64
+		wantLat, wantLon float64
+		wantAzi2         float64
+	}{
+		{
+			// Vincenty (1975), Flinders Peak to Buninyong, as published by
+			// Geoscience Australia.
+			name:     "FlindersPeakBuninyong",
+			lat1:     dms(-37, 57, 3.72030),
+			lon1:     dms(144, 25, 29.52440),
+			azi1:     dms(306, 52, 5.37),
+			dist:     54972.271,
+			wantLat:  dms(-37, 39, 10.15610),
+			wantLon:  dms(143, 55, 35.38390),
+			wantAzi2: dms(307, 10, 25.07),
+		},
+		{
+			name:     "Meridian",
+			azi1:     0,
+			dist:     110574.388557,
+			wantLat:  1,
+			wantAzi2: 0,
+		},
+		{
+			name:     "AcrossAntimeridian",
+			lon1:     179.5,
+			azi1:     90,
+			dist:     111319.490793,
+			wantLon:  -179.5,
+			wantAzi2: 90,
+		},
+		{
+			name:    "ZeroDistance",
+			lat1:    37.5,
+			lon1:    -122,
+			azi1:    45,
+			wantLat: 37.5,
+			wantLon: -122,
+			// The azimuth is unchanged along a geodesic of zero length.
+			wantAzi2: 45,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			lat2, lon2, azi2, err := WGS84.Direct(tt.lat1, tt.lon1, tt.azi1, tt.dist)
+			if err != nil {
+				t.Fatalf("Direct() = _, _, _, %v want _, _, _, <nil>", err)
+			}
+			// Positions are checked to about a millimeter.
+			if math.Abs(lat2-tt.wantLat) > posTolerance || math.Abs(lon2-tt.wantLon) > posTolerance {
+				t.Errorf("Direct() = %.9f, %.9f, _, _ want %.9f, %.9f, _, _", lat2, lon2, tt.wantLat, tt.wantLon)
+			}
+			if math.Abs(azi2-tt.wantAzi2) > aziTolerance {
+				t.Errorf("Direct() = _, _, %.9f, _ want _, _, %.9f, _", azi2, tt.wantAzi2)
+			}
+		})
+	}
+}
+
+func TestDestinationRoundTrip(t *testing.T) {
+	const tolerance = 0.001
+	lat1, lon1 := 37.659578, -122.122431
+	for _, azi := range []float64{0, 45, 107.9, 180, 288, 359.9} {
+		lat2, lon2, err := Destination(lat1, lon1, azi, 20000)
+		if err != nil {
+			t.Fatalf("Destination(%f) = _, _, %v want _, _, <nil>", azi, err)
+		}
+		dist, gotAzi, _, err := WGS84.Inverse(lat1, lon1, lat2, lon2)
+		if err != nil {
+			t.Fatalf("Inverse() = _, _, _, %v want _, _, _, <nil>", err)
+		}
+		if math.Abs(dist-20000) > tolerance || math.Abs(gotAzi-azi) > 1e-6 {
+			t.Errorf("Inverse(Destination(%f, 20000)) = %f, %f want 20000, %f", azi, dist, gotAzi, azi)
+		}
+	}
+}
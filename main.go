@@ -3,10 +3,13 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/wallaceicy06/enhance-faa-cifp/enhance"
 )
@@ -14,11 +17,33 @@ import (
 var (
 	removeDuplicateLocalizers = flag.Bool("remove_duplicate_locs", true, "if true, then duplicate LDA localizers are removed from the output data")
 	outFile                   = flag.String("output", "", "path of the file to output augmented procedures")
+	reportFile                = flag.String("report", "", "path of the file to write a report of every localizer to; the report is CSV if the path ends in .csv and JSON otherwise")
+	bearingEstimators         = flag.String("bearing_estimators", "faf,map,rwy", "comma separated list of estimators tried in order to compute localizer bearings: \"faf\" (final approach fix to localizer), \"map\" (missed approach point to localizer), \"rwy\" (runway threshold to opposite threshold), or \"pub\" (published bearing and magnetic variation)")
+	maxDeviation              = flag.Float64("max_deviation", 5, "largest difference in degrees tolerated between a computed localizer bearing and the published one")
+	deviationPolicy           = flag.String("deviation_policy", "keep", "what to do with localizers that exceed max_deviation: \"keep\" (write the computed bearing), \"published\" (write the published bearing), or \"drop\" (do not enhance the localizer)")
+	overridesFile             = flag.String("overrides", "", "path of a CSV file of localizer bearing overrides, with lines of the form \"airport,localizer_id,true_bearing\" or \"airport,localizer_id,SKIP\"")
+	continueOnError           = flag.Bool("continue_on_error", false, "if true, then records that cannot be processed are passed through unchanged instead of stopping")
+	maxErrors                 = flag.Int("max_errors", 0, "maximum number of records that can fail to be processed with continue_on_error before stopping, or 0 for no limit")
+	validatePathPoints        = flag.Bool("validate_path_points", false, "if true, then the FAS data block CRC of every path point is checked and path points that do not match are listed in the report")
 )
 
+var estimatorsByName = map[string]enhance.BearingEstimator{
+	"faf": enhance.FinalApproachFixEstimator{},
+	"map": enhance.MissedApproachPointEstimator{},
+	"rwy": enhance.RunwayThresholdEstimator{},
+	"pub": enhance.PublishedBearingEstimator{},
+}
+
+var deviationPoliciesByName = map[string]enhance.DeviationPolicy{
+	"keep":      enhance.DeviationKeep,
+	"published": enhance.DeviationPublished,
+	"drop":      enhance.DeviationDrop,
+}
+
 func init() {
 	flag.Usage = func() {
 		fmt.Fprintln(flag.CommandLine.Output(), "Usage: enhance_faa_cifp [options...] <cifp_file>")
+		fmt.Fprintln(flag.CommandLine.Output(), "The CIFP file may be the zip file distributed by the FAA. Use - to read from standard input.")
 		flag.PrintDefaults()
 	}
 }
@@ -28,17 +53,34 @@ func main() {
 	if len(flag.Args()) < 1 {
 		log.Fatalf("Must specify a CIFP file.")
 	}
+	var estimators []enhance.BearingEstimator
+	for _, name := range strings.Split(*bearingEstimators, ",") {
+		e, ok := estimatorsByName[strings.TrimSpace(name)]
+		if !ok {
+			log.Fatalf("Unknown bearing estimator %q.", name)
+		}
+		estimators = append(estimators, e)
+	}
+	policy, ok := deviationPoliciesByName[*deviationPolicy]
+	if !ok {
+		log.Fatalf("Unknown deviation policy %q.", *deviationPolicy)
+	}
 	cifpFile := flag.Args()[0]
 	log.Printf("CIFP file: %q", cifpFile)
 	log.Printf("CIFP output file: %q", *outFile)
 
-	inReader, err := os.Open(cifpFile)
-	if err != nil {
-		log.Fatalf("Could not open CIFP file: %v", err)
+	inReader := os.Stdin
+	if cifpFile != "-" {
+		var err error
+		inReader, err = os.Open(cifpFile)
+		if err != nil {
+			log.Fatalf("Could not open CIFP file: %v", err)
+		}
+		defer inReader.Close()
 	}
-	defer inReader.Close()
 	outWriter := os.Stdout
 	if *outFile != "" {
+		var err error
 		outWriter, err = os.Create(*outFile)
 		if err != nil {
 			log.Fatalf("Could not open output file: %v", err)
@@ -46,8 +88,91 @@ func main() {
 		defer outWriter.Close()
 	}
 
-	if err := enhance.Process(inReader, outWriter, enhance.RemoveDuplicateLocalizers(*removeDuplicateLocalizers)); err != nil {
+	opts := []enhance.Option{
+		enhance.RemoveDuplicateLocalizers(*removeDuplicateLocalizers),
+		enhance.BearingEstimators(estimators...),
+		enhance.MaxDeviation(*maxDeviation),
+		enhance.OnDeviation(policy),
+		enhance.ValidatePathPoints(*validatePathPoints),
+	}
+	if *continueOnError {
+		opts = append(opts, enhance.ContinueOnError(*maxErrors))
+	}
+	if *overridesFile != "" {
+		overrides, err := readOverrides(*overridesFile)
+		if err != nil {
+			log.Fatalf("Could not read overrides: %v", err)
+		}
+		log.Printf("Read %d overrides from %q.", len(overrides), *overridesFile)
+		opts = append(opts, enhance.BearingOverrides(overrides))
+	}
+	// Standard input can only be read once, so it is processed in a single
+	// pass. Files may also be a zip or gzip archive that contains the data.
+	report := &enhance.Report{}
+	opts = append(opts, enhance.ReportTo(report))
+	var err error
+	if cifpFile == "-" {
+		err = enhance.ProcessStream(inReader, outWriter, opts...)
+	} else {
+		var info os.FileInfo
+		if info, err = inReader.Stat(); err != nil {
+			log.Fatalf("Could not read CIFP file: %v", err)
+		}
+		err = enhance.ProcessArchive(inReader, info.Size(), outWriter, opts...)
+	}
+	var recErrs *enhance.RecordErrors
+	if errors.As(err, &recErrs) && !recErrs.Aborted {
+		// The records that could not be processed were logged and passed
+		// through unchanged, so the output is still usable.
+		log.Printf("%d records could not be processed and were passed through unchanged.", len(recErrs.Errors))
+	} else if err != nil {
+		var recErr *enhance.RecordError
+		if errors.As(err, &recErr) {
+			log.Printf("Bad record on line %d of %q:\n%s", recErr.Line, cifpFile, recErr.Record)
+		}
 		log.Fatalf("Could not process data: %v", err)
 	}
 	log.Printf("Processed data.")
+	if n := len(report.UnusedOverrides); n > 0 {
+		log.Printf("%d overrides did not match any localizer.", n)
+	}
+	if n := len(report.CorruptPathPoints); n > 0 {
+		log.Printf("%d path points failed CRC validation.", n)
+	}
+
+	if *reportFile != "" {
+		if err := writeReport(report, *reportFile); err != nil {
+			log.Fatalf("Could not write report: %v", err)
+		}
+		log.Printf("Wrote report to %q.", *reportFile)
+	}
+}
+
+// writeReport writes the report to the file at path. The report is written as
+// CSV if the file has a .csv extension, and as JSON otherwise.
+func writeReport(report *enhance.Report, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		err = report.WriteCSV(f)
+	} else {
+		err = report.WriteJSON(f)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readOverrides reads localizer bearing overrides from the CSV file at path.
+func readOverrides(path string) ([]enhance.Override, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return enhance.ReadOverrides(f)
 }